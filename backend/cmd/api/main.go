@@ -1,17 +1,35 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // Quiet hours use IANA timezones; don't depend on the host having them
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
+	"github.com/saidimuKennedy/spotlight-africa/internal/authz"
 	"github.com/saidimuKennedy/spotlight-africa/internal/controller"
 	"github.com/saidimuKennedy/spotlight-africa/internal/database"
+	"github.com/saidimuKennedy/spotlight-africa/internal/events"
+	"github.com/saidimuKennedy/spotlight-africa/internal/jobs"
+	"github.com/saidimuKennedy/spotlight-africa/internal/mail"
 	"github.com/saidimuKennedy/spotlight-africa/internal/middleware"
 	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/newsletter"
+	"github.com/saidimuKennedy/spotlight-africa/internal/oauth"
+	"github.com/saidimuKennedy/spotlight-africa/internal/realtime"
 	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+	"github.com/saidimuKennedy/spotlight-africa/internal/scoring"
+	"github.com/saidimuKennedy/spotlight-africa/internal/spam"
+	"github.com/saidimuKennedy/spotlight-africa/internal/subscribers"
+	"github.com/saidimuKennedy/spotlight-africa/internal/totp"
+	"github.com/saidimuKennedy/spotlight-africa/internal/webhooks"
 	"github.com/saidimuKennedy/spotlight-africa/internal/worker"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -39,9 +57,11 @@ func main() {
 	}
 
 	// 3. Auto-Migrate
+	database.PrepareMigrations(db)
 	db.AutoMigrate(
 		&models.User{},
 		&models.Business{},
+		&models.BusinessView{},
 		&models.BusinessBio{}, 
 		&models.Comment{},
 		&models.Like{},
@@ -56,16 +76,120 @@ func main() {
 		&models.PlatformInquiry{},
 		&models.News{},
 		&models.Blog{},
+		&models.QuarantineEntry{},
+		&models.PlatformInquiryReply{},
+		&models.CannedResponse{},
+		&models.CommentRevision{},
+		&models.CommentReport{},
+		&models.HealthSnapshot{},
+		&models.Job{},
+		&models.NotificationPreference{},
+		&models.NotificationSettings{},
+		&models.OutboxEmail{},
+		&models.EmailSuppression{},
+		&models.NewsletterIssue{},
+		&models.NewsletterDelivery{},
+		&models.NewsletterClick{},
+		&models.UserToken{},
+		&models.Session{},
+		&models.Role{},
+		&models.BusinessMember{},
+		&models.BusinessInvitation{},
+		&models.UserIdentity{},
+		&models.OAuthState{},
+		&models.TwoFactor{},
+		&models.RecoveryCode{},
+		&models.LoginAttempt{},
+		&models.LoginThrottle{},
 	)
 	database.SeedData(db)
+	database.BackfillBusinessMembers(db)
 
 	// --- WORKERS ---
 	// Start the background scraping worker
 	scraper := &worker.ScraperWorker{DB: db}
 	scraper.Start()
 
+	// Periodically rescore businesses so time decay is reflected even on quiet profiles
+	healthRepo := &repository.HealthRepository{DB: db, Engine: scoring.NewEngine(scoring.ConfigFromEnv())}
+	healthInterval, _ := time.ParseDuration(os.Getenv("HEALTH_RECOMPUTE_INTERVAL"))
+	healthWorker := &worker.HealthScoreWorker{
+		Repo:       healthRepo,
+		Businesses: &repository.BusinessRepository{DB: db},
+		Interval:   healthInterval,
+		ViewPeriod: controller.ViewDedupePeriod,
+	}
+	healthWorker.Start()
+
+	// Background job queue: health recomputes, activity logging, notifications and email
+	actRepo := &repository.ActivityRepository{DB: db}
+	notifRepo := &repository.NotificationRepository{DB: db}
+	prefRepo := &repository.NotificationPreferenceRepository{DB: db}
+	platformInqRepo := &repository.PlatformInquiryRepository{DB: db}
+	emailRepo := &repository.EmailRepository{DB: db}
+	outbox := &mail.Outbox{Repo: emailRepo, Sender: mail.NewSenderFromEnv()}
+
+	jobQueue := &jobs.Queue{DB: db}
+	jobConcurrency, _ := strconv.Atoi(os.Getenv("JOB_CONCURRENCY"))
+	jobPoll, _ := time.ParseDuration(os.Getenv("JOB_POLL_INTERVAL"))
+	jobRunner := &jobs.Runner{Queue: jobQueue, Concurrency: jobConcurrency, PollInterval: jobPoll}
+	// Live updates: each instance serves its own SSE clients; Postgres NOTIFY fans out between instances
+	hub := realtime.NewHub()
+	pgFanout := &realtime.PGBroadcaster{DB: db, DSN: dsn}
+	hub.Broadcaster = pgFanout
+	go pgFanout.Listen(context.Background(), hub)
+
+	handlers := &worker.JobHandlers{
+		ActivityRepo:     actRepo,
+		HealthRepo:       healthRepo,
+		NotificationRepo: notifRepo,
+		PreferenceRepo:   prefRepo,
+		Outbox:           outbox,
+		Realtime:         hub,
+	}
+	handlers.Register(jobRunner)
+
+	// Domain events: controllers publish, subscribers handle the side effects
+	bus := events.NewBus()
+	(&subscribers.Activity{Jobs: jobQueue}).Register(bus)
+	(&subscribers.Scoring{Jobs: jobQueue}).Register(bus)
+	userRepo := &repository.UserRepository{DB: db}
+	(&subscribers.Notifications{DB: db, Jobs: jobQueue}).Register(bus)
+	(&subscribers.Realtime{DB: db, Hub: hub}).Register(bus)
+	(&subscribers.Emails{DB: db, Outbox: outbox, Preferences: prefRepo}).Register(bus)
+	hooks := webhooks.NewDispatcherFromEnv(jobQueue)
+	hooks.Register(bus)
+	jobRunner.Handle(jobs.TypeWebhook, hooks.Deliver)
+
+	// Newsletter issues go out in batches: NEWSLETTER_BATCH_SIZE recipients every NEWSLETTER_BATCH_DELAY
+	issueRepo := &repository.NewsletterIssueRepository{DB: db}
+	batchSize, _ := strconv.Atoi(os.Getenv("NEWSLETTER_BATCH_SIZE"))
+	batchDelay, _ := time.ParseDuration(os.Getenv("NEWSLETTER_BATCH_DELAY"))
+	campaigns := &newsletter.Campaigns{Repo: issueRepo, Outbox: outbox, Jobs: jobQueue, BatchSize: batchSize, BatchDelay: batchDelay}
+	jobRunner.Handle(jobs.TypeNewsletterSend, campaigns.Send)
+
+	jobRunner.Start()
+
+	// Email digests of unread notifications
+	digestInterval, _ := time.ParseDuration(os.Getenv("DIGEST_INTERVAL"))
+	digestWorker := &worker.DigestWorker{Repo: prefRepo, Jobs: jobQueue, Interval: digestInterval}
+	digestWorker.Start()
+
 	// 4. Initialize Gin Router
-	r := gin.Default()
+	// gin.Default() without its logger, which would write the stream's ?token= to the logs
+	r := gin.New()
+	r.Use(middleware.Logger(), gin.Recovery())
+	// Only believe X-Forwarded-For from our own proxies (TRUSTED_PROXIES, comma-separated IPs
+	// or CIDRs). Otherwise clients could pick their own IP and dodge every per-IP rate limit.
+	var trustedProxies []string
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			trustedProxies = append(trustedProxies, p)
+		}
+	}
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
 	
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173", "http://localhost:5174", "http://localhost:3000"},
@@ -78,31 +202,123 @@ func main() {
 	// Initialize Repository and Controller
 	bizRepo := &repository.BusinessRepository{DB: db}
 	interRepo := &repository.InteractionRepository{DB: db}
-	actRepo := &repository.ActivityRepository{DB: db}
 	meetRepo := &repository.MeetingRepository{DB: db}
+	modRepo := &repository.ModerationRepository{DB: db}
+	memberRepo := &repository.BusinessMemberRepository{DB: db}
+	// Permissions per role, cached briefly; editing a role applies within seconds
+	roleRepo := &repository.RoleRepository{DB: db}
+	policy := &authz.Policy{Load: roleRepo.Permissions}
+	middleware.RoleGrants = policy.Grants
 
 	bizCtrl := &controller.BusinessController{
-		Repo:         bizRepo,
-		ActivityRepo: actRepo,
+		Repo:       bizRepo,
+		HealthRepo: healthRepo,
+		Members:    memberRepo,
+		Users:      userRepo,
+		Events:     bus,
 	}
 
+	commentDepth, _ := strconv.Atoi(os.Getenv("COMMENT_MAX_DEPTH"))
 	interCtrl := &controller.InteractionController{
-		Repo:         interRepo,
-		ActivityRepo: actRepo,
+		Repo:            interRepo,
+		Members:         memberRepo,
+		Events:          bus,
+		ModerationRepo:  modRepo,
+		Spam:            spam.NewAnalyzerFromEnv(),
+		Captcha:         spam.NewVerifierFromEnv(),
+		MaxCommentDepth: commentDepth,
 	}
 
+	modCtrl := &controller.ModerationController{Repo: modRepo, InterRepo: interRepo, Events: bus}
+	platformInqCtrl := &controller.PlatformInquiryController{Repo: platformInqRepo, Users: userRepo, Policy: policy, Outbox: outbox}
+	jobCtrl := &controller.JobController{Queue: jobQueue}
+	rtCtrl := &controller.RealtimeController{Hub: hub}
+	meetCtrl := &controller.MeetingController{Repo: meetRepo, BizRepo: bizRepo, Members: memberRepo, Events: bus}
+	newsletterCtrl := &controller.NewsletterController{Repo: &repository.NewsletterRepository{DB: db}, Outbox: outbox, Events: bus}
+	issueCtrl := &controller.NewsletterIssueController{Repo: issueRepo, Campaigns: campaigns}
+	emailCtrl := &controller.EmailController{Repo: emailRepo, WebhookSecret: os.Getenv("EMAIL_WEBHOOK_SECRET")}
+
 	// Initialize Auth Controller
-	authCtrl := &controller.AuthController{DB: db}
+	sessionRepo := &repository.SessionRepository{DB: db}
+	tokenRepo := &repository.UserTokenRepository{DB: db}
+	// Roles that must sign in with an authenticator app (TWO_FACTOR_ROLES, comma-separated; default admin)
+	twoFactorRoles := os.Getenv("TWO_FACTOR_ROLES")
+	if twoFactorRoles == "" {
+		twoFactorRoles = "admin"
+	}
+	requireTwoFactor := func(role string) bool {
+		for _, r := range strings.Split(twoFactorRoles, ",") {
+			if strings.TrimSpace(r) == role {
+				return true
+			}
+		}
+		return false
+	}
+	// Failed sign-ins back off per account and per IP address, and lock the account after many
+	loginAttemptRepo := &repository.LoginAttemptRepository{DB: db}
+	loginGuard := &controller.LoginGuard{
+		Attempts: loginAttemptRepo,
+		Tokens:   tokenRepo,
+		Outbox:   outbox,
+	}
+	// Login attempts are kept for LOGIN_ATTEMPT_RETENTION (default 90 days)
+	loginRetention, _ := time.ParseDuration(os.Getenv("LOGIN_ATTEMPT_RETENTION"))
+	(&worker.LoginRetentionWorker{Repo: loginAttemptRepo, Retention: loginRetention}).Start()
+	twoFactorCtrl := &controller.TwoFactorController{
+		Repo:     &repository.TwoFactorRepository{DB: db, Key: totp.KeyFromEnv()},
+		Users:    userRepo,
+		Tokens:   tokenRepo,
+		Sessions: sessionRepo,
+		Guard:    loginGuard,
+		Required: requireTwoFactor,
+	}
+	middleware.TwoFactorRequired = requireTwoFactor
+	authCtrl := &controller.AuthController{
+		DB:       db,
+		Events:   bus,
+		Users:    userRepo,
+		Tokens:    tokenRepo,
+		Sessions:  sessionRepo,
+		Outbox:    outbox,
+		TwoFactor: twoFactorCtrl,
+		Guard:     loginGuard,
+	}
+	// Sign-in with Google, LinkedIn and GitHub; each is enabled by setting its client ID
+	oauthCtrl := &controller.OAuthController{
+		Providers:  oauth.ProvidersFromEnv(mail.Link("/auth/callback")),
+		Identities: &repository.IdentityRepository{DB: db},
+		Users:      userRepo,
+		Sessions:   sessionRepo,
+		TwoFactor:  twoFactorCtrl,
+		Guard:      loginGuard,
+		Events:     bus,
+	}
+	// Access tokens are checked against their session, so revoking it logs the device out
+	middleware.SessionActive = sessionRepo.IsActive
+	// Roles are looked up per request (cached briefly), so role changes don't wait for tokens to expire
+	middleware.CurrentRole = func(userID uuid.UUID) (string, int, error) {
+		state, err := userRepo.AuthState(userID)
+		if err != nil {
+			return "", 0, err
+		}
+		return state.Role, state.TokenVersion, nil
+	}
+	userCtrl := &controller.UserController{Users: userRepo, Roles: roleRepo}
+	roleCtrl := &controller.RoleController{Repo: roleRepo, Policy: policy}
+	memberCtrl := &controller.BusinessMemberController{Repo: memberRepo, BizRepo: bizRepo, Users: userRepo, Outbox: outbox}
 	
 	dashCtrl := &controller.DashboardController{
-		BizRepo:      bizRepo,
-		InterRepo:    interRepo,
-		ActivityRepo: actRepo,
-		MeetingRepo:  meetRepo,
+		BizRepo:             bizRepo,
+		InterRepo:           interRepo,
+		ActivityRepo:        actRepo,
+		MeetingRepo:         meetRepo,
+		PlatformInquiryRepo: platformInqRepo,
+		HealthRepo:          healthRepo,
+		Members:             memberRepo,
+		Users:               userRepo,
 	}
 
-	notifRepo := &repository.NotificationRepository{DB: db}
-	notifCtrl := &controller.NotificationController{Repo: notifRepo}
+	notifCtrl := &controller.NotificationController{Repo: notifRepo, PrefRepo: prefRepo}
 
 	postRepo := &repository.PostRepository{DB: db}
 	postCtrl := &controller.PostController{Repo: postRepo}
@@ -118,16 +334,37 @@ func main() {
 	// --- PUBLIC ROUTES ---
 	r.POST("/login", authCtrl.Login)
 	r.POST("/register", authCtrl.Register)
+	r.POST("/auth/forgot-password", middleware.RateLimit(5, time.Hour, middleware.ByIP), authCtrl.ForgotPassword)
+	r.POST("/auth/reset-password", middleware.RateLimit(10, time.Hour, middleware.ByIP), authCtrl.ResetPassword)
+	r.POST("/auth/verify-email", authCtrl.VerifyEmail)
+	r.POST("/auth/refresh", middleware.RateLimit(60, time.Hour, middleware.ByIP), authCtrl.Refresh)
+	r.POST("/auth/2fa/verify", middleware.RateLimit(20, 15*time.Minute, middleware.ByIP), twoFactorCtrl.Verify)
+	r.POST("/auth/2fa/setup", middleware.RateLimit(20, 15*time.Minute, middleware.ByIP), twoFactorCtrl.Setup)
+	r.POST("/auth/2fa/setup/confirm", middleware.RateLimit(20, 15*time.Minute, middleware.ByIP), twoFactorCtrl.ConfirmSetup)
+	r.GET("/auth/providers", oauthCtrl.ListProviders)
+	r.GET("/auth/oauth/:provider", middleware.RateLimit(30, time.Hour, middleware.ByIP), oauthCtrl.StartSignIn)
+	r.POST("/auth/oauth/:provider/callback", middleware.RateLimit(30, time.Hour, middleware.ByIP), oauthCtrl.Callback)
 	
 	r.GET("/businesses", bizCtrl.GetAllBusinesses)
 	
 	r.GET("/businesses/:id", bizCtrl.GetBusiness)
-	r.POST("/businesses/:id/view", bizCtrl.TrackView)
+	r.GET("/businesses/:id/health", bizCtrl.GetHealth)
+	r.GET("/businesses/:id/health/history", bizCtrl.GetHealthHistory)
+	// Views count once per viewer per day; the limit stops one address sweeping every profile
+	r.POST("/businesses/:id/view", middleware.OptionalAuthorize(), middleware.RateLimit(60, time.Minute, middleware.ByIP), bizCtrl.TrackView)
 	r.POST("/businesses/:id/conversion", bizCtrl.TrackConversion)
 	r.GET("/businesses/:id/comments", interCtrl.GetComments)
+	r.GET("/comments/:id/history", middleware.OptionalAuthorize(), interCtrl.GetCommentHistory)
 	r.GET("/network/feed", interCtrl.GetNetworkFeed)
-	r.POST("/newsletter/subscribe", interCtrl.SubscribeNewsletter)
-	r.POST("/platform-inquiries", interCtrl.SubmitPlatformInquiry)
+	r.POST("/newsletter/subscribe", middleware.RateLimit(5, time.Hour, middleware.ByIP), newsletterCtrl.Subscribe)
+	r.POST("/newsletter/confirm", newsletterCtrl.Confirm)
+	r.GET("/newsletter/preferences", newsletterCtrl.GetPreferences)
+	r.PUT("/newsletter/preferences", newsletterCtrl.UpdatePreferences)
+	r.GET("/newsletter/unsubscribe", newsletterCtrl.UnsubscribePage)
+	r.POST("/newsletter/unsubscribe", newsletterCtrl.Unsubscribe)
+	r.GET("/newsletter/o/:token", issueCtrl.TrackOpen)
+	r.GET("/newsletter/c/:token", issueCtrl.TrackClick)
+	r.POST("/platform-inquiries", middleware.RateLimit(5, time.Hour, middleware.ByIP), interCtrl.SubmitPlatformInquiry)
 	r.GET("/events", bizCtrl.GetEvents)
 	r.GET("/posts", postCtrl.GetPosts)
 	r.GET("/posts/:slug", postCtrl.GetPost)
@@ -135,39 +372,150 @@ func main() {
 	r.GET("/news/:slug", newsCtrl.GetNewsArticle)
 	r.GET("/blogs", blogCtrl.GetBlogs)
 	r.GET("/blogs/:slug", blogCtrl.GetBlog)
+	r.GET("/invitations/:token", memberCtrl.GetInvitation)
+
+	// Bounce and complaint reports from the mail provider, authenticated by EMAIL_WEBHOOK_SECRET
+	r.POST("/webhooks/email", emailCtrl.HandleWebhook)
 	
-	//
+	// EventSource can't send headers, so the stream also accepts ?token=
+	r.GET("/stream", middleware.TokenFromQuery(), middleware.Authorize(), rtCtrl.Stream)
+
+	// --- SIGNED-IN ROUTES ---
+	// Open to every role. Handlers for a business check team membership with authz.CanAccess.
 	userGroup := r.Group("/")
-	userGroup.Use(middleware.Authorize("admin", "privileged", "viewer", "owner"))
+	userGroup.Use(middleware.Authorize())
 	{
-		userGroup.POST("/businesses/:id/like", interCtrl.LikeBusiness)
-		userGroup.POST("/businesses/:id/comment", interCtrl.AddComment)
-		userGroup.POST("/businesses/:id/inquiry", interCtrl.SubmitInquiry)
-		userGroup.POST("/network/chat", interCtrl.SendChatMessage)
+		userGroup.DELETE("/businesses/:id/like", interCtrl.RemoveReaction)
+		userGroup.DELETE("/businesses/:id/reaction", interCtrl.RemoveReaction)
+		userGroup.GET("/me/likes", interCtrl.GetMyLikes)
+		userGroup.DELETE("/comments/:id", interCtrl.DeleteComment)
+		userGroup.PATCH("/comments/:id/visibility", interCtrl.SetCommentVisibility)
+		userGroup.PATCH("/comments/:id/pin", interCtrl.SetCommentPinned)
 		userGroup.GET("/dashboard/me", dashCtrl.GetDashboardMe)
-		userGroup.POST("/businesses", bizCtrl.CreateBusiness)
+		userGroup.GET("/dashboard/businesses", dashCtrl.GetPortfolio)
+		userGroup.GET("/dashboard/businesses/:id", dashCtrl.GetBusinessDashboard)
+		userGroup.POST("/businesses", middleware.RateLimit(5, time.Hour, middleware.ByUser), bizCtrl.CreateBusiness)
+		userGroup.PUT("/businesses/:id", bizCtrl.UpdateBusiness)
+		userGroup.DELETE("/businesses/:id", bizCtrl.DeleteBusiness)
+		userGroup.GET("/businesses/:id/members", memberCtrl.ListMembers)
+		userGroup.PATCH("/businesses/:id/members/:userId", memberCtrl.UpdateMember)
+		userGroup.DELETE("/businesses/:id/members/:userId", memberCtrl.RemoveMember)
+		userGroup.POST("/businesses/:id/transfer", memberCtrl.TransferOwnership)
+		userGroup.GET("/businesses/:id/invitations", memberCtrl.ListInvitations)
+		userGroup.POST("/businesses/:id/invitations", middleware.RateLimit(20, time.Hour, middleware.ByUser), memberCtrl.Invite)
+		userGroup.DELETE("/businesses/:id/invitations/:invitationId", memberCtrl.RevokeInvitation)
+		userGroup.POST("/invitations/accept", memberCtrl.AcceptInvitation)
 		userGroup.PATCH("/inquiries/:id/status", interCtrl.UpdateInquiryStatus)
+		userGroup.PATCH("/meetings/:id/status", meetCtrl.UpdateMeetingStatus)
 		userGroup.GET("/notifications", notifCtrl.GetUserNotifications)
+		userGroup.GET("/notifications/unread-count", notifCtrl.GetUnreadCount)
 		userGroup.PATCH("/notifications/:id/read", notifCtrl.MarkRead)
+		userGroup.PATCH("/notifications/:id/archive", notifCtrl.ArchiveNotification)
+		userGroup.DELETE("/notifications/:id", notifCtrl.DeleteNotification)
 		userGroup.PATCH("/notifications/read-all", notifCtrl.MarkAllRead)
+		userGroup.POST("/auth/resend-verification", middleware.RateLimit(3, time.Hour, middleware.ByUser), authCtrl.ResendVerification)
+		userGroup.POST("/auth/logout", authCtrl.Logout)
+		userGroup.POST("/auth/logout-all", authCtrl.LogoutAll)
+		userGroup.GET("/me/sessions", authCtrl.ListSessions)
+		userGroup.DELETE("/me/sessions/:id", authCtrl.RevokeSession)
+		userGroup.GET("/me/2fa", twoFactorCtrl.GetStatus)
+		userGroup.POST("/me/2fa/setup", twoFactorCtrl.BeginSetup)
+		userGroup.POST("/me/2fa/enable", middleware.RateLimit(10, 15*time.Minute, middleware.ByUser), twoFactorCtrl.Enable)
+		userGroup.POST("/me/2fa/disable", middleware.RateLimit(10, 15*time.Minute, middleware.ByUser), twoFactorCtrl.Disable)
+		userGroup.POST("/me/2fa/recovery-codes", middleware.RateLimit(10, 15*time.Minute, middleware.ByUser), twoFactorCtrl.RegenerateRecoveryCodes)
+		userGroup.GET("/me/identities", oauthCtrl.ListIdentities)
+		userGroup.POST("/me/identities/:provider", oauthCtrl.StartLink)
+		userGroup.POST("/me/identities/:provider/callback", middleware.RateLimit(30, time.Hour, middleware.ByUser), oauthCtrl.FinishLink)
+		userGroup.DELETE("/me/identities/:provider", oauthCtrl.Unlink)
+		userGroup.GET("/me/notification-preferences", notifCtrl.GetPreferences)
+		userGroup.PUT("/me/notification-preferences", notifCtrl.UpdatePreferences)
 	}
 
-	// --- ADMIN ROUTES ---
-	adminRoutes := r.Group("/")
-	adminRoutes.Use(middleware.Authorize("admin"))
+	// Routes that publish content are closed to banned users.
+	postingGroup := userGroup.Group("/")
+	postingGroup.Use(middleware.RejectBanned(modRepo.IsBanned))
+	// REQUIRE_VERIFIED_EMAIL=true also closes them to accounts that haven't verified their email.
+	if os.Getenv("REQUIRE_VERIFIED_EMAIL") == "true" {
+		postingGroup.Use(middleware.RequireVerifiedEmail(userRepo.IsEmailVerified))
+	}
 	{
-		adminRoutes.PUT("/businesses/:id", bizCtrl.UpdateBusiness)
-		adminRoutes.DELETE("/businesses/:id", bizCtrl.DeleteBusiness)
-		adminRoutes.POST("/blogs", blogCtrl.CreateBlog)
-		adminRoutes.PUT("/blogs/:slug", blogCtrl.UpdateBlog)
-		adminRoutes.DELETE("/blogs/:slug", blogCtrl.DeleteBlog)
+		postingGroup.POST("/businesses/:id/like", interCtrl.LikeBusiness)
+		postingGroup.PUT("/businesses/:id/reaction", interCtrl.SetReaction)
+		// Posting and editing share one budget, so edits can't be used to post more
+		commentLimit := middleware.RateLimit(10, 10*time.Minute, middleware.ByUser, middleware.ByIP)
+		postingGroup.POST("/businesses/:id/comment", commentLimit, interCtrl.AddComment)
+		postingGroup.PATCH("/comments/:id", commentLimit, interCtrl.EditComment)
+		postingGroup.POST("/comments/:id/report", middleware.RateLimit(20, time.Hour, middleware.ByUser), interCtrl.ReportComment)
+		postingGroup.POST("/businesses/:id/inquiry", middleware.RateLimit(5, time.Hour, middleware.ByUser, middleware.ByIP), interCtrl.SubmitInquiry)
+		postingGroup.POST("/businesses/:id/meetings", middleware.RateLimit(10, time.Hour, middleware.ByUser), meetCtrl.BookMeeting)
+		postingGroup.POST("/network/chat", interCtrl.SendChatMessage)
 	}
 
-	// --- PRIVILEGED ROUTES ---
-	privilegedRoutes := r.Group("/")
-	privilegedRoutes.Use(middleware.Authorize("admin", "privileged"))
+	// --- STAFF ROUTES ---
+	// Each route requires a permission (see internal/authz); roles map to permissions in
+	// /admin/roles and admins hold them all.
+	staffRoutes := r.Group("/")
+	staffRoutes.Use(middleware.Authorize())
 	{
-		privilegedRoutes.GET("/stats", bizCtrl.GetStats)
+		staffRoutes.POST("/admin/health/recompute", middleware.Require(authz.HealthManage), bizCtrl.RecalculateAllHealth)
+		staffRoutes.GET("/stats", middleware.Require(authz.StatsView), bizCtrl.GetStats)
+
+		manageJobs := middleware.Require(authz.JobsManage)
+		staffRoutes.GET("/admin/jobs", manageJobs, jobCtrl.ListJobs)
+		staffRoutes.POST("/admin/jobs/:id/retry", manageJobs, jobCtrl.RetryJob)
+
+		manageEmail := middleware.Require(authz.EmailManage)
+		staffRoutes.GET("/admin/email/outbox", manageEmail, emailCtrl.ListOutbox)
+		staffRoutes.GET("/admin/email/suppressions", manageEmail, emailCtrl.ListSuppressions)
+		staffRoutes.POST("/admin/email/suppressions", manageEmail, emailCtrl.AddSuppression)
+		staffRoutes.DELETE("/admin/email/suppressions/:email", manageEmail, emailCtrl.RemoveSuppression)
+
+		manageNewsletter := middleware.Require(authz.NewsletterManage)
+		staffRoutes.POST("/admin/newsletter/issues", manageNewsletter, issueCtrl.ComposeIssue)
+		staffRoutes.GET("/admin/newsletter/issues", manageNewsletter, issueCtrl.ListIssues)
+		staffRoutes.GET("/admin/newsletter/issues/:id", manageNewsletter, issueCtrl.GetIssue)
+		staffRoutes.PUT("/admin/newsletter/issues/:id", manageNewsletter, issueCtrl.UpdateIssue)
+		staffRoutes.DELETE("/admin/newsletter/issues/:id", manageNewsletter, issueCtrl.DeleteIssue)
+		staffRoutes.GET("/admin/newsletter/issues/:id/preview", manageNewsletter, issueCtrl.PreviewIssue)
+		staffRoutes.POST("/admin/newsletter/issues/:id/test", manageNewsletter, issueCtrl.SendTestIssue)
+		staffRoutes.POST("/admin/newsletter/issues/:id/schedule", manageNewsletter, issueCtrl.ScheduleIssue)
+		staffRoutes.POST("/admin/newsletter/issues/:id/cancel", manageNewsletter, issueCtrl.CancelIssue)
+		staffRoutes.GET("/admin/newsletter/issues/:id/stats", manageNewsletter, issueCtrl.GetIssueStats)
+
+		publishBlog := middleware.Require(authz.BlogPublish)
+		staffRoutes.POST("/blogs", publishBlog, blogCtrl.CreateBlog)
+		staffRoutes.PUT("/blogs/:slug", publishBlog, blogCtrl.UpdateBlog)
+		staffRoutes.DELETE("/blogs/:slug", publishBlog, blogCtrl.DeleteBlog)
+
+		moderate := middleware.Require(authz.ModerationManage)
+		staffRoutes.GET("/admin/quarantine", moderate, modCtrl.ListQuarantine)
+		staffRoutes.POST("/admin/quarantine/:id/approve", moderate, modCtrl.ApproveQuarantine)
+		staffRoutes.POST("/admin/quarantine/:id/reject", moderate, modCtrl.RejectQuarantine)
+		staffRoutes.GET("/admin/moderation/reports", moderate, modCtrl.ListReportedComments)
+		staffRoutes.GET("/admin/moderation/comments/:id/reports", moderate, modCtrl.GetCommentReports)
+		staffRoutes.POST("/admin/moderation/bulk", moderate, modCtrl.BulkModerate)
+		staffRoutes.POST("/admin/users/:id/ban", moderate, modCtrl.BanUser)
+		staffRoutes.DELETE("/admin/users/:id/ban", moderate, modCtrl.UnbanUser)
+
+		staffRoutes.PATCH("/admin/users/:id/role", middleware.Require(authz.UserManage), userCtrl.ChangeRole)
+		staffRoutes.GET("/admin/login-attempts", middleware.Require(authz.UserManage), loginGuard.ListAttempts)
+
+		manageRoles := middleware.Require(authz.RoleManage)
+		staffRoutes.GET("/admin/roles", manageRoles, roleCtrl.ListRoles)
+		staffRoutes.POST("/admin/roles", manageRoles, roleCtrl.CreateRole)
+		staffRoutes.PUT("/admin/roles/:name", manageRoles, roleCtrl.UpdateRole)
+		staffRoutes.DELETE("/admin/roles/:name", manageRoles, roleCtrl.DeleteRole)
+
+		managePlatformInquiries := middleware.Require(authz.PlatformInquiryManage)
+		staffRoutes.GET("/admin/platform-inquiries", managePlatformInquiries, platformInqCtrl.ListInquiries)
+		staffRoutes.GET("/admin/platform-inquiries/:id", managePlatformInquiries, platformInqCtrl.GetInquiry)
+		staffRoutes.PATCH("/admin/platform-inquiries/:id/assign", managePlatformInquiries, platformInqCtrl.AssignInquiry)
+		staffRoutes.PATCH("/admin/platform-inquiries/:id/status", managePlatformInquiries, platformInqCtrl.UpdateInquiryStatus)
+		staffRoutes.POST("/admin/platform-inquiries/:id/replies", managePlatformInquiries, platformInqCtrl.ReplyToInquiry)
+		staffRoutes.GET("/admin/canned-responses", managePlatformInquiries, platformInqCtrl.GetCannedResponses)
+		staffRoutes.POST("/admin/canned-responses", managePlatformInquiries, platformInqCtrl.CreateCannedResponse)
+		staffRoutes.PUT("/admin/canned-responses/:id", managePlatformInquiries, platformInqCtrl.UpdateCannedResponse)
+		staffRoutes.DELETE("/admin/canned-responses/:id", managePlatformInquiries, platformInqCtrl.DeleteCannedResponse)
 	}
 
 	port := os.Getenv("PORT")
@@ -0,0 +1,191 @@
+// Package authz maps roles to permissions and answers "may this user do X (to this thing)?".
+//
+// A role grants a list of permissions. Each grant is one of:
+//
+//	"business:update"      the permission on any business
+//	"business:update:own"  the permission only on businesses the user owns
+//	"*"                    every permission (the admin role)
+//
+// Team members also get permissions on a business from their role in its team
+// (MemberPermissions), whatever their platform role.
+//
+// Platform-wide routes check the permission with middleware.Require; handlers for a
+// particular business check it with CanAccess.
+package authz
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Permission names an action on a kind of resource.
+type Permission string
+
+const (
+	BusinessUpdate        Permission = "business:update"
+	BusinessDelete        Permission = "business:delete"
+	HealthManage          Permission = "health:manage"
+	MembersManage         Permission = "business:members"
+	InquiryManage         Permission = "inquiry:manage"
+	MeetingManage         Permission = "meeting:manage"
+	CommentModerate       Permission = "comment:moderate"
+	BlogPublish           Permission = "blog:publish"
+	NewsletterManage      Permission = "newsletter:manage"
+	EmailManage           Permission = "email:manage"
+	JobsManage            Permission = "jobs:manage"
+	ModerationManage      Permission = "moderation:manage"
+	PlatformInquiryManage Permission = "platform_inquiry:manage"
+	UserManage            Permission = "user:manage"
+	RoleManage            Permission = "role:manage"
+	StatsView             Permission = "stats:view"
+)
+
+// PermissionInfo describes a permission for the admin API.
+type PermissionInfo struct {
+	Name        Permission `json:"name"`
+	Description string     `json:"description"`
+	Ownable     bool       `json:"ownable"` // Can be granted for own resources only (":own")
+}
+
+// Catalog lists every permission a role can be granted.
+var Catalog = []PermissionInfo{
+	{BusinessUpdate, "Edit business profiles", true},
+	{BusinessDelete, "Delete businesses", true},
+	{MembersManage, "Invite, change and remove business team members", true},
+	{HealthManage, "Recompute health scores", false},
+	{InquiryManage, "Update the status of business inquiries", true},
+	{MeetingManage, "Complete or cancel meetings", true},
+	{CommentModerate, "Hide and pin comments", true},
+	{BlogPublish, "Write, edit and delete blog posts", false},
+	{NewsletterManage, "Compose and send newsletter issues", false},
+	{EmailManage, "View the email outbox and manage suppressions", false},
+	{JobsManage, "View and retry background jobs", false},
+	{ModerationManage, "Review reports and quarantine, and ban users", false},
+	{PlatformInquiryManage, "Handle inquiries sent to the platform", false},
+	{UserManage, "Change users' roles", false},
+	{RoleManage, "Create and edit roles", false},
+	{StatsView, "View platform statistics", false},
+}
+
+// All grants every permission.
+const All = "*"
+
+const ownSuffix = ":own"
+
+// ValidGrant reports whether g can be stored on a role.
+func ValidGrant(g string) bool {
+	if g == All {
+		return true
+	}
+	name, own := strings.CutSuffix(g, ownSuffix)
+	for _, p := range Catalog {
+		if string(p.Name) == name {
+			return !own || p.Ownable
+		}
+	}
+	return false
+}
+
+// Grants is the set of permissions a role holds.
+type Grants []string
+
+// Has reports whether the grants include perm in any scope.
+func (g Grants) Has(perm Permission) bool {
+	return g.Allows(perm, true)
+}
+
+// Allows reports whether the grants cover perm on a resource. owned says whether the
+// user owns that resource; ":own" grants only count when they do.
+func (g Grants) Allows(perm Permission, owned bool) bool {
+	for _, grant := range g {
+		if grant == All || grant == string(perm) {
+			return true
+		}
+		if owned && grant == string(perm)+ownSuffix {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers reports whether the grants include every grant in other, in at least the same
+// scope. Only "*" covers "*".
+func (g Grants) Covers(other []string) bool {
+	for _, grant := range other {
+		if grant == All {
+			if !slices.Contains(g, All) {
+				return false
+			}
+			continue
+		}
+		name, own := strings.CutSuffix(grant, ownSuffix)
+		if !g.Allows(Permission(name), own) {
+			return false
+		}
+	}
+	return true
+}
+
+// MemberPermissions lists what each business member role may do on that business.
+var MemberPermissions = map[string][]Permission{
+	"owner":   {BusinessUpdate, BusinessDelete, MembersManage, InquiryManage, MeetingManage, CommentModerate},
+	"manager": {BusinessUpdate, MembersManage, InquiryManage, MeetingManage, CommentModerate},
+	"editor":  {BusinessUpdate, CommentModerate},
+	"viewer":  {},
+}
+
+// MemberRoleAllows reports whether a member role includes perm.
+func MemberRoleAllows(memberRole string, perm Permission) bool {
+	for _, p := range MemberPermissions[memberRole] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// MemberRolesWith lists the member roles that include perm.
+func MemberRolesWith(perm Permission) []string {
+	var roles []string
+	for role := range MemberPermissions {
+		if MemberRoleAllows(role, perm) {
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// ContextKey is where middleware.Authorize stores the user's Grants.
+const ContextKey = "permissions"
+
+// FromContext returns the grants of the user making the request.
+func FromContext(c *gin.Context) Grants {
+	if val, ok := c.Get(ContextKey); ok {
+		if g, ok := val.(Grants); ok {
+			return g
+		}
+	}
+	return nil
+}
+
+// Can reports whether the user may use perm on any resource, not just their own.
+func Can(c *gin.Context, perm Permission) bool {
+	return FromContext(c).Allows(perm, false)
+}
+
+// CanAccess reports whether the user may use perm on a business where they hold
+// memberRole ("" if they aren't on its team).
+func CanAccess(c *gin.Context, perm Permission, memberRole string) bool {
+	grants := FromContext(c)
+	if grants.Allows(perm, false) {
+		return true
+	}
+	if MemberRoleAllows(memberRole, perm) {
+		return true
+	}
+	return memberRole == "owner" && grants.Allows(perm, true)
+}
@@ -0,0 +1,55 @@
+package authz
+
+import (
+	"sync"
+	"time"
+)
+
+// Policy resolves a role to its grants. Roles live in the database, so lookups are
+// cached for TTL; Forget drops a role as soon as this instance changes it.
+type Policy struct {
+	Load func(role string) ([]string, error)
+	TTL  time.Duration // Default 30s
+
+	mu    sync.Mutex
+	cache map[string]cachedGrants
+}
+
+type cachedGrants struct {
+	grants  Grants
+	expires time.Time
+}
+
+// Grants returns the permissions of role.
+func (p *Policy) Grants(role string) (Grants, error) {
+	p.mu.Lock()
+	cached, ok := p.cache[role]
+	p.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.grants, nil
+	}
+
+	perms, err := p.Load(role)
+	if err != nil {
+		return nil, err
+	}
+	ttl := p.TTL
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cache == nil {
+		p.cache = map[string]cachedGrants{}
+	}
+	p.cache[role] = cachedGrants{grants: perms, expires: time.Now().Add(ttl)}
+	return perms, nil
+}
+
+// Forget drops the cached grants of role.
+func (p *Policy) Forget(role string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.cache, role)
+}
@@ -1,19 +1,43 @@
 package controller
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/events"
+	"github.com/saidimuKennedy/spotlight-africa/internal/mail"
 	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
 	"github.com/saidimuKennedy/spotlight-africa/internal/utils"
 	"gorm.io/gorm"
 )
 
 type AuthController struct {
-	DB *gorm.DB
+	DB        *gorm.DB
+	Events    *events.Bus
+	Users     *repository.UserRepository
+	Tokens    *repository.UserTokenRepository
+	Sessions  *repository.SessionRepository
+	Outbox    *mail.Outbox
+	TwoFactor *TwoFactorController
+	Guard     *LoginGuard
 }
 
+// Lifetimes of the links emailed for account flows.
+const (
+	passwordResetTTL     = time.Hour
+	emailVerificationTTL = 48 * time.Hour
+)
+
+// refreshTokenTTL is how long a device stays signed in without being used.
+// Every refresh extends it.
+const refreshTokenTTL = 30 * 24 * time.Hour
+
 // LoginRequest defines the structure for incoming credentials
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
@@ -24,6 +48,8 @@ type LoginRequest struct {
 type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
+	Name     string `json:"name" binding:"max=100"`
+	Locale   string `json:"locale" binding:"omitempty,oneof=en fr"` // Language for emails; defaults to en
 }
 
 func (ctrl *AuthController) Login(c *gin.Context) {
@@ -34,34 +60,187 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 		return
 	}
 
-	// 2. Find User in DB
+	// 2. Refuse while the account or this IP address is backing off after failed attempts
+	if !ctrl.Guard.Check(c, input.Email, "password") {
+		return
+	}
+
+	// 3. Find User in DB
 	var user models.User
-	if err := ctrl.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
+	found := ctrl.DB.Where("email = ?", input.Email).First(&user).Error == nil
+
+	// 4. Verify Password
+	// SECURITY BEST PRACTICE: unknown emails and accounts without a password are checked
+	// against a dummy hash, so they fail as slowly as a wrong password
+	hash := user.Password
+	if !found || hash == "" {
+		hash = utils.NoAccountHash
+	}
+	ok, err := utils.ComparePassword(input.Password, hash)
+	if errors.Is(err, utils.ErrPasswordBusy) {
+		ctrl.Guard.Release(c, input.Email)
+		c.Header("Retry-After", "5")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "We're busy right now, please try again in a moment"})
+		return
+	}
+	if !ok || hash == utils.NoAccountHash {
+		var account *models.User
+		if found {
+			account = &user
+		}
+		ctrl.Guard.Failed(c, input.Email, account, "password", models.LoginInvalidCredentials)
 		// SECURITY BEST PRACTICE: Generic "Invalid credentials" message
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
-	// 3. Verify Password
-	if !utils.CheckPasswordHash(input.Password, user.Password) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+	// 5. Start a session for this device and issue its tokens, or ask for the second factor first
+	resp, err := ctrl.TwoFactor.SignIn(c, &user)
+	if err != nil {
+		ctrl.Guard.Release(c, input.Email)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not start session"})
+		return
+	}
+	if resp["mfa_token"] != nil {
+		// The password was right; the second factor is checked, and counted, on its own
+		ctrl.Guard.Record(c, user.Email, &user, "password", models.LoginTwoFactorPending)
+		ctrl.Guard.Release(c, input.Email)
+	} else {
+		ctrl.Guard.Succeeded(c, user.Email, &user, "password")
+	}
+	if resp["message"] == nil {
+		resp["message"] = "Welcome back to the Spotlight"
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// startSession signs the user in on this device and returns the login response.
+// twoFactor records that they passed a second factor.
+func startSession(c *gin.Context, sessions *repository.SessionRepository, user *models.User, twoFactor bool) (gin.H, error) {
+	session, refreshToken, err := sessions.Create(user.ID, c.Request.UserAgent(), c.ClientIP(), refreshTokenTTL, twoFactor)
+	if err != nil {
+		return nil, err
+	}
+	token, err := utils.GenerateToken(user.ID, user.Role, session.ID, user.TokenVersion, twoFactor)
+	if err != nil {
+		return nil, err
+	}
+	return gin.H{
+		"token":          token,
+		"refresh_token":  refreshToken,
+		"expires_in":     int(utils.AccessTokenTTL().Seconds()),
+		"role":           user.Role,
+		"email_verified": user.IsVerified(),
+	}, nil
+}
+
+// Refresh handles POST /auth/refresh
+// Body: {"refresh_token": "..."}. Returns a new access token and a new refresh token;
+// the old refresh token stops working. Reusing an old one ends the session.
+func (ctrl *AuthController) Refresh(c *gin.Context) {
+	var input struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Refresh token is required"})
 		return
 	}
 
-	// 4. Generate the JWT
-	token, err := utils.GenerateToken(user.ID, user.Role)
+	session, refreshToken, err := ctrl.Sessions.Rotate(input.RefreshToken, refreshTokenTTL)
+	if errors.Is(err, repository.ErrInvalidSession) || errors.Is(err, repository.ErrRefreshTokenReused) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has ended, please log in again"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not refresh session"})
+		return
+	}
+
+	// The role comes from the database, so promotions apply from the next refresh
+	user, err := ctrl.Users.GetByID(session.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has ended, please log in again"})
+		return
+	}
+	token, err := utils.GenerateToken(user.ID, user.Role, session.ID, user.TokenVersion, session.TwoFactorAt != nil)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"token": token,
-		"role":  user.Role,
-		"message": "Welcome back to the Spotlight",
+		"token":          token,
+		"refresh_token":  refreshToken,
+		"expires_in":     int(utils.AccessTokenTTL().Seconds()),
+		"role":           user.Role,
+		"email_verified": user.IsVerified(),
 	})
 }
 
+// Logout handles POST /auth/logout. Ends the session the request was made with.
+func (ctrl *AuthController) Logout(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	sessionID, _ := c.Get("session_id")
+	if _, err := ctrl.Sessions.Revoke(userID.(uuid.UUID), sessionID.(uuid.UUID).String()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not log out"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// LogoutAll handles POST /auth/logout-all. Ends every session of the user, this one included.
+func (ctrl *AuthController) LogoutAll(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	count, err := ctrl.Sessions.RevokeAll(userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not log out"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out everywhere", "revoked": count})
+}
+
+// sessionView is a session as listed to its owner.
+type sessionView struct {
+	models.Session
+	Current bool `json:"current"`
+}
+
+// ListSessions handles GET /me/sessions. The session making the request is marked current.
+func (ctrl *AuthController) ListSessions(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	sessionID, _ := c.Get("session_id")
+	sessions, err := ctrl.Sessions.ListActive(userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+		return
+	}
+
+	items := make([]sessionView, len(sessions))
+	for i, s := range sessions {
+		items[i] = sessionView{Session: s, Current: s.ID == sessionID}
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// RevokeSession handles DELETE /me/sessions/:id. Signs one of the user's devices out.
+func (ctrl *AuthController) RevokeSession(c *gin.Context) {
+	if _, err := uuid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+	userID, _ := c.Get("user_id")
+	ok, err := ctrl.Sessions.Revoke(userID.(uuid.UUID), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not revoke session"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
+
 func (ctrl *AuthController) Register(c *gin.Context) {
 	var input RegisterRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -78,6 +257,10 @@ func (ctrl *AuthController) Register(c *gin.Context) {
 
 	// Hash password
 	hashedPassword, err := utils.HashPassword(input.Password)
+	if errors.Is(err, utils.ErrPasswordBusy) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "We're busy right now, please try again in a moment"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not secure account"})
 		return
@@ -87,8 +270,13 @@ func (ctrl *AuthController) Register(c *gin.Context) {
 	user := models.User{
 		ID:       uuid.New(),
 		Email:    input.Email,
+		Name:     input.Name,
 		Password: hashedPassword,
 		Role:     "viewer", // Default role
+		Locale:   input.Locale,
+	}
+	if user.Locale == "" {
+		user.Locale = "en"
 	}
 
 	if err := ctrl.DB.Create(&user).Error; err != nil {
@@ -96,5 +284,154 @@ func (ctrl *AuthController) Register(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Welcome to Spotlight Africa! You can now log in."})
-}
\ No newline at end of file
+	if err := ctrl.sendVerification(&user); err != nil {
+		log.Println("⚠️ Could not send verification email:", err)
+	}
+
+	ctrl.Events.Publish(c.Request.Context(), events.UserRegistered{
+		UserID: user.ID,
+		Email:  user.Email,
+		Name:   user.Name,
+		Locale: user.Locale,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Welcome to Spotlight Africa! Check your inbox to verify your email, then log in."})
+}
+
+// ForgotPassword handles POST /auth/forgot-password
+// Body: {"email": "..."}. Always answers the same way so it can't be used to discover accounts.
+func (ctrl *AuthController) ForgotPassword(c *gin.Context) {
+	var input struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A valid email is required"})
+		return
+	}
+
+	if user, err := ctrl.Users.GetByEmail(input.Email); err == nil {
+		token, err := ctrl.Tokens.Issue(user.ID, models.TokenPurposePasswordReset, passwordResetTTL)
+		if err == nil {
+			err = ctrl.Outbox.Queue(nil, user.Email, "password_reset", user.Locale, map[string]interface{}{
+				"Name":     user.DisplayName(),
+				"ResetURL": mail.Link("/reset-password?token=" + url.QueryEscape(token)),
+			})
+		}
+		if err != nil {
+			log.Println("⚠️ Could not send password reset email:", err)
+		}
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If an account exists for that email, we've sent a link to reset the password"})
+}
+
+// ResetPassword handles POST /auth/reset-password
+// Body: {"token": "...", "password": "..."}. The token works once. Resetting also verifies
+// the email, since the user has just proved they can read it.
+func (ctrl *AuthController) ResetPassword(c *gin.Context) {
+	var input struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required,min=6"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token and a password of at least 6 characters are required"})
+		return
+	}
+
+	hashed, err := utils.HashPassword(input.Password)
+	if errors.Is(err, utils.ErrPasswordBusy) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "We're busy right now, please try again in a moment"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not secure account"})
+		return
+	}
+
+	token, err := ctrl.Tokens.Consume(input.Token, models.TokenPurposePasswordReset)
+	if errors.Is(err, repository.ErrInvalidUserToken) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This reset link is invalid, expired or already used"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not reset password"})
+		return
+	}
+
+	if err := ctrl.Users.UpdatePassword(token.UserID, hashed); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not reset password"})
+		return
+	}
+	_ = ctrl.Users.MarkEmailVerified(token.UserID)
+	// A new password unlocks an account locked after failed sign-ins
+	if user, err := ctrl.Users.GetByID(token.UserID); err == nil {
+		if err := ctrl.Guard.Unlock(user.Email); err != nil {
+			log.Println("⚠️ Could not unlock account after password reset:", err)
+		}
+	}
+	// Whoever knew the old password may still be signed in
+	if _, err := ctrl.Sessions.RevokeAll(token.UserID); err != nil {
+		log.Println("⚠️ Could not revoke sessions after password reset:", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Your password has been reset. You can now log in."})
+}
+
+// VerifyEmail handles POST /auth/verify-email
+// Body: {"token": "..."} from the verification email.
+func (ctrl *AuthController) VerifyEmail(c *gin.Context) {
+	var input struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token is required"})
+		return
+	}
+
+	token, err := ctrl.Tokens.Consume(input.Token, models.TokenPurposeEmailVerification)
+	if errors.Is(err, repository.ErrInvalidUserToken) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This verification link is invalid, expired or already used"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify email"})
+		return
+	}
+	if err := ctrl.Users.MarkEmailVerified(token.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified"})
+}
+
+// ResendVerification handles POST /auth/resend-verification for the logged-in user.
+func (ctrl *AuthController) ResendVerification(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	user, err := ctrl.Users.GetByID(val.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if user.IsVerified() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Your email is already verified"})
+		return
+	}
+	if err := ctrl.sendVerification(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not send verification email"})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Verification email sent"})
+}
+
+// sendVerification emails the user a fresh verification link.
+func (ctrl *AuthController) sendVerification(user *models.User) error {
+	token, err := ctrl.Tokens.Issue(user.ID, models.TokenPurposeEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+	return ctrl.Outbox.Queue(nil, user.Email, "verify_email", user.Locale, map[string]interface{}{
+		"Name":      user.DisplayName(),
+		"VerifyURL": mail.Link("/verify-email?token=" + url.QueryEscape(token)),
+	})
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -8,9 +9,12 @@ import (
 	// Helper for converting strings (URL params) to integers
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/authz"
+	"github.com/saidimuKennedy/spotlight-africa/internal/events"
 	"github.com/saidimuKennedy/spotlight-africa/internal/models"
 	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
 	"github.com/saidimuKennedy/spotlight-africa/internal/utils"
+	"gorm.io/gorm"
 )
 
 // BusinessController handles HTTP requests related to businesses.
@@ -20,7 +24,10 @@ import (
 // This allows us to access the repository methods within our controller handlers.
 type BusinessController struct {
 	Repo         *repository.BusinessRepository // Dependency Injection: The controller needs a repository to work.
-	ActivityRepo *repository.ActivityRepository
+	HealthRepo   *repository.HealthRepository
+	Members      *repository.BusinessMemberRepository
+	Users        *repository.UserRepository
+	Events       *events.Bus // Side effects (activity, scoring) subscribe to business events
 }
 
 // ctrl *BusinessController means the method belongs to the BusinessController struct. 
@@ -35,41 +42,58 @@ func (ctrl *BusinessController) CreateBusiness(c *gin.Context) {
 		return
 	}
 
-	// 1. Check if user already has a business
-	var count int64
-	ctrl.Repo.DB.Model(&models.Business{}).Where("owner_id = ?", userID).Count(&count)
-	if count > 0 {
-		c.JSON(http.StatusConflict, gin.H{"error": "User already has a registered business"})
-		return
-	}
-
+	// 1. Users can register as many businesses as they run
 	var biz models.Business
 	if err := c.ShouldBindJSON(&biz); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// 2. Set ownership
+	// 2. Set ownership. Computed metrics are managed by the platform, not the form, and
+	// only editors of every business decide what is featured.
 	biz.OwnerID = userID.(uuid.UUID)
+	biz.HealthScore = 0
+	biz.Views = 0
+	biz.CreatedAt, biz.UpdatedAt = time.Time{}, time.Time{}
+	if !authz.Can(c, authz.BusinessUpdate) {
+		biz.IsFeatured = false
+	}
 
-	// 3. Create business
-	if err := ctrl.Repo.Create(&biz); err != nil {
+	// 3. Create the business with the creator as the first member of its team, and
+	// promote a viewer to 'owner' (staff keep their role). All or nothing, so there is
+	// never a business without an owner.
+	err := ctrl.Repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := (&repository.BusinessRepository{DB: tx}).Create(&biz); err != nil {
+			return err
+		}
+		if err := (&repository.BusinessMemberRepository{DB: tx}).AddOwner(biz.ID, biz.OwnerID); err != nil {
+			return err
+		}
+		return (&repository.UserRepository{DB: tx}).PromoteToOwner(c.Request.Context(), biz.OwnerID)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create business"})
 		return
 	}
-
-	// 4. Update User Role to 'owner'
-	if err := ctrl.Repo.DB.Model(&models.User{}).Where("id = ?", userID).Update("role", "owner").Error; err != nil {
-		// Log error but don't fail business creation.
+	ctrl.Users.ForgetAuthState(biz.OwnerID)
+	role, _ := c.Get("user_role")
+	if role == "viewer" {
+		role = "owner"
 	}
 
-	// 5. Generate New Token with updated role
-	newToken, _ := utils.GenerateToken(userID.(uuid.UUID), "owner")
+	// 4. Subscribers log the activity and anything else that reacts to new businesses
+	ctrl.Events.Publish(c.Request.Context(), events.BusinessCreated{BusinessID: biz.ID, OwnerID: biz.OwnerID, Name: biz.Name})
+
+	// 5. Generate a new access token with the updated role for the same session
+	sessionID, _ := c.Get("session_id")
+	version, _ := c.Get("token_version")
+	twoFactor, _ := c.Get("two_factor")
+	newToken, _ := utils.GenerateToken(userID.(uuid.UUID), role.(string), sessionID.(uuid.UUID), version.(int), twoFactor.(bool))
 
 	c.JSON(http.StatusCreated, gin.H{
 		"business": biz,
 		"token":    newToken,
-		"role":     "owner",
+		"role":     role,
 	})
 }
 
@@ -129,6 +153,10 @@ func (ctrl *BusinessController) UpdateBusiness(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Business not found"})
 		return
 	}
+	if !authz.CanAccess(c, authz.BusinessUpdate, memberRole(c, ctrl.Members, existing.ID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit businesses you help run"})
+		return
+	}
 
 	// 2. Parse the incoming JSON data.
 	var updatedBiz models.Business // planning basically we want to use this updatedBiz variable later
@@ -141,6 +169,16 @@ func (ctrl *BusinessController) UpdateBusiness(c *gin.Context) {
 	// We overwrite whatever ID might be in the body with the one from the URL.
 	updatedBiz.ID = existing.ID
 
+	// Ownership and computed metrics are managed by the platform, not the form.
+	// Only editors of every business (not just their own) decide what is featured.
+	updatedBiz.OwnerID = existing.OwnerID
+	updatedBiz.HealthScore = existing.HealthScore
+	updatedBiz.Views = existing.Views
+	updatedBiz.CreatedAt = existing.CreatedAt
+	if !authz.Can(c, authz.BusinessUpdate) {
+		updatedBiz.IsFeatured = existing.IsFeatured
+	}
+
 	// 4. Perform the update.
 	if err := ctrl.Repo.Update(&updatedBiz); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update business"})
@@ -156,6 +194,16 @@ func (ctrl *BusinessController) UpdateBusiness(c *gin.Context) {
 func (ctrl *BusinessController) DeleteBusiness(c *gin.Context) {
 	id := c.Param("id")
 
+	existing, err := ctrl.Repo.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Business not found"})
+		return
+	}
+	if !authz.CanAccess(c, authz.BusinessDelete, memberRole(c, ctrl.Members, existing.ID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can't delete this business"})
+		return
+	}
+
 	if err := ctrl.Repo.Delete(id); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete business"})
 		return
@@ -190,33 +238,42 @@ func (ctrl *BusinessController) GetEvents(c *gin.Context) {
 	c.JSON(http.StatusOK, events)
 }
 
-func (ctrl *BusinessController) TrackView(c *gin.Context) {
-	id := c.Param("id")
-	bizID, _ := uuid.Parse(id)
+// ViewDedupePeriod is how often one viewer's views of a business count.
+const ViewDedupePeriod = 24 * time.Hour
 
-	// 1. Increment Counter (Simple)
-	if err := ctrl.Repo.IncrementViews(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to track view"})
+// TrackView handles POST /businesses/:id/view
+// Each signed-in user, or IP address for anonymous visitors, counts once a day per business,
+// so refreshing or scripting views doesn't inflate the health score.
+func (ctrl *BusinessController) TrackView(c *gin.Context) {
+	bizID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Business not found"})
 		return
 	}
 
-	// 2. Log Detailed Activity
 	var userIDPtr *uuid.UUID
-	val, exists := c.Get("user_id")
-	if exists {
+	viewer := "ip:" + c.ClientIP()
+	if val, exists := c.Get("user_id"); exists {
 		uID := val.(uuid.UUID)
 		userIDPtr = &uID
+		viewer = "user:" + uID.String()
 	}
 
-	activity := models.Activity{
-		ID:         uuid.New(),
-		UserID:     userIDPtr,
-		EntityID:   bizID,
-		EntityType: "business",
-		Type:       models.ActivityTypeView,
-		CreatedAt:  time.Now(),
+	// 1. Increment Counter, unless this viewer was already counted today
+	counted, err := ctrl.Repo.RecordView(bizID, viewer, ViewDedupePeriod)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Business not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to track view"})
+		return
+	}
+
+	// 2. Log Detailed Activity
+	if counted {
+		ctrl.Events.Publish(c.Request.Context(), events.BusinessViewed{BusinessID: bizID, UserID: userIDPtr})
 	}
-	_ = ctrl.ActivityRepo.Track(&activity) // Async logging would be better but this is fine for now
 
 	c.JSON(http.StatusOK, gin.H{"message": "View tracked"})
 }
@@ -232,15 +289,64 @@ func (ctrl *BusinessController) TrackConversion(c *gin.Context) {
 		userIDPtr = &uID
 	}
 
-	activity := models.Activity{
-		ID:         uuid.New(),
-		UserID:     userIDPtr,
-		EntityID:   bizID,
-		EntityType: "business",
-		Type:       models.ActivityTypeConversion,
-		CreatedAt:  time.Now(),
-	}
-	_ = ctrl.ActivityRepo.Track(&activity)
+	ctrl.Events.Publish(c.Request.Context(), events.ConversionTracked{BusinessID: bizID, UserID: userIDPtr})
 
 	c.JSON(http.StatusOK, gin.H{"message": "Conversion tracked"})
-}
\ No newline at end of file
+}
+
+// GetHealth handles GET /businesses/:id/health
+// It returns the live health score with a breakdown of how each signal contributed.
+func (ctrl *BusinessController) GetHealth(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := ctrl.Repo.GetByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Business not found"})
+		return
+	}
+
+	breakdown, err := ctrl.HealthRepo.Breakdown(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not calculate health score"})
+		return
+	}
+	c.JSON(http.StatusOK, breakdown)
+}
+
+// GetHealthHistory handles GET /businesses/:id/health/history
+// Query params: interval ("daily" or "weekly", default daily), days (lookback, default 90, max 365).
+func (ctrl *BusinessController) GetHealthHistory(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := ctrl.Repo.GetByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Business not found"})
+		return
+	}
+
+	interval := c.DefaultQuery("interval", "daily")
+	trunc := map[string]string{"daily": "day", "weekly": "week"}[interval]
+	if trunc == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "interval must be daily or weekly"})
+		return
+	}
+
+	days, _ := strconv.Atoi(c.Query("days"))
+	if days <= 0 || days > 365 {
+		days = 90
+	}
+
+	points, err := ctrl.HealthRepo.History(id, trunc, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load health history"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"interval": interval, "days": days, "points": points})
+}
+
+// RecalculateAllHealth handles POST /admin/health/recompute
+// Rescores every business now instead of waiting for the background job.
+func (ctrl *BusinessController) RecalculateAllHealth(c *gin.Context) {
+	updated, err := ctrl.HealthRepo.RecalculateAll(200)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Recalculation failed", "updated": updated})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Health scores recalculated", "updated": updated})
+}
@@ -0,0 +1,339 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/authz"
+	"github.com/saidimuKennedy/spotlight-africa/internal/mail"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+)
+
+const invitationTTL = 7 * 24 * time.Hour
+
+// BusinessMemberController manages the team that runs a business: members, their roles,
+// invitations to join and handing over ownership.
+type BusinessMemberController struct {
+	Repo    *repository.BusinessMemberRepository
+	BizRepo *repository.BusinessRepository
+	Users   *repository.UserRepository
+	Outbox  *mail.Outbox
+}
+
+// memberRole returns the caller's role on a business's team, or "" if they aren't on it.
+func memberRole(c *gin.Context, members *repository.BusinessMemberRepository, businessID uuid.UUID) string {
+	val, ok := c.Get("user_id")
+	if !ok {
+		return ""
+	}
+	return members.Role(businessID, val.(uuid.UUID))
+}
+
+// teamBusiness loads the business from the URL and checks the caller may use perm on it.
+func (ctrl *BusinessMemberController) teamBusiness(c *gin.Context, perm authz.Permission) (*models.Business, bool) {
+	business, err := ctrl.BizRepo.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Business not found"})
+		return nil, false
+	}
+	if !authz.CanAccess(c, perm, memberRole(c, ctrl.Repo, business.ID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can't manage this business's team"})
+		return nil, false
+	}
+	return business, true
+}
+
+// ListMembers handles GET /businesses/:id/members
+// Anyone on the team can see who else is on it.
+func (ctrl *BusinessMemberController) ListMembers(c *gin.Context) {
+	business, err := ctrl.BizRepo.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Business not found"})
+		return
+	}
+	role := memberRole(c, ctrl.Repo, business.ID)
+	if role == "" && !authz.Can(c, authz.MembersManage) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You aren't on this business's team"})
+		return
+	}
+
+	members, err := ctrl.Repo.List(business.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch members"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": members, "role": role})
+}
+
+// UpdateMember handles PATCH /businesses/:id/members/:userId
+// Body: {"role": "manager"|"editor"|"viewer"}. Ownership changes through /transfer instead.
+func (ctrl *BusinessMemberController) UpdateMember(c *gin.Context) {
+	business, ok := ctrl.teamBusiness(c, authz.MembersManage)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+	var input struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || !models.ValidMemberRole(input.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be manager, editor or viewer"})
+		return
+	}
+
+	member, err := ctrl.Repo.UpdateRole(business.ID, userID, input.Role)
+	switch {
+	case errors.Is(err, repository.ErrNotMember):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+	case errors.Is(err, repository.ErrOwnerMembership):
+		c.JSON(http.StatusConflict, gin.H{"error": "Transfer ownership to change the owner's role"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update member"})
+	default:
+		c.JSON(http.StatusOK, member)
+	}
+}
+
+// RemoveMember handles DELETE /businesses/:id/members/:userId
+// Members who manage the team can remove others; anyone but the owner can remove themselves.
+func (ctrl *BusinessMemberController) RemoveMember(c *gin.Context) {
+	business, err := ctrl.BizRepo.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Business not found"})
+		return
+	}
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+	val, _ := c.Get("user_id")
+	leaving := userID == val.(uuid.UUID)
+	if !leaving && !authz.CanAccess(c, authz.MembersManage, memberRole(c, ctrl.Repo, business.ID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can't manage this business's team"})
+		return
+	}
+
+	err = ctrl.Repo.Remove(business.ID, userID)
+	switch {
+	case errors.Is(err, repository.ErrNotMember):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+	case errors.Is(err, repository.ErrOwnerMembership):
+		c.JSON(http.StatusConflict, gin.H{"error": "Transfer ownership before the owner leaves"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not remove member"})
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Member removed"})
+	}
+}
+
+// TransferOwnership handles POST /businesses/:id/transfer
+// Body: {"user_id": "..."}. Only the owner (or staff who manage every team) can hand the
+// business to another member. The previous owner stays on the team as a manager.
+func (ctrl *BusinessMemberController) TransferOwnership(c *gin.Context) {
+	business, err := ctrl.BizRepo.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Business not found"})
+		return
+	}
+	if memberRole(c, ctrl.Repo, business.ID) != models.MemberRoleOwner && !authz.Can(c, authz.MembersManage) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the owner can transfer the business"})
+		return
+	}
+	var input struct {
+		UserID uuid.UUID `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+		return
+	}
+
+	err = ctrl.Repo.TransferOwnership(business.ID, business.OwnerID, input.UserID)
+	switch {
+	case errors.Is(err, repository.ErrAlreadyMember):
+		c.JSON(http.StatusConflict, gin.H{"error": "They already own this business"})
+		return
+	case errors.Is(err, repository.ErrNotMember):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invite them to the team before handing it over"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not transfer ownership"})
+		return
+	}
+
+	// The new owner gets the platform's owner role, as if they had created the business
+	if err := ctrl.Users.PromoteToOwner(c.Request.Context(), input.UserID); err != nil {
+		log.Println("⚠️ Could not promote new owner:", err)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Ownership transferred"})
+}
+
+// Invite handles POST /businesses/:id/invitations
+// Body: {"email": "...", "role": "manager"|"editor"|"viewer"}. Emails a link that joins
+// the team once accepted by an account with that address.
+func (ctrl *BusinessMemberController) Invite(c *gin.Context) {
+	business, ok := ctrl.teamBusiness(c, authz.MembersManage)
+	if !ok {
+		return
+	}
+	var input struct {
+		Email string `json:"email" binding:"required,email"`
+		Role  string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A valid email and role are required"})
+		return
+	}
+	if !models.ValidMemberRole(input.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be manager, editor or viewer"})
+		return
+	}
+
+	val, _ := c.Get("user_id")
+	inviter, err := ctrl.Users.GetByID(val.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	invitation, token, err := ctrl.Repo.Invite(business.ID, inviter.ID, input.Email, input.Role, invitationTTL)
+	if errors.Is(err, repository.ErrAlreadyMember) {
+		c.JSON(http.StatusConflict, gin.H{"error": "They're already on the team"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create invitation"})
+		return
+	}
+
+	// Write in the invitee's language if they already have an account
+	locale := inviter.Locale
+	if invitee, err := ctrl.Users.GetByEmail(invitation.Email); err == nil {
+		locale = invitee.Locale
+	}
+	err = ctrl.Outbox.Queue(nil, invitation.Email, "business_invitation", locale, map[string]interface{}{
+		"InviterName":  inviter.DisplayName(),
+		"BusinessName": business.Name,
+		"Role":         invitation.Role,
+		"AcceptURL":    mail.Link("/invitations/accept?token=" + url.QueryEscape(token)),
+	})
+	if err != nil {
+		// Inviting again replaces this invitation, so the caller can simply retry
+		log.Println("⚠️ Could not send business invitation:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not send the invitation email, please try again"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// ListInvitations handles GET /businesses/:id/invitations
+// Returns invitations that haven't been accepted and haven't expired.
+func (ctrl *BusinessMemberController) ListInvitations(c *gin.Context) {
+	business, ok := ctrl.teamBusiness(c, authz.MembersManage)
+	if !ok {
+		return
+	}
+	invitations, err := ctrl.Repo.PendingInvitations(business.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invitations"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": invitations})
+}
+
+// RevokeInvitation handles DELETE /businesses/:id/invitations/:invitationId
+func (ctrl *BusinessMemberController) RevokeInvitation(c *gin.Context) {
+	business, ok := ctrl.teamBusiness(c, authz.MembersManage)
+	if !ok {
+		return
+	}
+	revoked, err := ctrl.Repo.RevokeInvitation(business.ID, c.Param("invitationId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not revoke invitation"})
+		return
+	}
+	if !revoked {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation revoked"})
+}
+
+// GetInvitation handles GET /invitations/:token
+// Shows who the invitation is for before the user signs in to accept it.
+func (ctrl *BusinessMemberController) GetInvitation(c *gin.Context) {
+	invitation, err := ctrl.Repo.GetInvitation(c.Param("token"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "This invitation is invalid or has expired"})
+		return
+	}
+	business, err := ctrl.BizRepo.GetByID(invitation.BusinessID.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "This invitation is invalid or has expired"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"business_id":   business.ID,
+		"business_name": business.Name,
+		"email":         invitation.Email,
+		"role":          invitation.Role,
+		"expires_at":    invitation.ExpiresAt,
+	})
+}
+
+// AcceptInvitation handles POST /invitations/accept
+// Body: {"token": "..."}. The signed-in account must have the invited, verified email.
+// Viewers become owners so they can open the business dashboard; what they may do in
+// the business still depends on their role in its team.
+func (ctrl *BusinessMemberController) AcceptInvitation(c *gin.Context) {
+	var input struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token is required"})
+		return
+	}
+
+	val, _ := c.Get("user_id")
+	user, err := ctrl.Users.GetByID(val.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !user.IsVerified() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Verify your email before joining a team"})
+		return
+	}
+
+	member, err := ctrl.Repo.AcceptInvitation(input.Token, user)
+	switch {
+	case errors.Is(err, repository.ErrInvalidInvitation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This invitation is invalid or has expired"})
+	case errors.Is(err, repository.ErrInvitationEmail):
+		c.JSON(http.StatusForbidden, gin.H{"error": "This invitation was sent to a different email address"})
+	case errors.Is(err, repository.ErrAlreadyMember):
+		c.JSON(http.StatusConflict, gin.H{"error": "You're already on this team"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not accept invitation"})
+	default:
+		if err := ctrl.Users.PromoteToOwner(c.Request.Context(), user.ID); err != nil {
+			log.Println("⚠️ Could not promote new team member:", err)
+		}
+		role := user.Role
+		if role == "viewer" {
+			role = "owner"
+		}
+		c.JSON(http.StatusOK, gin.H{"member": member, "role": role})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/authz"
 	"github.com/saidimuKennedy/spotlight-africa/internal/models"
 	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
 )
@@ -17,10 +18,14 @@ var StartTime = time.Now()
 
 
 type DashboardController struct {
-	BizRepo      *repository.BusinessRepository
-	InterRepo    *repository.InteractionRepository
-	ActivityRepo *repository.ActivityRepository
-	MeetingRepo  *repository.MeetingRepository
+	BizRepo             *repository.BusinessRepository
+	InterRepo           *repository.InteractionRepository
+	ActivityRepo        *repository.ActivityRepository
+	MeetingRepo         *repository.MeetingRepository
+	PlatformInquiryRepo *repository.PlatformInquiryRepository
+	HealthRepo          *repository.HealthRepository
+	Members             *repository.BusinessMemberRepository
+	Users               *repository.UserRepository
 }
 
 func (ctrl *DashboardController) GetDashboardMe(c *gin.Context) {
@@ -56,7 +61,10 @@ func (ctrl *DashboardController) GetDashboardMe(c *gin.Context) {
 
 		// For Admin, fetch ALL inquiries for the pipeline view
 		var allInquiries []models.Inquiry
-		ctrl.InterRepo.DB.Preload("User").Order("created_at desc").Find(&allInquiries)
+		ctrl.InterRepo.DB.Preload("User").Where("is_quarantined = ?", false).Order("created_at desc").Find(&allInquiries)
+
+		// Contact-form triage queue, broken down by status
+		platformInquiries, _ := ctrl.PlatformInquiryRepo.CountByStatus()
 
 		c.JSON(http.StatusOK, gin.H{
 			"user": user,
@@ -74,14 +82,19 @@ func (ctrl *DashboardController) GetDashboardMe(c *gin.Context) {
 				"status":             "Healthy",
 				"active_goroutines": runtime.NumGoroutine(),
 			},
-			"pipeline": allInquiries,
+			"pipeline":           allInquiries,
+			"platform_inquiries": platformInquiries,
 		})
 		return
 	}
 
-	// OWNER / REGULAR USER LOGIC
-	// Fetch business owned by this user
-	business, err := ctrl.BizRepo.GetByOwnerID(userID.(uuid.UUID).String())
+	// TEAM MEMBER / REGULAR USER LOGIC
+	// Show the business this user owns or helps run; the others are one switch away
+	var business *models.Business
+	membership, err := ctrl.Members.BusinessFor(user.ID)
+	if err == nil {
+		business, err = ctrl.BizRepo.GetByID(membership.BusinessID.String())
+	}
 	if err != nil {
 		// If they don't have a business, they are treated as a regular user (privileged or viewer)
 		c.JSON(http.StatusOK, gin.H{
@@ -93,31 +106,114 @@ func (ctrl *DashboardController) GetDashboardMe(c *gin.Context) {
 		return
 	}
 
-	// Fetch recent inquiries
-	inquiries, _ := ctrl.InterRepo.GetInquiriesByBusiness(business.ID.String())
-	
+	ctrl.businessDashboard(c, &user, business, membership.Role)
+}
+
+// GetBusinessDashboard handles GET /dashboard/businesses/:id
+// The dashboard of one of the user's businesses, for users who run several. Staff who can
+// edit every business can open any of them.
+func (ctrl *DashboardController) GetBusinessDashboard(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	user, err := ctrl.Users.GetByID(val.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	business, err := ctrl.BizRepo.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Business not found"})
+		return
+	}
+	role := ctrl.Members.Role(business.ID, user.ID)
+	if role == "" && !authz.Can(c, authz.BusinessUpdate) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You aren't on this business's team"})
+		return
+	}
+
+	ctrl.businessDashboard(c, user, business, role)
+}
+
+// GetPortfolio handles GET /dashboard/businesses
+// Lists the user's businesses with their headline numbers, and totals across all of them.
+func (ctrl *DashboardController) GetPortfolio(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uuid.UUID)
+
+	businesses, err := ctrl.Members.Businesses(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch businesses"})
+		return
+	}
+	portfolio, err := ctrl.Members.Portfolio(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate portfolio"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": businesses, "portfolio": portfolio})
+}
+
+// businessDashboard responds with a business's dashboard as seen by a user holding
+// memberRole in its team ("" for staff looking in).
+func (ctrl *DashboardController) businessDashboard(c *gin.Context, user *models.User, business *models.Business, memberRole string) {
+	// Fetch recent inquiries. Viewers see the numbers but not the people who wrote in.
+	inquiries := []models.Inquiry{}
+	var inquiryCount int64
+	if memberRole == models.MemberRoleViewer {
+		ctrl.InterRepo.DB.Model(&models.Inquiry{}).
+			Where("business_id = ? AND is_quarantined = ?", business.ID, false).Count(&inquiryCount)
+	} else {
+		inquiries, _ = ctrl.InterRepo.GetInquiriesByBusiness(business.ID.String())
+		inquiryCount = int64(len(inquiries))
+	}
+
 	// Fetch real activities
 	activities, _ := ctrl.ActivityRepo.GetByEntity(business.ID.String(), "business")
 
-	// Fetch real meetings
-	meetings, _ := ctrl.MeetingRepo.GetByBusinessID(business.ID.String())
+	// Fetch real meetings. Viewers likewise get the count but not who booked them.
+	meetings := []models.Meeting{}
+	var meetingCount int64
+	if memberRole == models.MemberRoleViewer {
+		ctrl.MeetingRepo.DB.Model(&models.Meeting{}).Where("business_id = ?", business.ID).Count(&meetingCount)
+	} else {
+		meetings, _ = ctrl.MeetingRepo.GetByBusinessID(business.ID.String())
+		meetingCount = int64(len(meetings))
+	}
 
 	var conversionCount int64
 	ctrl.ActivityRepo.DB.Model(&models.Activity{}).
 		Where("entity_id = ? AND entity_type = ? AND type = ?", business.ID, "business", models.ActivityTypeConversion).
 		Count(&conversionCount)
 
+	// Week-over-week health change, from the snapshot history
+	healthDelta, healthTrend := 0, "flat"
+	if prev, ok, _ := ctrl.HealthRepo.ScoreAt(business.ID.String(), time.Now().AddDate(0, 0, -7)); ok {
+		healthDelta = business.HealthScore - prev
+		if healthDelta > 0 {
+			healthTrend = "up"
+		} else if healthDelta < 0 {
+			healthTrend = "down"
+		}
+	}
+
+	// Every business the user can switch to
+	businesses, _ := ctrl.Members.Businesses(user.ID)
+
 	c.JSON(http.StatusOK, gin.H{
 		"user":     user,
-		"role":     "owner",
+		"role":     user.Role,
+		"member_role": memberRole,
 		"has_business": true,
 		"business": business,
+		"businesses": businesses,
 		"stats": gin.H{
 			"health_score":     business.HealthScore,
 			"profile_views":    business.Views,
-			"active_inquiries": len(inquiries),
+			"active_inquiries": inquiryCount,
+			"meetings":         meetingCount,
 			"likes":           business.LikeCount,
 			"conversions":     conversionCount,
+			"health_score_delta_wow": healthDelta,
+			"health_trend":           healthTrend,
 		},
 		"pipeline":  inquiries,
 		"activities": activities,
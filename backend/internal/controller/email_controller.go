@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+)
+
+// EmailController receives bounce and complaint reports from the mail provider and lets
+// admins inspect the outbox and manage the suppression list.
+type EmailController struct {
+	Repo          *repository.EmailRepository
+	WebhookSecret string // EMAIL_WEBHOOK_SECRET; the webhook is disabled when empty
+}
+
+// emailEvent is one delivery report. Providers differ, so both "type" and the
+// SendGrid-style "event" are accepted.
+type emailEvent struct {
+	Type       string `json:"type"`
+	Event      string `json:"event"`
+	Email      string `json:"email"`
+	BounceType string `json:"bounce_type"` // "hard" (default) or "soft"
+	Reason     string `json:"reason"`
+}
+
+// HandleWebhook handles POST /webhooks/email
+// The secret is passed in the X-Webhook-Secret header. It is never read from the query
+// string, which ends up in access logs.
+// The body is one event or an array of events. Hard bounces and complaints suppress
+// the address; soft bounces are left to the job queue's retries.
+func (ctrl *EmailController) HandleWebhook(c *gin.Context) {
+	secret := c.GetHeader("X-Webhook-Secret")
+	if ctrl.WebhookSecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(ctrl.WebhookSecret)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid webhook secret"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read body"})
+		return
+	}
+	var batch []emailEvent
+	if trimmed := strings.TrimSpace(string(body)); strings.HasPrefix(trimmed, "[") {
+		err = json.Unmarshal(body, &batch)
+	} else {
+		var single emailEvent
+		err = json.Unmarshal(body, &single)
+		batch = []emailEvent{single}
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event payload"})
+		return
+	}
+
+	suppressed := 0
+	for _, e := range batch {
+		reason := suppressionReason(e)
+		if reason == "" || e.Email == "" {
+			continue
+		}
+		if err := ctrl.Repo.Suppress(e.Email, reason, e.Reason); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record event"})
+			return
+		}
+		suppressed++
+	}
+
+	c.JSON(http.StatusOK, gin.H{"received": len(batch), "suppressed": suppressed})
+}
+
+// suppressionReason maps a provider event to a suppression reason, or "" if the
+// address should keep receiving mail.
+func suppressionReason(e emailEvent) string {
+	kind := strings.ToLower(e.Type)
+	if kind == "" {
+		kind = strings.ToLower(e.Event)
+	}
+	switch kind {
+	case "bounce", "bounced", "dropped":
+		if strings.EqualFold(e.BounceType, "soft") || strings.EqualFold(e.BounceType, "transient") {
+			return ""
+		}
+		return models.SuppressionBounce
+	case "complaint", "spamreport", "spam":
+		return models.SuppressionComplaint
+	}
+	return ""
+}
+
+// ListOutbox handles GET /admin/email/outbox
+// Query params: status (pending, sent, failed, suppressed), limit, offset.
+func (ctrl *EmailController) ListOutbox(c *gin.Context) {
+	limit, offset := emailPage(c)
+	list, total, err := ctrl.Repo.ListOutbox(c.Query("status"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch outbox"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": list, "total": total, "limit": limit, "offset": offset})
+}
+
+// ListSuppressions handles GET /admin/email/suppressions
+func (ctrl *EmailController) ListSuppressions(c *gin.Context) {
+	limit, offset := emailPage(c)
+	list, total, err := ctrl.Repo.ListSuppressions(limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch suppressions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": list, "total": total, "limit": limit, "offset": offset})
+}
+
+// AddSuppression handles POST /admin/email/suppressions
+func (ctrl *EmailController) AddSuppression(c *gin.Context) {
+	var input struct {
+		Email  string `json:"email" binding:"required,email"`
+		Detail string `json:"detail"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A valid email is required"})
+		return
+	}
+	if err := ctrl.Repo.Suppress(input.Email, models.SuppressionManual, input.Detail); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to suppress address"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Address suppressed"})
+}
+
+// RemoveSuppression handles DELETE /admin/email/suppressions/:email
+func (ctrl *EmailController) RemoveSuppression(c *gin.Context) {
+	found, err := ctrl.Repo.Unsuppress(c.Param("email"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove suppression"})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Address is not suppressed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Address can receive email again"})
+}
+
+func emailPage(c *gin.Context) (limit, offset int) {
+	limit, _ = strconv.Atoi(c.Query("limit"))
+	offset, _ = strconv.Atoi(c.Query("offset"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return limit, offset
+}
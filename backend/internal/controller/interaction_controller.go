@@ -3,56 +3,157 @@ package controller
 import (
 	"fmt"
 	"net/http"
-	"time"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/authz"
+	"github.com/saidimuKennedy/spotlight-africa/internal/events"
 	"github.com/saidimuKennedy/spotlight-africa/internal/models"
 	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+	"github.com/saidimuKennedy/spotlight-africa/internal/spam"
 )
 
 type InteractionController struct {
-	Repo         *repository.InteractionRepository
-	ActivityRepo *repository.ActivityRepository
+	Repo           *repository.InteractionRepository
+	Members        *repository.BusinessMemberRepository
+	Events         *events.Bus
+	ModerationRepo *repository.ModerationRepository
+	Spam           *spam.Analyzer
+	Captcha        spam.Verifier // Optional; nil disables captcha on public forms
+
+	// MaxCommentDepth caps how deeply replies can nest (1 = replies to top-level comments only).
+	MaxCommentDepth int
 }
 
+// defaultMaxCommentDepth applies when MaxCommentDepth is not configured.
+const defaultMaxCommentDepth = 3
+
+// quarantineEntry is the review record for a held-back submission, or nil when the
+// submission wasn't held back.
+func (ctrl *InteractionController) quarantineEntry(c *gin.Context, held bool, entityType string, entityID uuid.UUID, userID *uuid.UUID, excerpt string, res spam.Result) *models.QuarantineEntry {
+	if !held {
+		return nil
+	}
+	return &models.QuarantineEntry{
+		EntityType: entityType,
+		EntityID:   entityID,
+		UserID:     userID,
+		IP:         c.ClientIP(),
+		Excerpt:    excerpt,
+		SpamScore:  res.Score,
+		Reasons:    strings.Join(res.Reasons, "; "),
+	}
+}
+
+// LikeBusiness handles POST /businesses/:id/like
+// Kept for existing clients: it is SetReaction with the plain "like" type, and is idempotent.
 func (ctrl *InteractionController) LikeBusiness(c *gin.Context) {
+	ctrl.react(c, models.ReactionLike)
+}
+
+// SetReaction handles PUT /businesses/:id/reaction
+// Body: {"type": "like|inspiring|want_to_partner"}. Repeating the same request is a no-op;
+// sending a different type replaces the previous reaction.
+func (ctrl *InteractionController) SetReaction(c *gin.Context) {
+	var input struct {
+		Type string `json:"type"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	reaction := models.ReactionType(input.Type)
+	if reaction == "" {
+		reaction = models.ReactionLike
+	}
+	if !models.ValidReactionTypes[reaction] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Reaction must be one of like, inspiring, want_to_partner"})
+		return
+	}
+
+	ctrl.react(c, reaction)
+}
+
+func (ctrl *InteractionController) react(c *gin.Context, reaction models.ReactionType) {
 	// 1. Extract IDs
-	bizID, _ := uuid.Parse(c.Param("id"))
-	
+	bizID, err := uuid.Parse(c.Param("id"))
+	if err != nil || !ctrl.Repo.BusinessExists(bizID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Business not found"})
+		return
+	}
+
 	val, _ := c.Get("user_id")
 	userID := val.(uuid.UUID)
 
-	like := models.Like{
-		ID:         uuid.New(),
-		UserID:     userID,
-		BusinessID: bizID,
+	// 2. Upsert the reaction
+	like, changed, err := ctrl.Repo.SetReaction(userID, bizID, reaction)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save reaction"})
+		return
+	}
+
+	// 3. Let subscribers react (scoring, notifications), only when something actually changed
+	if !changed {
+		c.JSON(http.StatusOK, gin.H{"message": "You already reacted to this business", "reaction": like, "changed": false})
+		return
+	}
+	ctrl.Events.Publish(c.Request.Context(), events.LikeAdded{BusinessID: bizID, UserID: userID, Reaction: string(reaction)})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pulse increased!", "reaction": like, "changed": true})
+}
+
+// RemoveReaction handles DELETE /businesses/:id/reaction (and DELETE /businesses/:id/like).
+// Idempotent: removing a reaction that doesn't exist still succeeds.
+func (ctrl *InteractionController) RemoveReaction(c *gin.Context) {
+	bizID, err := uuid.Parse(c.Param("id"))
+	if err != nil || !ctrl.Repo.BusinessExists(bizID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Business not found"})
+		return
 	}
 
-	if err := ctrl.Repo.AddLike(&like); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Already liked or not found"})
+	val, _ := c.Get("user_id")
+	userID := val.(uuid.UUID)
+	removed, err := ctrl.Repo.RemoveReaction(userID, bizID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove reaction"})
 		return
 	}
 
-	// 3. TRIGGER UPDATE (Asynchronously)
-	go ctrl.logHealthEval(bizID)
+	if !removed {
+		c.JSON(http.StatusOK, gin.H{"message": "You haven't reacted to this business", "removed": false})
+		return
+	}
+	ctrl.Events.Publish(c.Request.Context(), events.LikeRemoved{BusinessID: bizID, UserID: userID})
 
-	c.JSON(http.StatusOK, gin.H{"message": "Pulse increased!"})
+	c.JSON(http.StatusOK, gin.H{"message": "Reaction removed", "removed": true})
 }
 
-func (ctrl *InteractionController) logHealthEval(bizID uuid.UUID) {
-	score, err := ctrl.Repo.UpdateHealthScore(bizID.String())
-	if err == nil {
-		activity := models.Activity{
-			ID:         uuid.New(),
-			EntityID:   bizID,
-			EntityType: "business",
-			Type:       models.ActivityTypeHealthEval,
-			Metadata:   fmt.Sprintf("Health Score recalibrated to %d%%", score),
-			CreatedAt:  time.Now(),
-		}
-		_ = ctrl.ActivityRepo.Track(&activity)
+// GetMyLikes handles GET /me/likes
+// Query params: type (optional reaction filter), limit, offset.
+func (ctrl *InteractionController) GetMyLikes(c *gin.Context) {
+	val, _ := c.Get("user_id")
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	items, total, err := ctrl.Repo.GetReactionsByUser(val.(uuid.UUID), c.Query("type"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch likes"})
+		return
 	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":  items,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
 }
 
 func (ctrl *InteractionController) AddComment(c *gin.Context) {
@@ -61,39 +162,317 @@ func (ctrl *InteractionController) AddComment(c *gin.Context) {
 	userID := val.(uuid.UUID)
 
 	var input struct {
-		Content string `json:"content" binding:"required"`
+		Content  string `json:"content" binding:"required"`
+		ParentID string `json:"parent_id"` // Optional: reply to this comment
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Content is required"})
 		return
 	}
 
+	check := ctrl.Spam.Check(input.Content)
+
 	comment := models.Comment{
-		ID:         uuid.New(),
-		UserID:     userID,
-		BusinessID: bizID,
-		Content:    input.Content,
+		ID:            uuid.New(),
+		UserID:        userID,
+		BusinessID:    bizID,
+		Content:       input.Content,
+		IsQuarantined: ctrl.Spam.IsSpam(check),
 	}
 
-	if err := ctrl.Repo.AddComment(&comment); err != nil {
+	// Replies inherit the thread root and sit one level below their parent.
+	if input.ParentID != "" {
+		parent, err := ctrl.Repo.GetComment(input.ParentID)
+		if err != nil || parent.BusinessID != bizID || parent.IsQuarantined {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parent comment not found"})
+			return
+		}
+		if parent.DeletedAt != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot reply to a deleted comment"})
+			return
+		}
+
+		maxDepth := ctrl.MaxCommentDepth
+		if maxDepth <= 0 {
+			maxDepth = defaultMaxCommentDepth
+		}
+		if parent.Depth+1 > maxDepth {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Replies can only be nested %d levels deep", maxDepth)})
+			return
+		}
+
+		rootID := parent.ID
+		if parent.RootID != nil {
+			rootID = *parent.RootID
+		}
+		comment.ParentID = &parent.ID
+		comment.RootID = &rootID
+		comment.Depth = parent.Depth + 1
+	}
+
+	entry := ctrl.quarantineEntry(c, comment.IsQuarantined, models.QuarantineEntityComment, comment.ID, &userID, comment.Content, check)
+	if err := ctrl.ModerationRepo.Submit(&comment, entry); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to post"})
 		return
 	}
 
-	// TRIGGER UPDATE (After success)
-	go ctrl.logHealthEval(bizID)
+	// Held-back comments don't count towards the score until approved.
+	if comment.IsQuarantined {
+		c.JSON(http.StatusAccepted, gin.H{"message": "Comment submitted for review"})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Comment added"})
+	ctrl.Events.Publish(c.Request.Context(), events.CommentAdded{
+		CommentID:  comment.ID,
+		BusinessID: bizID,
+		UserID:     userID,
+		ParentID:   comment.ParentID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment added", "comment": comment})
 }
 
+// GetComments handles GET /businesses/:id/comments
+// Returns a page of top-level comments with their replies nested under "replies".
+// Query params: sort ("newest" or "top"), limit, offset.
 func (ctrl *InteractionController) GetComments(c *gin.Context) {
 	id := c.Param("id")
-	comments, err := ctrl.Repo.GetCommentsByBusiness(id)
+
+	sort := c.DefaultQuery("sort", "newest")
+	if sort != "top" {
+		sort = "newest"
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	comments, total, err := ctrl.Repo.GetCommentThreads(id, sort, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, comments)
+	redactDeletedComments(comments)
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": comments,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+		"sort":     sort,
+	})
+}
+
+// redactDeletedComments blanks out deleted and hidden comments so only their place in the thread remains.
+func redactDeletedComments(comments []models.Comment) {
+	for i := range comments {
+		if comments[i].DeletedAt != nil || comments[i].IsHidden {
+			comments[i].Content = ""
+			comments[i].UserID = uuid.Nil
+			comments[i].User = models.User{}
+		}
+		redactDeletedComments(comments[i].Replies)
+	}
+}
+
+// EditComment handles PATCH /comments/:id
+// Only the author can edit; the previous text is kept as a revision. The new text goes
+// through the same spam checks as a new comment.
+func (ctrl *InteractionController) EditComment(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uuid.UUID)
+
+	var input struct {
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Content is required"})
+		return
+	}
+
+	comment, err := ctrl.Repo.GetComment(c.Param("id"))
+	if err != nil || comment.DeletedAt != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+	if comment.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own comments"})
+		return
+	}
+	if comment.Content == input.Content {
+		c.JSON(http.StatusOK, comment)
+		return
+	}
+
+	check := ctrl.Spam.Check(input.Content)
+	entry := ctrl.quarantineEntry(c, ctrl.Spam.IsSpam(check), models.QuarantineEntityComment, comment.ID, &userID, input.Content, check)
+	if entry != nil {
+		entry.IsEdit = !comment.IsQuarantined
+	}
+	if err := ctrl.Repo.EditComment(comment, input.Content, entry); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to edit comment"})
+		return
+	}
+
+	if entry != nil {
+		c.JSON(http.StatusAccepted, gin.H{"message": "Comment submitted for review"})
+		return
+	}
+	c.JSON(http.StatusOK, comment)
+}
+
+// DeleteComment handles DELETE /comments/:id
+// Authors can delete their own comments and admins can delete any. Replies are kept.
+func (ctrl *InteractionController) DeleteComment(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uuid.UUID)
+
+	comment, err := ctrl.Repo.GetComment(c.Param("id"))
+	if err != nil || comment.DeletedAt != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+	if comment.UserID != userID && !authz.Can(c, authz.ModerationManage) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only delete your own comments"})
+		return
+	}
+
+	if err := ctrl.Repo.SoftDeleteComment(comment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		return
+	}
+
+	ctrl.Events.Publish(c.Request.Context(), events.CommentVisibilityChanged{CommentID: comment.ID, BusinessID: comment.BusinessID, Visible: false})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted"})
+}
+
+// ReportComment handles POST /comments/:id/report
+// Body: {"reason": "spam|harassment|hate|misinformation|off_topic|other", "details": "..."}
+func (ctrl *InteractionController) ReportComment(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uuid.UUID)
+
+	var input struct {
+		Reason  string `json:"reason" binding:"required"`
+		Details string `json:"details"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || !models.ValidReportReasons[models.ReportReason(input.Reason)] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A valid reason is required (spam, harassment, hate, misinformation, off_topic, other)"})
+		return
+	}
+
+	comment, err := ctrl.Repo.GetComment(c.Param("id"))
+	if err != nil || comment.DeletedAt != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+
+	report := models.CommentReport{
+		CommentID:  comment.ID,
+		ReporterID: userID,
+		Reason:     models.ReportReason(input.Reason),
+		Details:    input.Details,
+	}
+	if err := ctrl.ModerationRepo.AddReport(&report); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "You have already reported this comment"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Thanks, our moderators will take a look"})
+}
+
+// SetCommentVisibility handles PATCH /comments/:id/visibility
+// Body: {"hidden": true}. Allowed for the owner of the business the comment is on, and admins.
+func (ctrl *InteractionController) SetCommentVisibility(c *gin.Context) {
+	var input struct {
+		Hidden *bool `json:"hidden" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "hidden is required"})
+		return
+	}
+
+	comment, ok := ctrl.moderatableComment(c)
+	if !ok {
+		return
+	}
+
+	val, _ := c.Get("user_id")
+	if err := ctrl.Repo.SetCommentHidden(comment, *input.Hidden, val.(uuid.UUID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
+		return
+	}
+
+	// Hidden comments stop counting towards the score (and count again when restored).
+	ctrl.Events.Publish(c.Request.Context(), events.CommentVisibilityChanged{CommentID: comment.ID, BusinessID: comment.BusinessID, Visible: !*input.Hidden})
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// SetCommentPinned handles PATCH /comments/:id/pin
+// Body: {"pinned": true}. Only top-level comments can be pinned.
+func (ctrl *InteractionController) SetCommentPinned(c *gin.Context) {
+	var input struct {
+		Pinned *bool `json:"pinned" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "pinned is required"})
+		return
+	}
+
+	comment, ok := ctrl.moderatableComment(c)
+	if !ok {
+		return
+	}
+	if comment.ParentID != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only top-level comments can be pinned"})
+		return
+	}
+
+	if err := ctrl.Repo.SetCommentPinned(comment, *input.Pinned); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// moderatableComment loads the comment from the URL and checks the caller may moderate it:
+// the business's team, or staff who moderate every business's comments.
+func (ctrl *InteractionController) moderatableComment(c *gin.Context) (*models.Comment, bool) {
+	comment, err := ctrl.Repo.GetComment(c.Param("id"))
+	if err != nil || comment.DeletedAt != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return nil, false
+	}
+
+	if !authz.CanAccess(c, authz.CommentModerate, memberRole(c, ctrl.Members, comment.BusinessID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the business's team can moderate these comments"})
+		return nil, false
+	}
+	return comment, true
+}
+
+// GetCommentHistory handles GET /comments/:id/history
+// Lists earlier versions of an edited comment, newest first. Hidden comments' history is
+// only shown to the business's moderating team and staff.
+func (ctrl *InteractionController) GetCommentHistory(c *gin.Context) {
+	comment, err := ctrl.Repo.GetComment(c.Param("id"))
+	if err != nil || comment.DeletedAt != nil || comment.IsQuarantined ||
+		(comment.IsHidden && !authz.CanAccess(c, authz.CommentModerate, memberRole(c, ctrl.Members, comment.BusinessID))) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+
+	revisions, err := ctrl.Repo.GetCommentRevisions(comment.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comment": comment, "revisions": revisions})
 }
 
 func (ctrl *InteractionController) SubmitInquiry(c *gin.Context) {
@@ -110,30 +489,36 @@ func (ctrl *InteractionController) SubmitInquiry(c *gin.Context) {
 		return
 	}
 
+	check := ctrl.Spam.Check(input.Subject, input.Message)
+
 	inquiry := models.Inquiry{
-		ID:         uuid.New(),
-		UserID:     userID,
-		BusinessID: bizID,
-		Subject:    input.Subject,
-		Message:    input.Message,
-		Status:     models.InquiryStatusPending,
+		ID:            uuid.New(),
+		UserID:        userID,
+		BusinessID:    bizID,
+		Subject:       input.Subject,
+		Message:       input.Message,
+		Status:        models.InquiryStatusPending,
+		IsQuarantined: ctrl.Spam.IsSpam(check),
 	}
 
-	if err := ctrl.Repo.AddInquiry(&inquiry); err != nil {
+	entry := ctrl.quarantineEntry(c, inquiry.IsQuarantined, models.QuarantineEntityInquiry, inquiry.ID, &userID, inquiry.Subject+"\n"+inquiry.Message, check)
+	if err := ctrl.ModerationRepo.Submit(&inquiry, entry); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send inquiry"})
 		return
 	}
 
-	// Log Activity
-	activity := models.Activity{
-		ID:         uuid.New(),
-		UserID:     &userID,
-		EntityID:   bizID,
-		EntityType: "business",
-		Type:       models.ActivityTypeInquiry,
-		CreatedAt:  time.Now(),
+	if inquiry.IsQuarantined {
+		c.JSON(http.StatusAccepted, gin.H{"message": "Inquiry submitted for review"})
+		return
 	}
-	_ = ctrl.ActivityRepo.Track(&activity)
+
+	// Subscribers log the activity and rescore; inquiries are a strong engagement signal.
+	ctrl.Events.Publish(c.Request.Context(), events.InquirySubmitted{
+		InquiryID:  inquiry.ID,
+		BusinessID: bizID,
+		UserID:     userID,
+		Subject:    inquiry.Subject,
+	})
 
 	c.JSON(http.StatusOK, gin.H{"message": "Inquiry sent successfully"})
 }
@@ -171,6 +556,8 @@ func (ctrl *InteractionController) SendChatMessage(c *gin.Context) {
 		return
 	}
 
+	ctrl.Events.Publish(c.Request.Context(), events.ChatMessagePosted{MessageID: chatMsg.ID, UserID: userID, BusinessID: bizIDPtr})
+
 	c.JSON(http.StatusOK, chatMsg)
 }
 
@@ -183,55 +570,99 @@ func (ctrl *InteractionController) GetNetworkFeed(c *gin.Context) {
 	c.JSON(http.StatusOK, messages)
 }
 
-func (ctrl *InteractionController) SubscribeNewsletter(c *gin.Context) {
+func (ctrl *InteractionController) UpdateInquiryStatus(c *gin.Context) {
+	id := c.Param("id")
 	var input struct {
-		Email string `json:"email" binding:"required,email"`
+		Status string `json:"status" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email address"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status is required"})
 		return
 	}
 
-	subscriber := models.NewsletterSubscriber{
-		ID:    uuid.New().String(),
-		Email: input.Email,
+	switch models.InquiryStatus(input.Status) {
+	case models.InquiryStatusPending, models.InquiryStatusRead, models.InquiryStatusReplied,
+		models.InquiryStatusInProgress, models.InquiryStatusClosed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown status"})
+		return
 	}
 
-	if err := ctrl.Repo.AddNewsletterSubscriber(&subscriber); err != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Subscription failed or email exists"})
+	inquiry, err := ctrl.Repo.GetInquiry(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Inquiry not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Subscribed successfully"})
-}
-
-func (ctrl *InteractionController) UpdateInquiryStatus(c *gin.Context) {
-	id := c.Param("id")
-	var input struct {
-		Status string `json:"status" binding:"required"`
-	}
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Status is required"})
+	// Only the business that received the inquiry (or staff who manage all inquiries) can move it along
+	if !authz.CanAccess(c, authz.InquiryManage, memberRole(c, ctrl.Members, inquiry.BusinessID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only manage inquiries sent to your business"})
 		return
 	}
 
-	// Optionally validate status against models.InquiryStatus constants
 	if err := ctrl.Repo.UpdateInquiryStatus(id, input.Status); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update status"})
 		return
 	}
 
+	if string(inquiry.Status) != input.Status {
+		val, _ := c.Get("user_id")
+		ctrl.Events.Publish(c.Request.Context(), events.InquiryStatusChanged{
+			InquiryID:  inquiry.ID,
+			BusinessID: inquiry.BusinessID,
+			UserID:     inquiry.UserID,
+			Status:     input.Status,
+			ChangedBy:  val.(uuid.UUID),
+		})
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Status updated"})
 }
 
 func (ctrl *InteractionController) SubmitPlatformInquiry(c *gin.Context) {
-	var inquiry models.PlatformInquiry
-	if err := c.ShouldBindJSON(&inquiry); err != nil {
+	var input struct {
+		Name    string `json:"name" binding:"required"`
+		Email   string `json:"email" binding:"required,email"`
+		Subject string `json:"subject" binding:"required"`
+		Message string `json:"message" binding:"required"`
+
+		// Honeypot: hidden from humans by the form, so only bots fill it in.
+		Website string `json:"website"`
+
+		// CaptchaToken is required only when a captcha provider is configured.
+		CaptchaToken string `json:"captcha_token"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
 		return
 	}
 
-	if err := ctrl.Repo.DB.Create(&inquiry).Error; err != nil {
+	// Pretend it worked so bots don't learn to skip the honeypot.
+	if input.Website != "" {
+		c.JSON(http.StatusOK, gin.H{"message": "Inquiry recorded successfully"})
+		return
+	}
+
+	if ctrl.Captcha != nil {
+		if err := ctrl.Captcha.Verify(input.CaptchaToken, c.ClientIP()); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Captcha verification failed"})
+			return
+		}
+	}
+
+	check := ctrl.Spam.Check(input.Subject, input.Message)
+
+	inquiry := models.PlatformInquiry{
+		ID:            uuid.New(),
+		Name:          input.Name,
+		Email:         input.Email,
+		Subject:       input.Subject,
+		Message:       input.Message,
+		IsQuarantined: ctrl.Spam.IsSpam(check),
+	}
+
+	entry := ctrl.quarantineEntry(c, inquiry.IsQuarantined, models.QuarantineEntityPlatformInquiry, inquiry.ID, nil, inquiry.Subject+"\n"+inquiry.Message, check)
+	if err := ctrl.ModerationRepo.Submit(&inquiry, entry); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record inquiry"})
 		return
 	}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidimuKennedy/spotlight-africa/internal/jobs"
+)
+
+// JobController lets admins inspect the background job queue and retry dead jobs.
+type JobController struct {
+	Queue *jobs.Queue
+}
+
+// ListJobs handles GET /admin/jobs
+// Query params: status (pending, running, done, dead), limit, offset.
+func (ctrl *JobController) ListJobs(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	list, total, err := ctrl.Queue.List(c.Query("status"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
+		return
+	}
+	counts, _ := ctrl.Queue.CountByStatus()
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":  list,
+		"total":  total,
+		"counts": counts,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// RetryJob handles POST /admin/jobs/:id/retry
+// Only dead-lettered jobs can be retried; they get a fresh set of attempts.
+func (ctrl *JobController) RetryJob(c *gin.Context) {
+	job, err := ctrl.Queue.Retry(c.Param("id"))
+	if err == jobs.ErrNotDead || err == jobs.ErrAlreadyQueued {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Job queued for retry", "job": job})
+}
@@ -0,0 +1,198 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/mail"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+)
+
+var (
+	accountBackoff = repository.LoginBackoff{Free: 3, Base: time.Second, Max: 15 * time.Minute, Window: 24 * time.Hour}
+	ipBackoff      = repository.LoginBackoff{Free: 20, Base: time.Second, Max: time.Hour, Window: time.Hour}
+)
+
+// After this many failures in a row an account is locked and its owner emailed, at most
+// once per accountLockNotifyEvery however often it is locked again.
+const (
+	accountLockThreshold   = 10
+	accountLockDuration    = 30 * time.Minute
+	accountLockNotifyEvery = 24 * time.Hour
+)
+
+// LoginGuard throttles password guessing. Failed logins count against both the account
+// and the IP address they came from, and every attempt is kept for the audit trail.
+type LoginGuard struct {
+	Attempts *repository.LoginAttemptRepository
+	Tokens   *repository.UserTokenRepository
+	Outbox   *mail.Outbox
+}
+
+func accountKey(email string) string { return "account:" + strings.ToLower(strings.TrimSpace(email)) }
+func ipKey(ip string) string         { return "ip:" + ip }
+
+// Check answers the request with 429 and returns false when the account or the client's
+// IP address has to wait before trying again. Otherwise it counts the attempt as a failed
+// one up front, so parallel guesses can't all get in before the first is counted; the
+// caller then settles it with Failed, Succeeded or Release.
+func (g *LoginGuard) Check(c *gin.Context, email, method string) bool {
+	now := time.Now()
+	ip, allowed, err := g.Attempts.Charge(ipKey(c.ClientIP()), ipBackoff)
+	if err != nil {
+		log.Println("⚠️ Could not check login throttle:", err)
+		return true
+	}
+	if !allowed {
+		g.refuse(c, email, method, ipBackoff.Wait(ip, now), "Too many failed sign-ins, please wait before trying again")
+		return false
+	}
+
+	account, allowed, err := g.Attempts.Charge(accountKey(email), accountBackoff)
+	if err != nil {
+		log.Println("⚠️ Could not check login throttle:", err)
+		return true
+	}
+	if allowed {
+		return true
+	}
+	if err := g.Attempts.Refund(ipKey(c.ClientIP())); err != nil {
+		log.Println("⚠️ Could not reset login throttle:", err)
+	}
+	if account.LockedUntil != nil && account.LockedUntil.After(now) {
+		g.refuse(c, email, method, account.LockedUntil.Sub(now),
+			"This account is temporarily locked after too many failed sign-ins. Check your email, or reset your password to unlock it.")
+	} else {
+		g.refuse(c, email, method, accountBackoff.Wait(account, now), "Too many failed sign-ins, please wait before trying again")
+	}
+	return false
+}
+
+func (g *LoginGuard) refuse(c *gin.Context, email, method string, wait time.Duration, message string) {
+	g.Record(c, email, nil, method, models.LoginThrottled)
+	seconds := int(math.Ceil(wait.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": message, "code": "login_throttled", "retry_after": seconds})
+}
+
+// Failed records a failed attempt, which Check already counted against the account and
+// the IP address. user is nil when no account has the email. Reaching the threshold locks
+// the account and tells its owner, with a link to reset the password.
+func (g *LoginGuard) Failed(c *gin.Context, email string, user *models.User, method, outcome string) {
+	g.Record(c, email, user, method, outcome)
+	key := accountKey(email)
+	locked, err := g.Attempts.Lock(key, accountLockThreshold, time.Now().Add(accountLockDuration))
+	if err != nil {
+		log.Println("⚠️ Could not lock account:", err)
+		return
+	}
+	// Accounts that don't exist are locked too, so lockouts don't reveal which emails are registered
+	if !locked || user == nil {
+		return
+	}
+	notify, err := g.Attempts.Notify(key, accountLockNotifyEvery)
+	if err != nil {
+		log.Println("⚠️ Could not send account locked email:", err)
+		return
+	}
+	if !notify {
+		return
+	}
+	if err := g.notifyLocked(c, user); err != nil {
+		log.Println("⚠️ Could not send account locked email:", err)
+	}
+}
+
+func (g *LoginGuard) notifyLocked(c *gin.Context, user *models.User) error {
+	token, err := g.Tokens.Issue(user.ID, models.TokenPurposePasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+	return g.Outbox.Queue(nil, user.Email, "account_locked", user.Locale, map[string]interface{}{
+		"Name":     user.DisplayName(),
+		"Minutes":  fmt.Sprint(int(accountLockDuration.Minutes())),
+		"IP":       c.ClientIP(),
+		"ResetURL": mail.Link("/reset-password?token=" + url.QueryEscape(token)),
+	})
+}
+
+// Succeeded records a successful sign-in and forgets the account's failures. The IP
+// address keeps its earlier count, so one working password doesn't buy more guesses at
+// others; only this attempt is given back.
+func (g *LoginGuard) Succeeded(c *gin.Context, email string, user *models.User, method string) {
+	g.Record(c, email, user, method, models.LoginSucceeded)
+	if err := g.Attempts.Clear(accountKey(email)); err != nil {
+		log.Println("⚠️ Could not reset login throttle:", err)
+	}
+	if err := g.Attempts.Refund(ipKey(c.ClientIP())); err != nil {
+		log.Println("⚠️ Could not reset login throttle:", err)
+	}
+}
+
+// Release gives back the attempt Check counted when it ended without a wrong password or
+// code, e.g. the server was busy or the password was right and a second factor is next.
+func (g *LoginGuard) Release(c *gin.Context, email string) {
+	for _, key := range []string{accountKey(email), ipKey(c.ClientIP())} {
+		if err := g.Attempts.Refund(key); err != nil {
+			log.Println("⚠️ Could not reset login throttle:", err)
+		}
+	}
+}
+
+// Unlock forgets the account's failures, lock included.
+func (g *LoginGuard) Unlock(email string) error {
+	return g.Attempts.Clear(accountKey(email))
+}
+
+// Record adds an attempt to the audit trail without counting it either way.
+func (g *LoginGuard) Record(c *gin.Context, email string, user *models.User, method, outcome string) {
+	attempt := &models.LoginAttempt{
+		Email:     strings.ToLower(strings.TrimSpace(email)),
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Method:    method,
+		Outcome:   outcome,
+		Success:   outcome == models.LoginSucceeded,
+	}
+	if user != nil {
+		attempt.UserID = &user.ID
+	}
+	if err := g.Attempts.Record(attempt); err != nil {
+		log.Println("⚠️ Could not record login attempt:", err)
+	}
+}
+
+// ListAttempts handles GET /admin/login-attempts
+// Filters: ?email=, ?ip=, ?user_id=, ?outcome=. Newest first.
+func (g *LoginGuard) ListAttempts(c *gin.Context) {
+	limit, offset := emailPage(c)
+	filter := repository.LoginAttemptFilter{
+		Email:   c.Query("email"),
+		IP:      c.Query("ip"),
+		Outcome: c.Query("outcome"),
+	}
+	if raw := c.Query("user_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		filter.UserID = &id
+	}
+
+	list, total, err := g.Attempts.List(filter, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch login attempts"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": list, "total": total, "limit": limit, "offset": offset})
+}
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/authz"
+	"github.com/saidimuKennedy/spotlight-africa/internal/events"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+)
+
+// MeetingController lets users book meetings with a business and the business manage them.
+type MeetingController struct {
+	Repo    *repository.MeetingRepository
+	BizRepo *repository.BusinessRepository
+	Members *repository.BusinessMemberRepository
+	Events  *events.Bus
+}
+
+// BookMeeting handles POST /businesses/:id/meetings
+// Body: {"title": "...", "description": "...", "start_time": RFC3339, "end_time": RFC3339, "meeting_link": "..."}
+func (ctrl *MeetingController) BookMeeting(c *gin.Context) {
+	business, err := ctrl.BizRepo.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Business not found"})
+		return
+	}
+
+	var input struct {
+		Title       string    `json:"title" binding:"required"`
+		Description string    `json:"description"`
+		StartTime   time.Time `json:"start_time" binding:"required"`
+		EndTime     time.Time `json:"end_time" binding:"required"`
+		MeetingLink string    `json:"meeting_link"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Title, start_time and end_time are required"})
+		return
+	}
+	if !input.StartTime.After(time.Now()) || !input.EndTime.After(input.StartTime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meetings must start in the future and end after they start"})
+		return
+	}
+	// The link is shown to the business and emailed, so only allow web links
+	if input.MeetingLink != "" {
+		if u, err := url.Parse(input.MeetingLink); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Meeting link must be an http or https URL"})
+			return
+		}
+	}
+
+	val, _ := c.Get("user_id")
+	userID := val.(uuid.UUID)
+	if ctrl.Members.Role(business.ID, userID) != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You can't book a meeting with your own business"})
+		return
+	}
+
+	meeting := models.Meeting{
+		BusinessID:  business.ID,
+		UserID:      userID,
+		Title:       input.Title,
+		Description: input.Description,
+		StartTime:   input.StartTime,
+		EndTime:     input.EndTime,
+		Status:      models.MeetingStatusScheduled,
+		MeetingLink: input.MeetingLink,
+	}
+	if err := ctrl.Repo.Create(&meeting); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not book meeting"})
+		return
+	}
+
+	ctrl.Events.Publish(c.Request.Context(), events.MeetingBooked{
+		MeetingID:  meeting.ID,
+		BusinessID: meeting.BusinessID,
+		UserID:     userID,
+		Title:      meeting.Title,
+		StartTime:  meeting.StartTime,
+	})
+
+	c.JSON(http.StatusCreated, meeting)
+}
+
+// UpdateMeetingStatus handles PATCH /meetings/:id/status
+// Body: {"status": "completed"|"cancelled"}. The business's owner or managers (or an admin) can set either;
+// the person who booked can only cancel.
+func (ctrl *MeetingController) UpdateMeetingStatus(c *gin.Context) {
+	var input struct {
+		Status models.MeetingStatus `json:"status" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil ||
+		(input.Status != models.MeetingStatusCompleted && input.Status != models.MeetingStatusCancelled) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be completed or cancelled"})
+		return
+	}
+
+	meeting, err := ctrl.Repo.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return
+	}
+
+	val, _ := c.Get("user_id")
+	userID := val.(uuid.UUID)
+
+	isOwner := authz.Can(c, authz.MeetingManage)
+	if business, err := ctrl.BizRepo.GetByID(meeting.BusinessID.String()); err == nil {
+		isOwner = authz.CanAccess(c, authz.MeetingManage, memberRole(c, ctrl.Members, business.ID))
+	}
+	isBooker := meeting.UserID == userID && input.Status == models.MeetingStatusCancelled
+	if !isOwner && !isBooker {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can't change this meeting"})
+		return
+	}
+
+	if meeting.Status == input.Status {
+		c.JSON(http.StatusOK, meeting)
+		return
+	}
+	if err := ctrl.Repo.UpdateStatus(meeting.ID.String(), string(input.Status)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update meeting"})
+		return
+	}
+	meeting.Status = input.Status
+
+	ctrl.Events.Publish(c.Request.Context(), events.MeetingStatusChanged{
+		MeetingID:  meeting.ID,
+		BusinessID: meeting.BusinessID,
+		UserID:     meeting.UserID,
+		Status:     string(meeting.Status),
+		ChangedBy:  userID,
+	})
+
+	c.JSON(http.StatusOK, meeting)
+}
@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/events"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+)
+
+// ModerationController exposes the admin review queue for content held back by the spam checks.
+type ModerationController struct {
+	Repo      *repository.ModerationRepository
+	InterRepo *repository.InteractionRepository
+	Events    *events.Bus
+}
+
+// ListQuarantine handles GET /admin/quarantine
+// Query params: status (default "pending"), type, limit, offset.
+func (ctrl *ModerationController) ListQuarantine(c *gin.Context) {
+	status := c.DefaultQuery("status", string(models.QuarantineStatusPending))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	entries, total, err := ctrl.Repo.ListQuarantine(status, c.Query("type"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch quarantine queue"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":  entries,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ApproveQuarantine handles POST /admin/quarantine/:id/approve
+func (ctrl *ModerationController) ApproveQuarantine(c *gin.Context) {
+	entry, ok := ctrl.pendingEntry(c)
+	if !ok {
+		return
+	}
+
+	val, _ := c.Get("user_id")
+	err := ctrl.Repo.Approve(entry, val.(uuid.UUID))
+	if errors.Is(err, repository.ErrQuarantinedContentMissing) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "The held-back content no longer exists"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to approve content"})
+		return
+	}
+
+	// Approved content is published as if it had just been posted. Held-back edits were
+	// announced when first posted.
+	switch entry.EntityType {
+	case models.QuarantineEntityComment:
+		if entry.IsEdit {
+			break
+		}
+		if comment, err := ctrl.InterRepo.GetComment(entry.EntityID.String()); err == nil {
+			ctrl.Events.Publish(c.Request.Context(), events.CommentAdded{
+				CommentID:  comment.ID,
+				BusinessID: comment.BusinessID,
+				UserID:     comment.UserID,
+				ParentID:   comment.ParentID,
+			})
+		}
+	case models.QuarantineEntityInquiry:
+		if inquiry, err := ctrl.InterRepo.GetInquiry(entry.EntityID.String()); err == nil {
+			ctrl.Events.Publish(c.Request.Context(), events.InquirySubmitted{
+				InquiryID:  inquiry.ID,
+				BusinessID: inquiry.BusinessID,
+				UserID:     inquiry.UserID,
+				Subject:    inquiry.Subject,
+			})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Content approved", "entry": entry})
+}
+
+// RejectQuarantine handles POST /admin/quarantine/:id/reject
+// The underlying content is deleted.
+func (ctrl *ModerationController) RejectQuarantine(c *gin.Context) {
+	entry, ok := ctrl.pendingEntry(c)
+	if !ok {
+		return
+	}
+
+	val, _ := c.Get("user_id")
+	err := ctrl.Repo.Reject(entry, val.(uuid.UUID))
+	if errors.Is(err, repository.ErrQuarantinedContentMissing) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "The held-back content no longer exists"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reject content"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Content rejected", "entry": entry})
+}
+
+// pendingEntry loads the entry from the URL and makes sure it hasn't been reviewed yet.
+func (ctrl *ModerationController) pendingEntry(c *gin.Context) (*models.QuarantineEntry, bool) {
+	entry, err := ctrl.Repo.GetQuarantineEntry(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Quarantine entry not found"})
+		return nil, false
+	}
+	if entry.Status != models.QuarantineStatusPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Entry has already been reviewed"})
+		return nil, false
+	}
+	return entry, true
+}
+
+// ListReportedComments handles GET /admin/moderation/reports
+// Comments with open reports, most reported first.
+func (ctrl *ModerationController) ListReportedComments(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	items, total, err := ctrl.Repo.ListReportedComments(limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch moderation queue"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":  items,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetCommentReports handles GET /admin/moderation/comments/:id/reports
+func (ctrl *ModerationController) GetCommentReports(c *gin.Context) {
+	reports, err := ctrl.Repo.GetReportsForComment(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reports"})
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+// BulkModerate handles POST /admin/moderation/bulk
+// Body: {"comment_ids": [...], "action": "hide|unhide|delete|dismiss"}
+func (ctrl *ModerationController) BulkModerate(c *gin.Context) {
+	var input struct {
+		CommentIDs []uuid.UUID `json:"comment_ids" binding:"required,min=1"`
+		Action     string      `json:"action" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "comment_ids and action are required"})
+		return
+	}
+
+	val, _ := c.Get("user_id")
+	bizIDs, err := ctrl.Repo.ApplyBulkAction(input.CommentIDs, input.Action, val.(uuid.UUID))
+	if err == repository.ErrUnknownModerationAction {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Action must be one of hide, unhide, delete, dismiss"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply moderation action"})
+		return
+	}
+
+	for _, bizID := range bizIDs {
+		ctrl.Events.Publish(c.Request.Context(), events.CommentsModerated{BusinessID: bizID, Action: input.Action})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Moderation action applied", "count": len(input.CommentIDs)})
+}
+
+// BanUser handles POST /admin/users/:id/ban
+// Body: {"reason": "...", "days": 7}. Omit days (or 0) for a permanent ban.
+func (ctrl *ModerationController) BanUser(c *gin.Context) {
+	var input struct {
+		Reason string `json:"reason" binding:"required"`
+		Days   int    `json:"days"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || input.Days < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A reason is required"})
+		return
+	}
+
+	var until *time.Time
+	if input.Days > 0 {
+		t := time.Now().AddDate(0, 0, input.Days)
+		until = &t
+	}
+
+	if err := ctrl.Repo.BanUser(c.Param("id"), input.Reason, until); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User banned", "banned_until": until})
+}
+
+// UnbanUser handles DELETE /admin/users/:id/ban
+func (ctrl *ModerationController) UnbanUser(c *gin.Context) {
+	if err := ctrl.Repo.UnbanUser(c.Param("id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to lift ban"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Ban lifted"})
+}
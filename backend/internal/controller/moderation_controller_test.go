@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/database/dbtest"
+	"github.com/saidimuKennedy/spotlight-africa/internal/events"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+	"github.com/saidimuKennedy/spotlight-africa/internal/spam"
+)
+
+// signedInAs stands in for the auth middleware.
+func signedInAs(id uuid.UUID) gin.HandlerFunc {
+	return func(c *gin.Context) { c.Set("user_id", id) }
+}
+
+func serve(t *testing.T, r *gin.Engine, method, path, body string, out interface{}) int {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if out != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: decode %q: %v", method, path, w.Body.String(), err)
+		}
+	}
+	return w.Code
+}
+
+func TestHeldCommentIsPublishedOnApproval(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := dbtest.Open(t, &models.User{}, &models.Business{}, &models.Comment{}, &models.QuarantineEntry{})
+	author := models.User{ID: uuid.New(), Email: "author@example.com", Password: "x"}
+	admin := models.User{ID: uuid.New(), Email: "admin@example.com", Password: "x", Role: "admin"}
+	for _, u := range []*models.User{&author, &admin} {
+		if err := db.Create(u).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+	biz := models.Business{Name: "Duka", OwnerID: admin.ID}
+	if err := db.Create(&biz).Error; err != nil {
+		t.Fatalf("create business: %v", err)
+	}
+
+	bus := events.NewBus()
+	var announced []events.CommentAdded
+	bus.Subscribe(events.CommentAdded{}.EventName(), func(ctx context.Context, e events.Event) error {
+		announced = append(announced, e.(events.CommentAdded))
+		return nil
+	})
+	modRepo := &repository.ModerationRepository{DB: db}
+	interRepo := &repository.InteractionRepository{DB: db}
+	inter := &InteractionController{
+		Repo:           interRepo,
+		Events:         bus,
+		ModerationRepo: modRepo,
+		Spam:           &spam.Analyzer{Threshold: spam.DefaultThreshold, Blocklist: []string{"casino", "free money"}},
+	}
+	mod := &ModerationController{Repo: modRepo, InterRepo: interRepo, Events: bus}
+
+	r := gin.New()
+	r.GET("/businesses/:id/comments", inter.GetComments)
+	r.POST("/businesses/:id/comment", signedInAs(author.ID), inter.AddComment)
+	r.GET("/admin/quarantine", signedInAs(admin.ID), mod.ListQuarantine)
+	r.POST("/admin/quarantine/:id/approve", signedInAs(admin.ID), mod.ApproveQuarantine)
+
+	commentsPath := "/businesses/" + biz.ID.String() + "/comments"
+	var thread struct {
+		Comments []models.Comment `json:"comments"`
+		Total    int64            `json:"total"`
+	}
+
+	if code := serve(t, r, "POST", "/businesses/"+biz.ID.String()+"/comment", `{"content":"Free money at the casino"}`, nil); code != http.StatusAccepted {
+		t.Fatalf("posting spam: status %d, want 202", code)
+	}
+	if serve(t, r, "GET", commentsPath, "", &thread); thread.Total != 0 {
+		t.Fatalf("a held comment is public before review: %+v", thread.Comments)
+	}
+
+	var queue struct {
+		Items []models.QuarantineEntry `json:"items"`
+	}
+	serve(t, r, "GET", "/admin/quarantine", "", &queue)
+	if len(queue.Items) != 1 {
+		t.Fatalf("quarantine has %d entries, want 1", len(queue.Items))
+	}
+	if code := serve(t, r, "POST", "/admin/quarantine/"+queue.Items[0].ID.String()+"/approve", "", nil); code != http.StatusOK {
+		t.Fatalf("approving: status %d, want 200", code)
+	}
+
+	serve(t, r, "GET", commentsPath, "", &thread)
+	if thread.Total != 1 || thread.Comments[0].Content != "Free money at the casino" {
+		t.Fatalf("approved comment is not public: %+v", thread)
+	}
+	if len(announced) != 1 || announced[0].CommentID != thread.Comments[0].ID {
+		t.Fatalf("CommentAdded events = %+v, want one for the approved comment", announced)
+	}
+}
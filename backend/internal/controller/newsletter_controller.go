@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidimuKennedy/spotlight-africa/internal/events"
+	"github.com/saidimuKennedy/spotlight-africa/internal/mail"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/newsletter"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+	"gorm.io/gorm"
+)
+
+// NewsletterController runs the newsletter list: double opt-in sign-up, topic
+// preferences and unsubscribe. Subscribers are identified by signed links, not accounts.
+type NewsletterController struct {
+	Repo   *repository.NewsletterRepository
+	Outbox *mail.Outbox
+	Events *events.Bus
+}
+
+// Subscribe handles POST /newsletter/subscribe
+// It always answers the same way so the endpoint can't be used to probe the list.
+func (ctrl *NewsletterController) Subscribe(c *gin.Context) {
+	var input struct {
+		Email  string   `json:"email" binding:"required,email"`
+		Topics []string `json:"topics"`
+		Locale string   `json:"locale" binding:"omitempty,oneof=en fr"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email address"})
+		return
+	}
+	if !models.ValidNewsletterTopics(input.Topics) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown newsletter topic"})
+		return
+	}
+
+	sub, confirmed, err := ctrl.Repo.Subscribe(input.Email, input.Locale, input.Topics)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Subscription failed"})
+		return
+	}
+	if !confirmed {
+		err := ctrl.Outbox.Queue(nil, sub.Email, "newsletter_confirm", sub.Locale, map[string]interface{}{
+			"Email":      sub.Email,
+			"ConfirmURL": newsletter.ConfirmURL(sub.Email),
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not send confirmation email"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Check your inbox to confirm your subscription"})
+}
+
+// Confirm handles POST /newsletter/confirm
+// Body: {"token": "..."} from the confirmation email. Returns a manage token so the
+// page can show the subscriber's preferences straight away.
+func (ctrl *NewsletterController) Confirm(c *gin.Context) {
+	var input struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token is required"})
+		return
+	}
+	email, err := newsletter.ParseConfirmToken(input.Token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This confirmation link is invalid or has expired"})
+		return
+	}
+
+	sub, firstTime, err := ctrl.Repo.Confirm(email)
+	if err == gorm.ErrRecordNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "This address has unsubscribed. Sign up again to rejoin."})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not confirm subscription"})
+		return
+	}
+	if firstTime {
+		ctrl.Events.Publish(c.Request.Context(), events.NewsletterSubscribed{Email: sub.Email, Locale: sub.Locale, Topics: sub.Topics})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Subscription confirmed",
+		"subscriber": sub,
+		"token":      newsletter.ManageToken(sub.Email),
+	})
+}
+
+// GetPreferences handles GET /newsletter/preferences?token=
+func (ctrl *NewsletterController) GetPreferences(c *gin.Context) {
+	sub, ok := ctrl.subscriberFromToken(c, c.Query("token"))
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"subscriber": sub, "topics": models.NewsletterTopics})
+}
+
+// UpdatePreferences handles PUT /newsletter/preferences
+// Body: {"token": "...", "topics": ["events", "news"]}. At least one topic is required;
+// to stop receiving everything, unsubscribe.
+func (ctrl *NewsletterController) UpdatePreferences(c *gin.Context) {
+	var input struct {
+		Token  string   `json:"token" binding:"required"`
+		Topics []string `json:"topics" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pick at least one topic, or unsubscribe"})
+		return
+	}
+	if !models.ValidNewsletterTopics(input.Topics) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown newsletter topic"})
+		return
+	}
+
+	sub, ok := ctrl.subscriberFromToken(c, input.Token)
+	if !ok {
+		return
+	}
+	if err := ctrl.Repo.UpdateTopics(sub, input.Topics); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save preferences"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"subscriber": sub})
+}
+
+// Unsubscribe handles POST /newsletter/unsubscribe?token=
+// This is the RFC 8058 one-click endpoint named in List-Unsubscribe: mail clients POST
+// "List-Unsubscribe=One-Click" to it with no cookies or auth, so the token alone decides.
+// The web app may send the token in a JSON body instead. Unsubscribing twice is not an error.
+func (ctrl *NewsletterController) Unsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		var input struct {
+			Token string `json:"token"`
+		}
+		_ = c.ShouldBindJSON(&input)
+		token = input.Token
+	}
+
+	email, err := newsletter.ParseManageToken(token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unsubscribe link"})
+		return
+	}
+	if _, err := ctrl.Repo.Unsubscribe(email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not unsubscribe"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "You have been unsubscribed"})
+}
+
+// UnsubscribePage handles GET /newsletter/unsubscribe?token=
+// Opening the List-Unsubscribe URL in a browser must not unsubscribe (link scanners
+// prefetch it), so it redirects to the web app, which asks for confirmation.
+func (ctrl *NewsletterController) UnsubscribePage(c *gin.Context) {
+	c.Redirect(http.StatusSeeOther, mail.Link("/newsletter?action=unsubscribe&token="+url.QueryEscape(c.Query("token"))))
+}
+
+// subscriberFromToken resolves a manage token, writing the error response if it fails.
+func (ctrl *NewsletterController) subscriberFromToken(c *gin.Context, token string) (*models.NewsletterSubscriber, bool) {
+	email, err := newsletter.ParseManageToken(token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired link"})
+		return nil, false
+	}
+	sub, err := ctrl.Repo.Get(email)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "This address is not subscribed"})
+		return nil, false
+	}
+	return sub, true
+}
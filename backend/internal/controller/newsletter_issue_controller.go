@@ -0,0 +1,255 @@
+package controller
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/newsletter"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+)
+
+// NewsletterIssueController lets admins compose, preview and schedule newsletter issues,
+// and serves the open and click tracking endpoints used in sent issues.
+type NewsletterIssueController struct {
+	Repo      *repository.NewsletterIssueRepository
+	Campaigns *newsletter.Campaigns
+}
+
+// ComposeIssue handles POST /admin/newsletter/issues
+// Body (optional): {"since_days": 7}. Drafts an issue from content published in that window.
+func (ctrl *NewsletterIssueController) ComposeIssue(c *gin.Context) {
+	var input struct {
+		SinceDays int `json:"since_days"`
+	}
+	_ = c.ShouldBindJSON(&input)
+	if input.SinceDays <= 0 || input.SinceDays > 31 {
+		input.SinceDays = 7
+	}
+
+	val, _ := c.Get("user_id")
+	issue, err := ctrl.Campaigns.Compose(val.(uuid.UUID), time.Now().AddDate(0, 0, -input.SinceDays))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not compose issue"})
+		return
+	}
+	c.JSON(http.StatusCreated, issue)
+}
+
+// ListIssues handles GET /admin/newsletter/issues
+// Query params: status (draft, scheduled, sending, sent, cancelled), limit, offset.
+func (ctrl *NewsletterIssueController) ListIssues(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	list, total, err := ctrl.Repo.List(c.Query("status"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch issues"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": list, "total": total, "limit": limit, "offset": offset})
+}
+
+// GetIssue handles GET /admin/newsletter/issues/:id
+func (ctrl *NewsletterIssueController) GetIssue(c *gin.Context) {
+	issue, err := ctrl.Repo.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return
+	}
+	c.JSON(http.StatusOK, issue)
+}
+
+// UpdateIssue handles PUT /admin/newsletter/issues/:id
+// Body: {"subject": "...", "intro": "...", "sections": [...]}. Only drafts and scheduled issues can change.
+func (ctrl *NewsletterIssueController) UpdateIssue(c *gin.Context) {
+	issue, err := ctrl.Repo.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return
+	}
+	if !issue.Editable() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Issues can't be edited once sending has started"})
+		return
+	}
+
+	var input struct {
+		Subject  string                `json:"subject" binding:"required,max=255"`
+		Intro    string                `json:"intro"`
+		Sections []models.IssueSection `json:"sections"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Subject is required"})
+		return
+	}
+	for _, s := range input.Sections {
+		if !models.ValidNewsletterTopics([]string{s.Topic}) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown topic in section " + s.Title})
+			return
+		}
+		for _, item := range s.Items {
+			if item.Title == "" || item.URL == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Every item needs a title and a URL"})
+				return
+			}
+		}
+	}
+
+	issue.Subject, issue.Intro, issue.Sections = input.Subject, input.Intro, input.Sections
+	if err := ctrl.Repo.SaveContent(issue); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save issue"})
+		return
+	}
+	c.JSON(http.StatusOK, issue)
+}
+
+// DeleteIssue handles DELETE /admin/newsletter/issues/:id (drafts only)
+func (ctrl *NewsletterIssueController) DeleteIssue(c *gin.Context) {
+	deleted, err := ctrl.Repo.Delete(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete issue"})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only drafts can be deleted"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Issue deleted"})
+}
+
+// PreviewIssue handles GET /admin/newsletter/issues/:id/preview?format=html|text
+// Renders the issue as a subscriber following every topic sees it, without tracking.
+func (ctrl *NewsletterIssueController) PreviewIssue(c *gin.Context) {
+	issue, err := ctrl.Repo.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return
+	}
+	msg, err := ctrl.Campaigns.Preview(issue, "preview@example.com")
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("format") == "text" {
+		c.String(http.StatusOK, "Subject: %s\n\n%s", msg.Subject, msg.Text)
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(msg.HTML))
+}
+
+// SendTestIssue handles POST /admin/newsletter/issues/:id/test
+// Body: {"email": "..."}. Sends an untracked copy marked [Test].
+func (ctrl *NewsletterIssueController) SendTestIssue(c *gin.Context) {
+	issue, err := ctrl.Repo.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return
+	}
+	var input struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A valid email is required"})
+		return
+	}
+	if err := ctrl.Campaigns.SendTest(issue, input.Email); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Test email queued"})
+}
+
+// ScheduleIssue handles POST /admin/newsletter/issues/:id/schedule
+// Body (optional): {"send_at": RFC3339}. Omit send_at to send now.
+func (ctrl *NewsletterIssueController) ScheduleIssue(c *gin.Context) {
+	var input struct {
+		SendAt *time.Time `json:"send_at"`
+	}
+	_ = c.ShouldBindJSON(&input)
+	at := time.Now()
+	if input.SendAt != nil && input.SendAt.After(at) {
+		at = *input.SendAt
+	}
+
+	issue, err := ctrl.Repo.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return
+	}
+	if len(issue.Topics()) == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "The issue has no content yet"})
+		return
+	}
+
+	ok, err := ctrl.Campaigns.Schedule(issue.ID.String(), at)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not schedule issue"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusConflict, gin.H{"error": "The issue has already been sent or is sending"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Issue scheduled", "scheduled_at": at})
+}
+
+// CancelIssue handles POST /admin/newsletter/issues/:id/cancel
+// A scheduled issue goes back to draft; one that is sending stops after the current batch.
+func (ctrl *NewsletterIssueController) CancelIssue(c *gin.Context) {
+	ok, err := ctrl.Repo.Cancel(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not cancel issue"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only scheduled or sending issues can be cancelled"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Issue cancelled"})
+}
+
+// GetIssueStats handles GET /admin/newsletter/issues/:id/stats
+func (ctrl *NewsletterIssueController) GetIssueStats(c *gin.Context) {
+	stats, err := ctrl.Repo.Stats(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stats"})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// transparentGIF is the 1x1 tracking pixel.
+var transparentGIF, _ = base64.StdEncoding.DecodeString("R0lGODlhAQABAIAAAAAAAP///yH5BAEAAAAALAAAAAABAAEAAAIBRAA7")
+
+// TrackOpen handles GET /newsletter/o/:token
+// Always returns the pixel; a bad token just isn't recorded.
+func (ctrl *NewsletterIssueController) TrackOpen(c *gin.Context) {
+	if id, err := newsletter.ParseOpenToken(c.Param("token")); err == nil {
+		if d, err := ctrl.Repo.GetDelivery(id); err == nil {
+			_ = ctrl.Repo.RecordOpen(d)
+		}
+	}
+	c.Header("Cache-Control", "no-store, max-age=0")
+	c.Data(http.StatusOK, "image/gif", transparentGIF)
+}
+
+// TrackClick handles GET /newsletter/c/:token
+// Records the click and redirects to the link signed into the token.
+func (ctrl *NewsletterIssueController) TrackClick(c *gin.Context) {
+	id, target, err := newsletter.ParseClickToken(c.Param("token"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid link"})
+		return
+	}
+	if d, err := ctrl.Repo.GetDelivery(id); err == nil {
+		_ = ctrl.Repo.RecordClick(d, target)
+	}
+	c.Redirect(http.StatusFound, target)
+}
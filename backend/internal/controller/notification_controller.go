@@ -1,46 +1,239 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
 	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
 )
 
 type NotificationController struct {
-	Repo *repository.NotificationRepository
+	Repo     *repository.NotificationRepository
+	PrefRepo *repository.NotificationPreferenceRepository
 }
 
+// GetUserNotifications handles GET /notifications
+// Query params: type (comma separated), read (true/false), archived (true lists the archive),
+// cursor (next_cursor from the previous page), limit (default 20, max 100).
 func (ctrl *NotificationController) GetUserNotifications(c *gin.Context) {
 	val, _ := c.Get("user_id")
 	userID := val.(uuid.UUID)
 
-	notifications, err := ctrl.Repo.GetByUserID(userID.String())
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	filter := repository.NotificationFilter{
+		Archived: c.Query("archived") == "true",
+		Cursor:   c.Query("cursor"),
+		Limit:    limit,
+	}
+	if types := c.Query("type"); types != "" {
+		filter.Types = strings.Split(types, ",")
+	}
+	if read, err := strconv.ParseBool(c.Query("read")); err == nil {
+		filter.Read = &read
+	}
+
+	notifications, next, err := ctrl.Repo.List(userID, filter)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
 		return
 	}
+	counts, _ := ctrl.Repo.UnreadCounts(userID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":        notifications,
+		"next_cursor":  next,
+		"unread_count": sumCounts(counts),
+	})
+}
+
+// GetUnreadCount handles GET /notifications/unread-count
+func (ctrl *NotificationController) GetUnreadCount(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	counts, err := ctrl.Repo.UnreadCounts(val.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"total": sumCounts(counts), "by_type": counts})
+}
 
-	c.JSON(http.StatusOK, notifications)
+func sumCounts(counts map[string]int64) int64 {
+	var total int64
+	for _, n := range counts {
+		total += n
+	}
+	return total
 }
 
+// MarkRead handles PATCH /notifications/:id/read
 func (ctrl *NotificationController) MarkRead(c *gin.Context) {
-	id := c.Param("id")
-	if err := ctrl.Repo.MarkAsRead(id); err != nil {
+	val, _ := c.Get("user_id")
+	found, err := ctrl.Repo.MarkAsRead(c.Param("id"), val.(uuid.UUID))
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
 		return
 	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{"message": "Marked as read"})
 }
 
+// MarkAllRead handles PATCH /notifications/read-all
+// Query params: type (optional) to only clear one kind of notification.
 func (ctrl *NotificationController) MarkAllRead(c *gin.Context) {
 	val, _ := c.Get("user_id")
 	userID := val.(uuid.UUID)
 
-	if err := ctrl.Repo.MarkAllAsRead(userID.String()); err != nil {
+	if err := ctrl.Repo.MarkAllAsRead(userID, c.Query("type")); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications"})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "All marked as read"})
 }
+
+// ArchiveNotification handles PATCH /notifications/:id/archive
+// Body: {"archived": true}. Send false to move it back to the inbox.
+func (ctrl *NotificationController) ArchiveNotification(c *gin.Context) {
+	var input struct {
+		Archived *bool `json:"archived" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "archived is required"})
+		return
+	}
+
+	val, _ := c.Get("user_id")
+	found, err := ctrl.Repo.SetArchived(c.Param("id"), val.(uuid.UUID), *input.Archived)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notification updated", "archived": *input.Archived})
+}
+
+// DeleteNotification handles DELETE /notifications/:id
+func (ctrl *NotificationController) DeleteNotification(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	found, err := ctrl.Repo.Delete(c.Param("id"), val.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete notification"})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notification deleted"})
+}
+
+// GetPreferences handles GET /me/notification-preferences
+// Returns quiet hours, digest schedule and the full type x channel matrix (defaults included).
+func (ctrl *NotificationController) GetPreferences(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uuid.UUID)
+
+	settings, err := ctrl.PrefRepo.GetSettings(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch preferences"})
+		return
+	}
+	prefs, err := ctrl.PrefRepo.GetPreferences(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"settings":    settings,
+		"preferences": prefs,
+		"types":       models.NotificationTypes,
+		"channels":    models.NotificationChannels,
+	})
+}
+
+// UpdatePreferences handles PUT /me/notification-preferences
+// Body: {"quiet_hours_start": "22:00", "quiet_hours_end": "07:00", "timezone": "Africa/Lagos",
+// "digest_frequency": "daily", "preferences": [{"type": "like", "channel": "email", "enabled": false}]}
+// Only the preferences listed are changed; empty quiet hours turn them off.
+func (ctrl *NotificationController) UpdatePreferences(c *gin.Context) {
+	var input struct {
+		QuietHoursStart string                          `json:"quiet_hours_start"`
+		QuietHoursEnd   string                          `json:"quiet_hours_end"`
+		Timezone        string                          `json:"timezone"`
+		DigestFrequency string                          `json:"digest_frequency"`
+		Preferences     []models.NotificationPreference `json:"preferences"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	val, _ := c.Get("user_id")
+	settings, err := ctrl.PrefRepo.GetSettings(val.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch preferences"})
+		return
+	}
+
+	if (input.QuietHoursStart == "") != (input.QuietHoursEnd == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Set both quiet_hours_start and quiet_hours_end, or neither"})
+		return
+	}
+	for _, clock := range []string{input.QuietHoursStart, input.QuietHoursEnd} {
+		if _, err := models.ParseClock(clock); clock != "" && err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	settings.QuietHoursStart, settings.QuietHoursEnd = input.QuietHoursStart, input.QuietHoursEnd
+
+	if input.Timezone != "" {
+		if _, err := time.LoadLocation(input.Timezone); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown timezone"})
+			return
+		}
+		settings.Timezone = input.Timezone
+	}
+
+	switch input.DigestFrequency {
+	case "":
+	case models.DigestOff, models.DigestDaily, models.DigestWeekly:
+		settings.DigestFrequency = input.DigestFrequency
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "digest_frequency must be off, daily or weekly"})
+		return
+	}
+
+	for _, p := range input.Preferences {
+		if !slices.Contains(models.NotificationTypes, p.Type) || !slices.Contains(models.NotificationChannels, p.Channel) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown notification type or channel: " + p.Type + "/" + p.Channel})
+			return
+		}
+	}
+
+	if err := ctrl.PrefRepo.Save(&settings, input.Preferences); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save preferences"})
+		return
+	}
+	ctrl.GetPreferences(c)
+}
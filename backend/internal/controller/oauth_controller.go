@@ -0,0 +1,312 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/events"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/oauth"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+	"gorm.io/gorm"
+)
+
+// oauthStateTTL is how long a user has to finish signing in at the provider.
+const oauthStateTTL = 10 * time.Minute
+
+// OAuthController signs users in with Google, LinkedIn and GitHub, and lets signed-in
+// users link those accounts. The provider redirects to the web app, which posts the
+// code and state to Callback.
+type OAuthController struct {
+	Providers  map[string]oauth.Provider
+	Identities *repository.IdentityRepository
+	Users      *repository.UserRepository
+	Sessions   *repository.SessionRepository
+	TwoFactor  *TwoFactorController
+	Guard      *LoginGuard
+	Events     *events.Bus
+}
+
+// ListProviders handles GET /auth/providers
+// Names of the providers users can sign in with.
+func (ctrl *OAuthController) ListProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"items": ctrl.providerNames()})
+}
+
+func (ctrl *OAuthController) providerNames() []string {
+	names := make([]string, 0, len(ctrl.Providers))
+	for name := range ctrl.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartSignIn handles GET /auth/oauth/:provider
+// Returns the provider URL to send the user to, and the state the web app should expect back.
+func (ctrl *OAuthController) StartSignIn(c *gin.Context) {
+	ctrl.start(c, nil)
+}
+
+// StartLink handles POST /me/identities/:provider
+// Like StartSignIn, but the web app finishes with FinishLink, which adds the provider
+// account to the signed-in user.
+func (ctrl *OAuthController) StartLink(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uuid.UUID)
+	ctrl.start(c, &userID)
+}
+
+func (ctrl *OAuthController) start(c *gin.Context, linkTo *uuid.UUID) {
+	provider, ok := ctrl.Providers[c.Param("provider")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown sign-in provider"})
+		return
+	}
+
+	state, errState := oauth.RandomString()
+	nonce, errNonce := oauth.RandomString()
+	verifier, challenge, errPKCE := oauth.NewPKCE()
+	if err := errors.Join(errState, errNonce, errPKCE); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not start sign-in"})
+		return
+	}
+
+	authURL := provider.AuthCodeURL(state, challenge, nonce)
+	if authURL == "" {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "The sign-in provider is unavailable"})
+		return
+	}
+	if err := ctrl.Identities.StartSignIn(provider.Name(), state, verifier, nonce, linkTo, oauthStateTTL); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not start sign-in"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"url": authURL, "state": state})
+}
+
+// Callback handles POST /auth/oauth/:provider/callback
+// Body: {"code": "...", "state": "..."} as the provider sent them back. Signs the user in,
+// linking the provider account to the user with the same verified email or creating a new
+// user if there's none. Links started by StartLink are finished by FinishLink instead.
+func (ctrl *OAuthController) Callback(c *gin.Context) {
+	provider, st, code, ok := ctrl.redeemState(c)
+	if !ok {
+		return
+	}
+	// A link must be finished by the account that started it, or anyone who got the
+	// victim to the provider could attach the victim's provider account to their own.
+	if st.UserID != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This link must be finished while signed in, please try again"})
+		return
+	}
+	identity, ok := ctrl.exchange(c, provider, st, code)
+	if !ok {
+		return
+	}
+
+	user, created, err := ctrl.userFor(c, provider.Name(), identity)
+	if err != nil {
+		if errors.Is(err, errNoVerifiedEmail) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Your " + provider.Name() + " account has no verified email address"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not sign in"})
+		return
+	}
+
+	resp, err := ctrl.TwoFactor.SignIn(c, user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not start session"})
+		return
+	}
+	if resp["mfa_token"] != nil {
+		ctrl.Guard.Record(c, user.Email, user, provider.Name(), models.LoginTwoFactorPending)
+	} else {
+		ctrl.Guard.Succeeded(c, user.Email, user, provider.Name())
+	}
+	resp["new_account"] = created
+	if resp["message"] == nil {
+		resp["message"] = "Welcome to the Spotlight"
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// FinishLink handles POST /me/identities/:provider/callback
+// Body: {"code": "...", "state": "..."} as the provider sent them back after StartLink.
+// Adds the provider account to the signed-in user, who must be the one that started it.
+func (ctrl *OAuthController) FinishLink(c *gin.Context) {
+	provider, st, code, ok := ctrl.redeemState(c)
+	if !ok {
+		return
+	}
+	val, _ := c.Get("user_id")
+	if st.UserID == nil || *st.UserID != val.(uuid.UUID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This link was started from another account"})
+		return
+	}
+	identity, ok := ctrl.exchange(c, provider, st, code)
+	if !ok {
+		return
+	}
+
+	err := ctrl.Identities.Link(*st.UserID, provider.Name(), identity)
+	if errors.Is(err, repository.ErrIdentityTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": "That account is already linked to another user"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not link account"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"linked": true, "provider": provider.Name()})
+}
+
+// redeemState reads the code and state the provider sent back and uses up the state.
+// It answers the request itself and returns false when they're missing or the state is
+// unknown or expired.
+func (ctrl *OAuthController) redeemState(c *gin.Context) (oauth.Provider, *models.OAuthState, string, bool) {
+	provider, ok := ctrl.Providers[c.Param("provider")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown sign-in provider"})
+		return nil, nil, "", false
+	}
+	var input struct {
+		Code  string `json:"code" binding:"required"`
+		State string `json:"state" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Code and state are required"})
+		return nil, nil, "", false
+	}
+
+	st, err := ctrl.Identities.FinishSignIn(provider.Name(), input.State)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This sign-in has expired, please try again"})
+		return nil, nil, "", false
+	}
+	return provider, st, input.Code, true
+}
+
+// exchange trades the code for the provider account. It answers the request itself and
+// returns false on failure.
+func (ctrl *OAuthController) exchange(c *gin.Context, provider oauth.Provider, st *models.OAuthState, code string) (*oauth.Identity, bool) {
+	identity, err := provider.Exchange(c.Request.Context(), code, st.CodeVerifier, st.Nonce)
+	if err != nil {
+		log.Printf("⚠️ %s sign-in failed: %v", provider.Name(), err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Could not sign in with " + provider.Name()})
+		return nil, false
+	}
+	return identity, true
+}
+
+var errNoVerifiedEmail = errors.New("identity has no verified email")
+
+// userFor finds or creates the user signing in with a provider account. Accounts are
+// matched by email only when the provider has verified it.
+func (ctrl *OAuthController) userFor(c *gin.Context, provider string, identity *oauth.Identity) (*models.User, bool, error) {
+	user, err := ctrl.Identities.UserFor(provider, identity)
+	if err == nil {
+		return user, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, false, errNoVerifiedEmail
+	}
+
+	created := false
+	user, err = ctrl.Users.GetByEmail(identity.Email)
+	switch {
+	case err == nil && !user.IsVerified():
+		// Whoever registered this address never proved they own it; the provider just did
+		if err := ctrl.Users.ClaimUnverified(user.ID); err != nil {
+			return nil, false, err
+		}
+		if _, err := ctrl.Sessions.RevokeAll(user.ID); err != nil {
+			return nil, false, err
+		}
+		if user, err = ctrl.Users.GetByID(user.ID); err != nil {
+			return nil, false, err
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		now := time.Now()
+		user = &models.User{
+			ID:              uuid.New(),
+			Email:           identity.Email,
+			Name:            identity.Name,
+			Role:            "viewer",
+			Locale:          "en",
+			EmailVerifiedAt: &now,
+		}
+		if err := ctrl.Users.Create(user); err != nil {
+			return nil, false, err
+		}
+		created = true
+	case err != nil:
+		return nil, false, err
+	}
+
+	if err := ctrl.Identities.Link(user.ID, provider, identity); err != nil {
+		return nil, false, err
+	}
+	if created {
+		ctrl.Events.Publish(c.Request.Context(), events.UserRegistered{
+			UserID: user.ID,
+			Email:  user.Email,
+			Name:   user.Name,
+			Locale: user.Locale,
+		})
+	}
+	return user, created, nil
+}
+
+// ListIdentities handles GET /me/identities
+// The provider accounts linked to the user, and the providers they could link.
+func (ctrl *OAuthController) ListIdentities(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	identities, err := ctrl.Identities.List(val.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch linked accounts"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": identities, "providers": ctrl.providerNames()})
+}
+
+// Unlink handles DELETE /me/identities/:provider
+// Users without a password must keep at least one provider to sign in with.
+func (ctrl *OAuthController) Unlink(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	user, err := ctrl.Users.GetByID(val.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if user.Password == "" {
+		identities, err := ctrl.Identities.List(user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not unlink account"})
+			return
+		}
+		if len(identities) <= 1 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Set a password before removing your only way to sign in"})
+			return
+		}
+	}
+
+	removed, err := ctrl.Identities.Unlink(user.ID, c.Param("provider"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not unlink account"})
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No linked account for that provider"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Account unlinked"})
+}
@@ -0,0 +1,297 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/authz"
+	"github.com/saidimuKennedy/spotlight-africa/internal/mail"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+	"gorm.io/gorm"
+)
+
+// PlatformInquiryController is the admin triage console for messages sent through the contact form.
+type PlatformInquiryController struct {
+	Repo   *repository.PlatformInquiryRepository
+	Users  *repository.UserRepository
+	Policy *authz.Policy
+	Outbox *mail.Outbox
+}
+
+var platformInquiryStatuses = map[string]bool{
+	models.PlatformInquiryStatusPending:    true,
+	models.PlatformInquiryStatusInProgress: true,
+	models.PlatformInquiryStatusResponded:  true,
+	models.PlatformInquiryStatusClosed:     true,
+}
+
+// ListInquiries handles GET /admin/platform-inquiries
+// Query params: status, assigned_to (user id, "me" or "none"), q, quarantined, limit, offset.
+func (ctrl *PlatformInquiryController) ListInquiries(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	assignedTo := c.Query("assigned_to")
+	if assignedTo == "me" {
+		val, _ := c.Get("user_id")
+		assignedTo = val.(uuid.UUID).String()
+	}
+
+	inquiries, total, err := ctrl.Repo.List(repository.PlatformInquiryFilter{
+		Status:      c.Query("status"),
+		AssignedTo:  assignedTo,
+		Search:      strings.TrimSpace(c.Query("q")),
+		Quarantined: c.Query("quarantined") == "true",
+		Limit:       limit,
+		Offset:      offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch inquiries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":  inquiries,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetInquiry handles GET /admin/platform-inquiries/:id
+func (ctrl *PlatformInquiryController) GetInquiry(c *gin.Context) {
+	inquiry, err := ctrl.Repo.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Inquiry not found"})
+		return
+	}
+	c.JSON(http.StatusOK, inquiry)
+}
+
+// AssignInquiry handles PATCH /admin/platform-inquiries/:id/assign
+// Body: {"assignee_id": "<admin user id>" | "me" | ""}. An empty value unassigns.
+func (ctrl *PlatformInquiryController) AssignInquiry(c *gin.Context) {
+	var input struct {
+		AssigneeID string `json:"assignee_id"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	inquiry, err := ctrl.Repo.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Inquiry not found"})
+		return
+	}
+
+	var assignee *uuid.UUID
+	switch input.AssigneeID {
+	case "":
+	case "me":
+		val, _ := c.Get("user_id")
+		id := val.(uuid.UUID)
+		assignee = &id
+	default:
+		id, err := uuid.Parse(input.AssigneeID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignee"})
+			return
+		}
+		// Only staff who can work the inquiry can be given it, whatever their role is called
+		user, err := ctrl.Users.GetByID(id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Assignee must be able to manage platform inquiries"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign inquiry"})
+			return
+		}
+		grants, err := ctrl.Policy.Grants(user.Role)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign inquiry"})
+			return
+		}
+		if !grants.Has(authz.PlatformInquiryManage) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Assignee must be able to manage platform inquiries"})
+			return
+		}
+		assignee = &id
+	}
+
+	if err := ctrl.Repo.Assign(inquiry, assignee); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign inquiry"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Inquiry assigned", "inquiry": inquiry})
+}
+
+// UpdateInquiryStatus handles PATCH /admin/platform-inquiries/:id/status
+func (ctrl *PlatformInquiryController) UpdateInquiryStatus(c *gin.Context) {
+	var input struct {
+		Status string `json:"status" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || !platformInquiryStatuses[input.Status] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be one of pending, in_progress, responded, closed"})
+		return
+	}
+
+	inquiry, err := ctrl.Repo.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Inquiry not found"})
+		return
+	}
+
+	if err := ctrl.Repo.UpdateStatus(inquiry, input.Status); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Status updated", "inquiry": inquiry})
+}
+
+// ReplyToInquiry handles POST /admin/platform-inquiries/:id/replies
+// Body: {"body": "...", "canned_response_id": "...", "close": false}. Either body or
+// canned_response_id is required; the reply is emailed to the person who wrote in.
+func (ctrl *PlatformInquiryController) ReplyToInquiry(c *gin.Context) {
+	var input struct {
+		Body             string `json:"body"`
+		CannedResponseID string `json:"canned_response_id"`
+		Close            bool   `json:"close"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	inquiry, err := ctrl.Repo.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Inquiry not found"})
+		return
+	}
+
+	body := strings.TrimSpace(input.Body)
+	if body == "" && input.CannedResponseID != "" {
+		canned, err := ctrl.Repo.GetCannedResponse(input.CannedResponseID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Canned response not found"})
+			return
+		}
+		body = canned.Body
+	}
+	if body == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Reply body or canned response is required"})
+		return
+	}
+	body = strings.NewReplacer(
+		"{{name}}", inquiry.Name,
+		"{{email}}", inquiry.Email,
+		"{{subject}}", inquiry.Subject,
+	).Replace(body)
+
+	// The reply and its email are saved together. The outbox delivers and retries the
+	// email, and the reply links to it so the admin can see whether it went out.
+	val, _ := c.Get("user_id")
+	reply := models.PlatformInquiryReply{
+		InquiryID: inquiry.ID,
+		AuthorID:  val.(uuid.UUID),
+		Body:      body,
+	}
+	var email *models.OutboxEmail
+	err = ctrl.Repo.DB.Transaction(func(tx *gorm.DB) error {
+		queued, err := ctrl.Outbox.QueueEmail(tx, inquiry.Email, "inquiry_reply", mail.DefaultLocale, map[string]interface{}{
+			"Name":    inquiry.Name,
+			"Subject": inquiry.Subject,
+			"Body":    body,
+		})
+		if err != nil {
+			return err
+		}
+		email = queued
+		reply.OutboxEmailID = &queued.ID
+		return ctrl.Repo.AddReply(tx, inquiry, &reply, input.Close)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save reply"})
+		return
+	}
+
+	reply.Email = email
+	c.JSON(http.StatusCreated, reply)
+}
+
+// --- Canned responses ---
+
+// GetCannedResponses handles GET /admin/canned-responses
+func (ctrl *PlatformInquiryController) GetCannedResponses(c *gin.Context) {
+	responses, err := ctrl.Repo.ListCannedResponses()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch canned responses"})
+		return
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// CreateCannedResponse handles POST /admin/canned-responses
+func (ctrl *PlatformInquiryController) CreateCannedResponse(c *gin.Context) {
+	var input struct {
+		Title string `json:"title" binding:"required"`
+		Body  string `json:"body" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Title and body are required"})
+		return
+	}
+
+	val, _ := c.Get("user_id")
+	response := models.CannedResponse{Title: input.Title, Body: input.Body, CreatedBy: val.(uuid.UUID)}
+	if err := ctrl.Repo.SaveCannedResponse(&response); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Could not save canned response (duplicate title?)"})
+		return
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// UpdateCannedResponse handles PUT /admin/canned-responses/:id
+func (ctrl *PlatformInquiryController) UpdateCannedResponse(c *gin.Context) {
+	response, err := ctrl.Repo.GetCannedResponse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Canned response not found"})
+		return
+	}
+
+	var input struct {
+		Title string `json:"title" binding:"required"`
+		Body  string `json:"body" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Title and body are required"})
+		return
+	}
+
+	response.Title, response.Body = input.Title, input.Body
+	if err := ctrl.Repo.SaveCannedResponse(response); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Could not save canned response (duplicate title?)"})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteCannedResponse handles DELETE /admin/canned-responses/:id
+func (ctrl *PlatformInquiryController) DeleteCannedResponse(c *gin.Context) {
+	if err := ctrl.Repo.DeleteCannedResponse(c.Param("id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete canned response"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Canned response deleted"})
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/middleware"
+	"github.com/saidimuKennedy/spotlight-africa/internal/realtime"
+)
+
+// RealtimeController streams live updates to the signed-in user.
+type RealtimeController struct {
+	Hub *realtime.Hub
+}
+
+// heartbeatInterval keeps proxies from closing idle streams. Each heartbeat also checks
+// the user is still signed in with the same access.
+const heartbeatInterval = 25 * time.Second
+
+// Stream handles GET /stream
+// A Server-Sent Events stream; each event is named after the message type
+// ("notification", "chat", "inquiry") and carries its JSON payload. The stream ends when
+// the session is revoked or the user's role changes; reconnecting with a refreshed token
+// picks it up again.
+func (ctrl *RealtimeController) Stream(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	messages, unsubscribe := ctrl.Hub.Subscribe(val.(uuid.UUID))
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable nginx response buffering
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.SSEvent("ready", gin.H{"connected_at": time.Now()})
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case msg := <-messages:
+			c.SSEvent(msg.Type, msg.Data)
+		case <-heartbeat.C:
+			if !middleware.StillAuthorized(c) {
+				c.SSEvent("session_ended", gin.H{})
+				return false
+			}
+			c.SSEvent("ping", time.Now().Unix())
+		}
+		return true
+	})
+}
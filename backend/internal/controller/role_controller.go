@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidimuKennedy/spotlight-africa/internal/authz"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+	"gorm.io/gorm"
+)
+
+// RoleController lets admins define roles and the permissions they grant.
+type RoleController struct {
+	Repo   *repository.RoleRepository
+	Policy *authz.Policy
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,29}$`)
+
+// validGrants checks a role's permission list. Only the admin role may hold "*".
+func validGrants(perms []string) (string, bool) {
+	for _, p := range perms {
+		if p == authz.All {
+			return "Only the admin role has every permission", false
+		}
+		if !authz.ValidGrant(p) {
+			return "Unknown permission " + p, false
+		}
+	}
+	return "", true
+}
+
+// ListRoles handles GET /admin/roles
+// Returns every role with its user count, and the permissions roles can be granted.
+func (ctrl *RoleController) ListRoles(c *gin.Context) {
+	roles, err := ctrl.Repo.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch roles"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": roles, "permissions": authz.Catalog})
+}
+
+// CreateRole handles POST /admin/roles
+// Body: {"name": "editor", "description": "...", "permissions": ["blog:publish"]}
+func (ctrl *RoleController) CreateRole(c *gin.Context) {
+	var input struct {
+		Name        string   `json:"name" binding:"required"`
+		Description string   `json:"description" binding:"max=255"`
+		Permissions []string `json:"permissions"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || !roleNamePattern.MatchString(input.Name) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name must be 2-30 lowercase letters, digits, - or _"})
+		return
+	}
+	if msg, ok := validGrants(input.Permissions); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if !authz.FromContext(c).Covers(input.Permissions) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only grant permissions you hold yourself"})
+		return
+	}
+	if _, err := ctrl.Repo.Get(input.Name); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A role with that name already exists"})
+		return
+	}
+
+	if input.Permissions == nil {
+		input.Permissions = []string{}
+	}
+	role := &models.Role{Name: input.Name, Description: input.Description, Permissions: input.Permissions}
+	if err := ctrl.Repo.Create(role); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create role"})
+		return
+	}
+	c.JSON(http.StatusCreated, role)
+}
+
+// UpdateRole handles PUT /admin/roles/:name
+// Body: {"description": "...", "permissions": [...]}. Built-in roles can be edited too,
+// except admin, which always has every permission. Changes apply to holders within seconds.
+func (ctrl *RoleController) UpdateRole(c *gin.Context) {
+	role, err := ctrl.Repo.Get(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+		return
+	}
+	if role.Name == "admin" {
+		c.JSON(http.StatusConflict, gin.H{"error": "The admin role can't be changed"})
+		return
+	}
+
+	var input struct {
+		Description string   `json:"description" binding:"max=255"`
+		Permissions []string `json:"permissions"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	if msg, ok := validGrants(input.Permissions); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	// Editing a role changes what its holders can do, so both the old and the new
+	// permissions must be ones the editor holds.
+	grants := authz.FromContext(c)
+	if !grants.Covers(role.Permissions) || !grants.Covers(input.Permissions) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit roles whose permissions you hold yourself"})
+		return
+	}
+
+	if input.Permissions == nil {
+		input.Permissions = []string{}
+	}
+	role.Description, role.Permissions = input.Description, input.Permissions
+	if err := ctrl.Repo.Update(role); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update role"})
+		return
+	}
+	ctrl.Policy.Forget(role.Name)
+	c.JSON(http.StatusOK, role)
+}
+
+// DeleteRole handles DELETE /admin/roles/:name
+// Only custom roles that nobody holds can be deleted.
+func (ctrl *RoleController) DeleteRole(c *gin.Context) {
+	name := c.Param("name")
+	deleted, err := ctrl.Repo.Delete(name)
+	if errors.Is(err, repository.ErrRoleInUse) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Move its users to another role first"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete role"})
+		return
+	}
+	if !deleted {
+		if _, err := ctrl.Repo.Get(name); errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": "Built-in roles can't be deleted"})
+		return
+	}
+	ctrl.Policy.Forget(name)
+	c.JSON(http.StatusOK, gin.H{"message": "Role deleted"})
+}
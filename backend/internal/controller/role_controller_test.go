@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidimuKennedy/spotlight-africa/internal/authz"
+	"github.com/saidimuKennedy/spotlight-africa/internal/database/dbtest"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+)
+
+// holding stands in for the auth middleware loading the caller's permissions.
+func holding(perms ...string) gin.HandlerFunc {
+	return func(c *gin.Context) { c.Set(authz.ContextKey, authz.Grants(perms)) }
+}
+
+// A role manager who isn't an admin must not hand out permissions they don't have.
+var roleManager = holding(string(authz.RoleManage), string(authz.BlogPublish))
+
+func TestCreateRoleRefusesPermissionsTheCallerLacks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := &RoleController{}
+	r := gin.New()
+	r.POST("/admin/roles", roleManager, ctrl.CreateRole)
+
+	code := serve(t, r, "POST", "/admin/roles", `{"name":"helper","permissions":["blog:publish","user:manage"]}`, nil)
+	if code != http.StatusForbidden {
+		t.Fatalf("granting user:manage without holding it: status %d, want 403", code)
+	}
+}
+
+func TestUpdateRoleRequiresHoldingOldAndNewPermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &repository.RoleRepository{DB: dbtest.Open(t, &models.Role{})}
+	for _, role := range []*models.Role{
+		{Name: "writer", Permissions: []string{string(authz.BlogPublish)}},
+		{Name: "support", Permissions: []string{string(authz.UserManage)}},
+	} {
+		if err := repo.Create(role); err != nil {
+			t.Fatalf("create role: %v", err)
+		}
+	}
+	ctrl := &RoleController{Repo: repo, Policy: &authz.Policy{Load: repo.Permissions}}
+	r := gin.New()
+	r.PUT("/admin/roles/:name", roleManager, ctrl.UpdateRole)
+
+	for _, tc := range []struct {
+		path, body string
+		want       int
+	}{
+		// Adding a permission the caller lacks
+		{"/admin/roles/writer", `{"permissions":["blog:publish","user:manage"]}`, http.StatusForbidden},
+		// Stripping a role the caller couldn't have granted
+		{"/admin/roles/support", `{"permissions":["blog:publish"]}`, http.StatusForbidden},
+		{"/admin/roles/writer", `{"description":"Blog writers","permissions":["blog:publish"]}`, http.StatusOK},
+	} {
+		if code := serve(t, r, "PUT", tc.path, tc.body, nil); code != tc.want {
+			t.Fatalf("PUT %s %s: status %d, want %d", tc.path, tc.body, code, tc.want)
+		}
+	}
+
+	support, _ := repo.Get("support")
+	if len(support.Permissions) != 1 || support.Permissions[0] != string(authz.UserManage) {
+		t.Fatalf("support permissions = %v, want them unchanged", support.Permissions)
+	}
+}
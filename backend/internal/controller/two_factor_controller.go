@@ -0,0 +1,354 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+	"github.com/saidimuKennedy/spotlight-africa/internal/totp"
+)
+
+// Limits on the step between the password and the second factor.
+const (
+	twoFactorChallengeTTL = 5 * time.Minute
+	twoFactorMaxAttempts  = 5
+)
+
+// twoFactorIssuer names the account in authenticator apps.
+const twoFactorIssuer = "Spotlight Africa"
+
+// TwoFactorController handles authenticator app (TOTP) sign-in. Once a user has enabled
+// it, or their role requires it, signing in takes a code after the password: login
+// returns an mfa_token instead of a session, which Verify (or Setup and ConfirmSetup for
+// users who haven't set it up yet) exchanges for one.
+type TwoFactorController struct {
+	Repo     *repository.TwoFactorRepository
+	Users    *repository.UserRepository
+	Tokens   *repository.UserTokenRepository
+	Sessions *repository.SessionRepository
+	Guard    *LoginGuard
+	Required func(role string) bool // Roles that must use a second factor; nil for none
+}
+
+func (ctrl *TwoFactorController) required(role string) bool {
+	return ctrl.Required != nil && ctrl.Required(role)
+}
+
+// SignIn finishes the first factor for a user whose password (or identity provider) checked
+// out. It starts a session, or returns the mfa_token for the second step.
+func (ctrl *TwoFactorController) SignIn(c *gin.Context, user *models.User) (gin.H, error) {
+	enabled, err := ctrl.Repo.Enabled(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled && !ctrl.required(user.Role) {
+		return startSession(c, ctrl.Sessions, user, false)
+	}
+
+	token, err := ctrl.Tokens.Issue(user.ID, models.TokenPurposeTwoFactor, twoFactorChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return gin.H{
+			"two_factor_setup_required": true,
+			"mfa_token":                 token,
+			"message":                   "Your account requires two-factor authentication. Set up an authenticator app to continue.",
+		}, nil
+	}
+	return gin.H{
+		"two_factor_required": true,
+		"mfa_token":           token,
+		"message":             "Enter the code from your authenticator app",
+	}, nil
+}
+
+// challenge returns the user an mfa_token was issued to, answering the request itself
+// if the token is no good.
+func (ctrl *TwoFactorController) challenge(c *gin.Context, raw string) (*models.UserToken, *models.User, bool) {
+	token, err := ctrl.Tokens.Peek(raw, models.TokenPurposeTwoFactor)
+	if errors.Is(err, repository.ErrInvalidUserToken) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "This sign-in has expired, please log in again"})
+		return nil, nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify code"})
+		return nil, nil, false
+	}
+	user, err := ctrl.Users.GetByID(token.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "This sign-in has expired, please log in again"})
+		return nil, nil, false
+	}
+	return token, user, true
+}
+
+// finish uses up the mfa_token and starts the session.
+func (ctrl *TwoFactorController) finish(c *gin.Context, raw string, user *models.User) (gin.H, bool) {
+	// Consume wins any race between two requests with the same token
+	if _, err := ctrl.Tokens.Consume(raw, models.TokenPurposeTwoFactor); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "This sign-in has expired, please log in again"})
+		return nil, false
+	}
+	resp, err := startSession(c, ctrl.Sessions, user, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not start session"})
+		return nil, false
+	}
+	resp["message"] = "Welcome back to the Spotlight"
+	return resp, true
+}
+
+// Verify handles POST /auth/2fa/verify
+// Body: {"mfa_token": "...", "code": "..."} where code is from the authenticator app or a
+// recovery code. A few wrong codes end the attempt.
+func (ctrl *TwoFactorController) Verify(c *gin.Context) {
+	var input struct {
+		MFAToken string `json:"mfa_token" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token and code are required"})
+		return
+	}
+	token, user, ok := ctrl.challenge(c, input.MFAToken)
+	if !ok || !ctrl.Guard.Check(c, user.Email, "two_factor") {
+		return
+	}
+
+	recovery, err := ctrl.Repo.Verify(user.ID, input.Code)
+	if errors.Is(err, repository.ErrInvalidTwoFactorCode) {
+		_ = ctrl.Tokens.RecordFailure(token.ID, twoFactorMaxAttempts)
+		ctrl.Guard.Failed(c, user.Email, user, "two_factor", models.LoginTwoFactorFailed)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "That code isn't right, please try again"})
+		return
+	}
+	if err != nil {
+		ctrl.Guard.Release(c, user.Email)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify code"})
+		return
+	}
+
+	resp, ok := ctrl.finish(c, input.MFAToken, user)
+	if !ok {
+		ctrl.Guard.Release(c, user.Email)
+		return
+	}
+	ctrl.Guard.Succeeded(c, user.Email, user, "two_factor")
+	if recovery {
+		left, _ := ctrl.Repo.RecoveryCodesLeft(user.ID)
+		resp["recovery_codes_left"] = left
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Setup handles POST /auth/2fa/setup
+// Body: {"mfa_token": "..."} from a login that requires setting up two-factor first.
+// Returns the secret and the otpauth:// URI to show as a QR code.
+func (ctrl *TwoFactorController) Setup(c *gin.Context) {
+	var input struct {
+		MFAToken string `json:"mfa_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token is required"})
+		return
+	}
+	_, user, ok := ctrl.challenge(c, input.MFAToken)
+	if !ok {
+		return
+	}
+	ctrl.begin(c, user)
+}
+
+// ConfirmSetup handles POST /auth/2fa/setup/confirm
+// Body: {"mfa_token": "...", "code": "..."}. Enables two-factor, signs the user in and
+// returns their recovery codes.
+func (ctrl *TwoFactorController) ConfirmSetup(c *gin.Context) {
+	var input struct {
+		MFAToken string `json:"mfa_token" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token and code are required"})
+		return
+	}
+	token, user, ok := ctrl.challenge(c, input.MFAToken)
+	if !ok || !ctrl.Guard.Check(c, user.Email, "two_factor") {
+		return
+	}
+
+	codes, err := ctrl.Repo.Enable(user.ID, input.Code)
+	if errors.Is(err, repository.ErrInvalidTwoFactorCode) {
+		_ = ctrl.Tokens.RecordFailure(token.ID, twoFactorMaxAttempts)
+		ctrl.Guard.Failed(c, user.Email, user, "two_factor", models.LoginTwoFactorFailed)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "That code isn't right, please try again"})
+		return
+	}
+	if !ctrl.enableOK(c, err) {
+		ctrl.Guard.Release(c, user.Email)
+		return
+	}
+
+	resp, ok := ctrl.finish(c, input.MFAToken, user)
+	if !ok {
+		ctrl.Guard.Release(c, user.Email)
+		return
+	}
+	ctrl.Guard.Succeeded(c, user.Email, user, "two_factor")
+	resp["recovery_codes"] = codes
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetStatus handles GET /me/2fa
+func (ctrl *TwoFactorController) GetStatus(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	role, _ := c.Get("user_role")
+	userID := val.(uuid.UUID)
+
+	status := gin.H{"enabled": false, "required": ctrl.required(role.(string))}
+	tf, err := ctrl.Repo.Get(userID)
+	if err == nil && tf.EnabledAt != nil {
+		left, _ := ctrl.Repo.RecoveryCodesLeft(userID)
+		status["enabled"] = true
+		status["enabled_at"] = tf.EnabledAt
+		status["recovery_codes_left"] = left
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// BeginSetup handles POST /me/2fa/setup
+// Starts setting up an authenticator app for the signed-in user; see Setup.
+func (ctrl *TwoFactorController) BeginSetup(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	user, err := ctrl.Users.GetByID(val.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	ctrl.begin(c, user)
+}
+
+func (ctrl *TwoFactorController) begin(c *gin.Context, user *models.User) {
+	secret, err := ctrl.Repo.Begin(user.ID)
+	if errors.Is(err, repository.ErrTwoFactorEnabled) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not start setup"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"secret": secret,
+		"uri":    totp.URI(twoFactorIssuer, user.Email, secret),
+	})
+}
+
+// Enable handles POST /me/2fa/enable
+// Body: {"code": "..."} from the app, to prove it's set up. Returns the recovery codes.
+func (ctrl *TwoFactorController) Enable(c *gin.Context) {
+	var input struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Code is required"})
+		return
+	}
+	val, _ := c.Get("user_id")
+	sessionID, _ := c.Get("session_id")
+
+	codes, err := ctrl.Repo.Enable(val.(uuid.UUID), input.Code)
+	if errors.Is(err, repository.ErrInvalidTwoFactorCode) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "That code isn't right, please try again"})
+		return
+	}
+	if !ctrl.enableOK(c, err) {
+		return
+	}
+	// The user just entered a code, so this device counts as having passed the second factor
+	_ = ctrl.Sessions.MarkTwoFactor(sessionID.(uuid.UUID))
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// enableOK answers the errors Enable shares with ConfirmSetup. It reports whether
+// the request can go on.
+func (ctrl *TwoFactorController) enableOK(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, repository.ErrTwoFactorNotSetUp):
+		c.JSON(http.StatusConflict, gin.H{"error": "Start setting up two-factor authentication first"})
+	case errors.Is(err, repository.ErrTwoFactorEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not enable two-factor authentication"})
+	}
+	return false
+}
+
+// Disable handles POST /me/2fa/disable
+// Body: {"code": "..."}, from the app or a recovery code. Not allowed for roles that require it.
+func (ctrl *TwoFactorController) Disable(c *gin.Context) {
+	role, _ := c.Get("user_role")
+	if ctrl.required(role.(string)) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Your role requires two-factor authentication"})
+		return
+	}
+	userID, ok := ctrl.confirmCode(c)
+	if !ok {
+		return
+	}
+	if err := ctrl.Repo.Disable(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not disable two-factor authentication"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes handles POST /me/2fa/recovery-codes
+// Body: {"code": "..."}. Replaces the user's recovery codes; the old ones stop working.
+func (ctrl *TwoFactorController) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, ok := ctrl.confirmCode(c)
+	if !ok {
+		return
+	}
+	codes, err := ctrl.Repo.RegenerateRecoveryCodes(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create recovery codes"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// confirmCode checks the code in the body before a change to the user's two-factor
+// settings, so a device left signed in can't be used to turn it off.
+func (ctrl *TwoFactorController) confirmCode(c *gin.Context) (uuid.UUID, bool) {
+	var input struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Code is required"})
+		return uuid.Nil, false
+	}
+	val, _ := c.Get("user_id")
+	userID := val.(uuid.UUID)
+
+	_, err := ctrl.Repo.Verify(userID, input.Code)
+	switch {
+	case errors.Is(err, repository.ErrTwoFactorNotSetUp):
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is not enabled"})
+		return uuid.Nil, false
+	case errors.Is(err, repository.ErrInvalidTwoFactorCode):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "That code isn't right, please try again"})
+		return uuid.Nil, false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify code"})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/authz"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+	"gorm.io/gorm"
+)
+
+// UserController lets admins manage user accounts.
+type UserController struct {
+	Users *repository.UserRepository
+	Roles *repository.RoleRepository
+}
+
+// ChangeRole handles PATCH /admin/users/:id/role
+// Body: {"role": "editor"}. Any role from /admin/roles can be given, as long as the caller
+// holds every permission of both the new role and the user's current one; only admins
+// can make or unmake admins. The user's existing access tokens stop working; their next
+// refresh picks up the new role, so they don't have to log in again.
+func (ctrl *UserController) ChangeRole(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	var input struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role is required"})
+		return
+	}
+	role, err := ctrl.Roles.Get(input.Role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown role " + input.Role})
+		return
+	}
+
+	// Someone else has to change yours, so an admin can't lock the platform out by accident
+	val, _ := c.Get("user_id")
+	if userID == val.(uuid.UUID) {
+		c.JSON(http.StatusConflict, gin.H{"error": "You can't change your own role"})
+		return
+	}
+
+	// Nobody can hand out, or take away, access they don't have themselves
+	target, err := ctrl.Users.GetByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not change role"})
+		return
+	}
+	current, err := ctrl.Roles.Permissions(target.Role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not change role"})
+		return
+	}
+	grants := authz.FromContext(c)
+	if !grants.Covers(role.Permissions) || !grants.Covers(current) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only change roles between ones whose permissions you hold yourself"})
+		return
+	}
+
+	user, err := ctrl.Users.ChangeRole(userID, input.Role)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not change role"})
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
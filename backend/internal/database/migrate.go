@@ -0,0 +1,73 @@
+package database
+
+import (
+	"log"
+
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+)
+
+// PrepareMigrations cleans up data that would stop AutoMigrate from adding new constraints.
+// It must run before db.AutoMigrate and is safe to run on every start.
+func PrepareMigrations(db *gorm.DB) {
+	// likes gained a unique (user_id, business_id) index. Older databases may hold duplicate
+	// likes from before the constraint existed, so keep the earliest one of each pair.
+	if db.Migrator().HasTable(&models.Like{}) && !db.Migrator().HasIndex(&models.Like{}, "idx_likes_user_business") {
+		res := db.Exec(`DELETE FROM likes a USING likes b
+			WHERE a.user_id = b.user_id AND a.business_id = b.business_id
+			AND (a.created_at > b.created_at OR (a.created_at = b.created_at AND a.id::text > b.id::text))`)
+		if res.Error != nil {
+			log.Println("⚠️ Could not remove duplicate likes:", res.Error)
+		} else if res.RowsAffected > 0 {
+			log.Printf("🧹 Removed %d duplicate likes before adding unique index", res.RowsAffected)
+		}
+	}
+
+	// newsletter_subscribers gained double opt-in. Addresses that signed up before it existed
+	// are treated as confirmed rather than silently dropped from the list.
+	if db.Migrator().HasTable(&models.NewsletterSubscriber{}) && !db.Migrator().HasColumn(&models.NewsletterSubscriber{}, "ConfirmedAt") {
+		if err := db.Migrator().AddColumn(&models.NewsletterSubscriber{}, "ConfirmedAt"); err != nil {
+			log.Println("⚠️ Could not add newsletter confirmed_at:", err)
+		} else {
+			db.Exec(`UPDATE newsletter_subscribers SET confirmed_at = created_at`)
+		}
+	}
+
+	// jobs gained a partial unique index on pending (type, dedupe_key). Drop pending
+	// duplicates that slipped past the old check-then-insert, keeping the earliest.
+	if db.Migrator().HasTable(&models.Job{}) && !db.Migrator().HasIndex(&models.Job{}, "idx_jobs_pending_dedupe") {
+		res := db.Exec(`DELETE FROM jobs a USING jobs b
+			WHERE a.type = b.type AND a.dedupe_key = b.dedupe_key AND a.dedupe_key <> ''
+			AND a.status = 'pending' AND b.status = 'pending'
+			AND (a.created_at > b.created_at OR (a.created_at = b.created_at AND a.id::text > b.id::text))`)
+		if res.Error != nil {
+			log.Println("⚠️ Could not remove duplicate pending jobs:", res.Error)
+		} else if res.RowsAffected > 0 {
+			log.Printf("🧹 Removed %d duplicate pending jobs before adding unique index", res.RowsAffected)
+		}
+	}
+
+	// users gained email verification. Accounts created before it are trusted as verified.
+	if db.Migrator().HasTable(&models.User{}) && !db.Migrator().HasColumn(&models.User{}, "EmailVerifiedAt") {
+		if err := db.Migrator().AddColumn(&models.User{}, "EmailVerifiedAt"); err != nil {
+			log.Println("⚠️ Could not add users.email_verified_at:", err)
+		} else {
+			db.Exec(`UPDATE users SET email_verified_at = created_at`)
+		}
+	}
+}
+
+// BackfillBusinessMembers gives every business's owner an owner membership. Businesses
+// created before teams existed only had businesses.owner_id. It must run after AutoMigrate
+// and is safe to run on every start.
+func BackfillBusinessMembers(db *gorm.DB) {
+	res := db.Exec(`INSERT INTO business_members (id, business_id, user_id, role, created_at, updated_at)
+		SELECT gen_random_uuid(), b.id, b.owner_id, ?, NOW(), NOW()
+		FROM businesses b JOIN users u ON u.id = b.owner_id
+		ON CONFLICT (business_id, user_id) DO NOTHING`, models.MemberRoleOwner)
+	if res.Error != nil {
+		log.Println("⚠️ Could not backfill business owners:", res.Error)
+	} else if res.RowsAffected > 0 {
+		log.Printf("👥 Added %d business owners to their teams", res.RowsAffected)
+	}
+}
@@ -1,6 +1,8 @@
 package database
 
 import (
+	crand "crypto/rand"
+	"encoding/base64"
 	"log"
 	"math/rand"
 	"os"
@@ -9,12 +11,16 @@ import (
 	"github.com/brianvoe/gofakeit/v7"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
+	"github.com/saidimuKennedy/spotlight-africa/internal/authz"
 	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+	"github.com/saidimuKennedy/spotlight-africa/internal/scoring"
 	"github.com/saidimuKennedy/spotlight-africa/internal/utils"
 	"gorm.io/gorm"
 )
 
-// SeedAdmin handles the master account creation
+// SeedAdmin handles the master account creation. The password comes from ADMIN_PASSWORD;
+// without it a random one is generated and logged once, so there's no default to guess.
 func SeedAdmin(db *gorm.DB) {
 	if err := godotenv.Load(); err != nil {
 		log.Println("Note: Error loading .env file in seed")
@@ -25,26 +31,66 @@ func SeedAdmin(db *gorm.DB) {
 	db.Model(&models.User{}).Where("role = ?", "admin").Count(&count)
 
 	if count == 0 {
-		hashedPassword, _ := utils.HashPassword("Password615!")
+		password, generated := os.Getenv("ADMIN_PASSWORD"), false
+		if password == "" {
+			buf := make([]byte, 18)
+			if _, err := crand.Read(buf); err != nil {
+				log.Println("⚠️ Could not generate an admin password:", err)
+				return
+			}
+			password, generated = base64.RawURLEncoding.EncodeToString(buf), true
+		}
+		hashedPassword, err := utils.HashPassword(password)
+		if err != nil {
+			log.Println("⚠️ Could not hash the admin password:", err)
+			return
+		}
 		admin := models.User{
 			ID:       uuid.New(),
 			Email:    os.Getenv("ADMIN_EMAIL"),
 			Password: hashedPassword,
 			Role:     "admin",
 		}
-		db.Create(&admin)
+		now := time.Now()
+		admin.EmailVerifiedAt = &now
+		if err := db.Create(&admin).Error; err != nil {
+			log.Println("⚠️ Could not seed admin user:", err)
+			return
+		}
 		log.Println("✅ Admin user seeded.")
+		if generated {
+			log.Printf("🔑 ADMIN_PASSWORD is not set; the admin password is %s (shown once, change it after signing in)", password)
+		}
+	}
+}
+
+// SeedRoles creates the built-in roles and the example "editor" role on first start.
+func SeedRoles(db *gorm.DB) {
+	own := func(p authz.Permission) string { return string(p) + ":own" }
+	roles := []models.Role{
+		{Name: "admin", Description: "Full access to the platform", Permissions: []string{authz.All}, System: true},
+		{Name: "privileged", Description: "Trusted members who can see platform statistics", Permissions: []string{string(authz.StatsView)}, System: true},
+		{Name: "owner", Description: "Business owners; manage their own business", System: true, Permissions: []string{
+			own(authz.BusinessUpdate), own(authz.InquiryManage), own(authz.MeetingManage), own(authz.CommentModerate),
+		}},
+		{Name: "viewer", Description: "Default role for new accounts", Permissions: []string{}, System: true},
+		{Name: "editor", Description: "Writes and publishes blog content", Permissions: []string{string(authz.BlogPublish)}},
+	}
+	if err := (&repository.RoleRepository{DB: db}).EnsureDefaults(roles); err != nil {
+		log.Println("⚠️ Could not seed roles:", err)
 	}
 }
 
 // SeedData handles the mass generation of businesses and interactions.
 func SeedData(db *gorm.DB) {
+	SeedRoles(db)
 	SeedAdmin(db)
 
 	var bizCount int64
 	db.Model(&models.Business{}).Count(&bizCount)
 	if bizCount == 0 {
 		log.Println("🌱 Seeding businesses...")
+		health := &repository.HealthRepository{DB: db, Engine: scoring.NewEngine(scoring.ConfigFromEnv())}
 
 		// 1. Create 5 Owners
 		var users []models.User
@@ -116,9 +162,9 @@ func SeedData(db *gorm.DB) {
 					db.Create(&models.Comment{ID: uuid.New(), UserID: user.ID, BusinessID: business.ID, Content: gofakeit.Phrase()})
 				}
 			}
-			updateHealthScore(db, business.ID)
+			health.Recalculate(business.ID.String())
 		}
-		updateHealthScore(db, municode.ID)
+		health.Recalculate(municode.ID.String())
 	} else {
 		log.Println("ℹ️ Database already has business data. Skipping business seed.")
 	}
@@ -241,12 +287,3 @@ func SeedData(db *gorm.DB) {
 
 	log.Println("✅ Seed complete.")
 }
-
-func updateHealthScore(db *gorm.DB, bizID uuid.UUID) {
-	var likes int64
-	var comments int64
-	db.Model(&models.Like{}).Where("business_id = ?", bizID).Count(&likes)
-	db.Model(&models.Comment{}).Where("business_id = ?", bizID).Count(&comments)
-	score := (int(likes) * 2) + (int(comments) * 5)
-	db.Model(&models.Business{}).Where("id = ?", bizID).Update("health_score", score)
-}
\ No newline at end of file
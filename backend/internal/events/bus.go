@@ -0,0 +1,89 @@
+// Package events is an in-process domain event bus. Controllers publish what happened
+// (a business was created, someone liked it, an inquiry came in) and subscribers decide
+// what to do about it: log activity, rescore, notify, call webhooks. New features subscribe
+// to existing events instead of editing every handler.
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Event is a domain event. EventName identifies the event type, e.g. "business.created".
+type Event interface {
+	EventName() string
+}
+
+// Handler reacts to an event. Errors are logged; they never fail the publishing request.
+type Handler func(ctx context.Context, e Event) error
+
+// Bus dispatches events to subscribers synchronously, in subscription order.
+// Subscribers that do slow or failure-prone work should hand it to the job queue.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	all      []Handler
+}
+
+// NewBus returns an empty bus.
+func NewBus() *Bus {
+	return &Bus{handlers: map[string][]Handler{}}
+}
+
+// Subscribe registers h for events with the given name.
+func (b *Bus) Subscribe(name string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[name] = append(b.handlers[name], h)
+}
+
+// SubscribeAll registers h for every event (used by webhooks).
+func (b *Bus) SubscribeAll(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.all = append(b.all, h)
+}
+
+// On registers a typed handler for events of type E.
+//
+//	events.On(bus, func(ctx context.Context, e events.LikeAdded) error { ... })
+func On[E Event](b *Bus, fn func(ctx context.Context, e E) error) {
+	var zero E
+	b.Subscribe(zero.EventName(), func(ctx context.Context, e Event) error {
+		typed, ok := e.(E)
+		if !ok {
+			return fmt.Errorf("event %s has unexpected type %T", e.EventName(), e)
+		}
+		return fn(ctx, typed)
+	})
+}
+
+// Publish delivers e to every subscriber. A nil bus is a no-op, so tools that don't
+// wire subscribers (seeders, one-off scripts) can share controller code.
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	handlers := append(append([]Handler{}, b.handlers[e.EventName()]...), b.all...)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		if err := call(ctx, h, e); err != nil {
+			log.Printf("⚠️ Event %s: subscriber failed: %v", e.EventName(), err)
+		}
+	}
+}
+
+// call runs a handler, turning panics into errors so one bad subscriber can't take down the request.
+func call(ctx context.Context, h Handler, e Event) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return h(ctx, e)
+}
@@ -0,0 +1,147 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserRegistered fires when someone creates an account.
+type UserRegistered struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+	Name   string    `json:"name"`
+	Locale string    `json:"locale"`
+}
+
+func (UserRegistered) EventName() string { return "user.registered" }
+
+// NewsletterSubscribed fires when an address confirms its newsletter subscription.
+type NewsletterSubscribed struct {
+	Email  string   `json:"email"`
+	Locale string   `json:"locale"`
+	Topics []string `json:"topics"`
+}
+
+func (NewsletterSubscribed) EventName() string { return "newsletter.subscribed" }
+
+// BusinessCreated fires when a user registers a business.
+type BusinessCreated struct {
+	BusinessID uuid.UUID `json:"business_id"`
+	OwnerID    uuid.UUID `json:"owner_id"`
+	Name       string    `json:"name"`
+}
+
+func (BusinessCreated) EventName() string { return "business.created" }
+
+// BusinessViewed fires when a profile view is tracked. UserID is nil for anonymous visitors.
+type BusinessViewed struct {
+	BusinessID uuid.UUID  `json:"business_id"`
+	UserID     *uuid.UUID `json:"user_id"`
+}
+
+func (BusinessViewed) EventName() string { return "business.viewed" }
+
+// ConversionTracked fires when a visitor converts (e.g. clicks through to the business).
+type ConversionTracked struct {
+	BusinessID uuid.UUID  `json:"business_id"`
+	UserID     *uuid.UUID `json:"user_id"`
+}
+
+func (ConversionTracked) EventName() string { return "business.conversion" }
+
+// LikeAdded fires when a user reacts to a business or changes their reaction type.
+type LikeAdded struct {
+	BusinessID uuid.UUID `json:"business_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	Reaction   string    `json:"reaction"`
+}
+
+func (LikeAdded) EventName() string { return "like.added" }
+
+// LikeRemoved fires when a user withdraws their reaction.
+type LikeRemoved struct {
+	BusinessID uuid.UUID `json:"business_id"`
+	UserID     uuid.UUID `json:"user_id"`
+}
+
+func (LikeRemoved) EventName() string { return "like.removed" }
+
+// CommentAdded fires when a comment becomes public: on posting, or when a held-back comment is approved.
+type CommentAdded struct {
+	CommentID  uuid.UUID  `json:"comment_id"`
+	BusinessID uuid.UUID  `json:"business_id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	ParentID   *uuid.UUID `json:"parent_id"` // Set for replies
+}
+
+func (CommentAdded) EventName() string { return "comment.added" }
+
+// CommentVisibilityChanged fires when a comment is deleted, hidden or unhidden.
+type CommentVisibilityChanged struct {
+	CommentID  uuid.UUID `json:"comment_id"`
+	BusinessID uuid.UUID `json:"business_id"`
+	Visible    bool      `json:"visible"`
+}
+
+func (CommentVisibilityChanged) EventName() string { return "comment.visibility_changed" }
+
+// CommentsModerated fires once per business after an admin bulk moderation action.
+type CommentsModerated struct {
+	BusinessID uuid.UUID `json:"business_id"`
+	Action     string    `json:"action"`
+}
+
+func (CommentsModerated) EventName() string { return "comments.moderated" }
+
+// InquirySubmitted fires when an inquiry reaches a business: on submission, or when approved from quarantine.
+type InquirySubmitted struct {
+	InquiryID  uuid.UUID `json:"inquiry_id"`
+	BusinessID uuid.UUID `json:"business_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	Subject    string    `json:"subject"`
+}
+
+func (InquirySubmitted) EventName() string { return "inquiry.submitted" }
+
+// InquiryStatusChanged fires when an inquiry moves through the pipeline.
+type InquiryStatusChanged struct {
+	InquiryID  uuid.UUID `json:"inquiry_id"`
+	BusinessID uuid.UUID `json:"business_id"`
+	UserID     uuid.UUID `json:"user_id"` // Who sent the inquiry
+	Status     string    `json:"status"`
+	ChangedBy  uuid.UUID `json:"changed_by"`
+}
+
+func (InquiryStatusChanged) EventName() string { return "inquiry.status_changed" }
+
+// MeetingBooked fires when a user books a meeting with a business.
+type MeetingBooked struct {
+	MeetingID  uuid.UUID `json:"meeting_id"`
+	BusinessID uuid.UUID `json:"business_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	Title      string    `json:"title"`
+	StartTime  time.Time `json:"start_time"`
+}
+
+func (MeetingBooked) EventName() string { return "meeting.booked" }
+
+// MeetingStatusChanged fires when a meeting is completed or cancelled.
+type MeetingStatusChanged struct {
+	MeetingID  uuid.UUID `json:"meeting_id"`
+	BusinessID uuid.UUID `json:"business_id"`
+	UserID     uuid.UUID `json:"user_id"` // Who booked the meeting
+	Status     string    `json:"status"`
+	ChangedBy  uuid.UUID `json:"changed_by"`
+}
+
+func (MeetingStatusChanged) EventName() string { return "meeting.status_changed" }
+
+// ChatMessagePosted fires when someone posts to the network chat.
+type ChatMessagePosted struct {
+	MessageID  uuid.UUID  `json:"message_id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	BusinessID *uuid.UUID `json:"business_id"`
+}
+
+func (ChatMessagePosted) EventName() string { return "chat.message_posted" }
@@ -0,0 +1,288 @@
+// Package jobs is a small Postgres-backed job queue. Work that used to run in bare goroutines
+// (health recomputes, activity logging, notifications, email) is persisted as a row, so it
+// survives a crash, is retried with backoff, and is dead-lettered for an admin once it runs
+// out of attempts.
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Job types handled by the runner.
+const (
+	TypeHealthRecompute = "health.recompute"
+	TypeActivityTrack   = "activity.track"
+	TypeNotification    = "notification.create"
+	TypeWebhook         = "webhook.deliver"
+	TypeEmail           = "email.send"
+	TypeDigest          = "notification.digest"
+	TypeNewsletterSend  = "newsletter.send"
+)
+
+// HealthRecompute is the payload for TypeHealthRecompute.
+type HealthRecompute struct {
+	BusinessID uuid.UUID `json:"business_id"`
+}
+
+// Notification is the payload for TypeNotification.
+type Notification struct {
+	Notification models.Notification `json:"notification"`
+	// ActorID is who caused the notification; each actor is counted once per group.
+	ActorID *uuid.UUID `json:"actor_id,omitempty"`
+	// GroupMessage replaces the message once notifications are merged, e.g. "%d people liked your business today".
+	GroupMessage string `json:"group_message,omitempty"`
+}
+
+// Email is the payload for TypeEmail: a message stored in the outbox.
+type Email struct {
+	OutboxID uuid.UUID `json:"outbox_id"`
+}
+
+// NewsletterSend is the payload for TypeNewsletterSend: start or continue sending an issue.
+type NewsletterSend struct {
+	IssueID uuid.UUID `json:"issue_id"`
+}
+
+// Digest is the payload for TypeDigest.
+type Digest struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// Webhook is the payload for TypeWebhook: one event delivery to one endpoint.
+type Webhook struct {
+	URL   string `json:"url"`
+	Event string `json:"event"`
+	Body  string `json:"body"` // Pre-rendered JSON envelope, so every retry sends identical bytes
+}
+
+// ErrNotDead is returned when retrying a job that hasn't been dead-lettered.
+var ErrNotDead = errors.New("only dead jobs can be retried")
+
+// ErrAlreadyQueued is returned when retrying a job whose dedupe key already has a pending job.
+var ErrAlreadyQueued = errors.New("an identical job is already queued")
+
+const defaultMaxAttempts = 5
+
+// Queue enqueues and manages jobs. It is safe for concurrent use.
+type Queue struct {
+	DB *gorm.DB
+}
+
+// Option customises a job at enqueue time.
+type Option func(*models.Job)
+
+// Delay schedules the job to run no earlier than d from now.
+func Delay(d time.Duration) Option {
+	return func(j *models.Job) { j.RunAt = time.Now().Add(d) }
+}
+
+// Dedupe skips the enqueue if a pending job with the same type and key already exists.
+// Used to coalesce bursts, e.g. ten likes in a second only need one health recompute.
+func Dedupe(key string) Option {
+	return func(j *models.Job) { j.DedupeKey = key }
+}
+
+// MaxAttempts overrides how many times the job is tried before it is dead-lettered.
+func MaxAttempts(n int) Option {
+	return func(j *models.Job) { j.MaxAttempts = n }
+}
+
+// Enqueue persists a job. payload is JSON-encoded.
+func (q *Queue) Enqueue(jobType string, payload interface{}, opts ...Option) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	job := models.Job{
+		Type:        jobType,
+		Status:      models.JobStatusPending,
+		Payload:     string(body),
+		MaxAttempts: defaultMaxAttempts,
+		RunAt:       time.Now(),
+	}
+	for _, opt := range opts {
+		opt(&job)
+	}
+
+	if job.DedupeKey != "" {
+		// The partial unique index on pending jobs turns a duplicate into a no-op
+		return q.DB.Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "type"}, {Name: "dedupe_key"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: pendingDedupe}}},
+			DoNothing:   true,
+		}).Create(&job).Error
+	}
+	return q.DB.Create(&job).Error
+}
+
+// pendingDedupe is the predicate of the idx_jobs_pending_dedupe index (see models.Job).
+const pendingDedupe = "status = 'pending' AND dedupe_key <> ''"
+
+// RecomputeHealth queues a health score recalculation for a business.
+func (q *Queue) RecomputeHealth(bizID uuid.UUID) error {
+	return q.Enqueue(TypeHealthRecompute, HealthRecompute{BusinessID: bizID}, Dedupe("health:"+bizID.String()))
+}
+
+// TrackActivity queues an activity log entry.
+func (q *Queue) TrackActivity(a *models.Activity) error {
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	return q.Enqueue(TypeActivityTrack, a)
+}
+
+// Notify queues an in-app notification.
+func (q *Queue) Notify(n Notification) error {
+	return q.Enqueue(TypeNotification, n)
+}
+
+// claim locks the next runnable job. Jobs stuck in "running" for longer than lease
+// (their worker crashed) are picked up again. Returns nil when the queue is empty.
+func (q *Queue) claim(lease time.Duration) (*models.Job, error) {
+	var job models.Job
+	err := q.DB.Raw(`UPDATE jobs SET status = ?, locked_at = now(), attempts = attempts + 1, updated_at = now()
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE (status = ? AND run_at <= now()) OR (status = ? AND locked_at < ?)
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		models.JobStatusRunning, models.JobStatusPending, models.JobStatusRunning, time.Now().Add(-lease),
+	).Scan(&job).Error
+	if err != nil || job.ID == uuid.Nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (q *Queue) complete(job *models.Job) error {
+	now := time.Now()
+	return q.DB.Model(job).Updates(map[string]interface{}{
+		"status":       models.JobStatusDone,
+		"completed_at": now,
+		"locked_at":    nil,
+		"last_error":   "",
+	}).Error
+}
+
+// fail records a failed attempt and either reschedules the job with exponential backoff
+// or dead-letters it. Returns true if the job is now dead.
+func (q *Queue) fail(job *models.Job, cause error) (bool, error) {
+	updates := map[string]interface{}{
+		"last_error": cause.Error(),
+		"locked_at":  nil,
+	}
+
+	dead := job.Attempts >= job.MaxAttempts
+	if dead {
+		updates["status"] = models.JobStatusDead
+		return true, q.DB.Model(job).Updates(updates).Error
+	}
+
+	updates["status"] = models.JobStatusPending
+	updates["run_at"] = time.Now().Add(backoff(job.Attempts))
+	query := q.DB.Model(job)
+	if job.DedupeKey != "" {
+		// A newer pending job with the same key does the same work, so don't retry this one
+		query = query.Where("NOT EXISTS (SELECT 1 FROM jobs p WHERE p.type = ? AND p.dedupe_key = ? AND p.status = ?)",
+			job.Type, job.DedupeKey, models.JobStatusPending)
+	}
+	res := query.Updates(updates)
+	if res.Error != nil || res.RowsAffected > 0 {
+		return false, res.Error
+	}
+	now := time.Now()
+	return false, q.DB.Model(job).Updates(map[string]interface{}{
+		"status":       models.JobStatusDone,
+		"completed_at": now,
+		"locked_at":    nil,
+		"last_error":   cause.Error() + " (superseded by a newer job)",
+	}).Error
+}
+
+// Prune deletes jobs that finished more than olderThan ago. Dead jobs are kept for admins.
+func (q *Queue) Prune(olderThan time.Duration) (int64, error) {
+	res := q.DB.Where("status = ? AND completed_at < ?", models.JobStatusDone, time.Now().Add(-olderThan)).
+		Delete(&models.Job{})
+	return res.RowsAffected, res.Error
+}
+
+// backoff is 10s, 20s, 40s, ... capped at one hour.
+func backoff(attempt int) time.Duration {
+	d := 10 * time.Second
+	for i := 1; i < attempt && d < time.Hour; i++ {
+		d *= 2
+	}
+	if d > time.Hour {
+		d = time.Hour
+	}
+	return d
+}
+
+// List returns jobs newest first, optionally filtered by status, with the total count.
+func (q *Queue) List(status string, limit, offset int) ([]models.Job, int64, error) {
+	query := q.DB.Model(&models.Job{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var list []models.Job
+	err := query.Order("created_at desc").Limit(limit).Offset(offset).Find(&list).Error
+	return list, total, err
+}
+
+// CountByStatus returns the number of jobs in each status.
+func (q *Queue) CountByStatus() (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := q.DB.Model(&models.Job{}).Select("status, count(*) AS count").Group("status").Scan(&rows).Error
+
+	counts := map[string]int64{}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, err
+}
+
+// Retry puts a dead job back on the queue with a fresh set of attempts.
+func (q *Queue) Retry(id string) (*models.Job, error) {
+	var job models.Job
+	if err := q.DB.First(&job, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	if job.Status != models.JobStatusDead {
+		return nil, ErrNotDead
+	}
+
+	query := q.DB.Model(&job)
+	if job.DedupeKey != "" {
+		query = query.Where("NOT EXISTS (SELECT 1 FROM jobs p WHERE p.type = ? AND p.dedupe_key = ? AND p.status = ?)",
+			job.Type, job.DedupeKey, models.JobStatusPending)
+	}
+	res := query.Updates(map[string]interface{}{
+		"status":   models.JobStatusPending,
+		"attempts": 0,
+		"run_at":   time.Now(),
+	})
+	if res.Error == nil && res.RowsAffected == 0 {
+		return nil, ErrAlreadyQueued
+	}
+	return &job, res.Error
+}
@@ -0,0 +1,130 @@
+package jobs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/saidimuKennedy/spotlight-africa/internal/database/dbtest"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+)
+
+// The test transaction freezes now() at its start, so jobs are queued a minute in the
+// past to be due straight away.
+var due = Delay(-time.Minute)
+
+func TestClaimTakesEachDueJobOnce(t *testing.T) {
+	q := &Queue{DB: dbtest.Open(t, &models.Job{})}
+	if err := q.Enqueue(TypeDigest, Digest{}, due); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := q.Enqueue(TypeDigest, Digest{}, Delay(time.Hour)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	job, err := q.claim(time.Minute)
+	if err != nil || job == nil {
+		t.Fatalf("claim = %v, %v; want the due job", job, err)
+	}
+	if job.Status != models.JobStatusRunning || job.Attempts != 1 || job.LockedAt == nil {
+		t.Fatalf("claimed job has status %q, attempts %d, locked_at %v", job.Status, job.Attempts, job.LockedAt)
+	}
+	if again, err := q.claim(time.Minute); err != nil || again != nil {
+		t.Fatalf("second claim = %v, %v; the running job and the future one must be skipped", again, err)
+	}
+
+	if err := q.complete(job); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if again, _ := q.claim(time.Minute); again != nil {
+		t.Fatalf("a completed job was claimed again")
+	}
+}
+
+func TestClaimReclaimsExpiredLease(t *testing.T) {
+	q := &Queue{DB: dbtest.Open(t, &models.Job{})}
+	if err := q.Enqueue(TypeDigest, Digest{}, due); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	job, _ := q.claim(time.Minute)
+	if job == nil {
+		t.Fatal("nothing to claim")
+	}
+
+	// The worker holding it crashed an hour ago
+	q.DB.Model(&models.Job{}).Where("id = ?", job.ID).Update("locked_at", time.Now().Add(-time.Hour))
+	again, err := q.claim(time.Minute)
+	if err != nil || again == nil || again.ID != job.ID {
+		t.Fatalf("claim = %v, %v; want the abandoned job back", again, err)
+	}
+	if again.Attempts != 2 {
+		t.Fatalf("attempts = %d, want 2", again.Attempts)
+	}
+}
+
+func TestFailRetriesThenDeadLetters(t *testing.T) {
+	q := &Queue{DB: dbtest.Open(t, &models.Job{})}
+	if err := q.Enqueue(TypeDigest, Digest{}, due, MaxAttempts(2)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	cause := errors.New("smtp down")
+
+	job, _ := q.claim(time.Minute)
+	if dead, err := q.fail(job, cause); dead || err != nil {
+		t.Fatalf("first failure: dead = %v, err = %v", dead, err)
+	}
+	var stored models.Job
+	q.DB.First(&stored, "id = ?", job.ID)
+	if stored.Status != models.JobStatusPending || stored.LastError != "smtp down" || !stored.RunAt.After(time.Now()) {
+		t.Fatalf("after one failure: status %q, last_error %q, run_at %v", stored.Status, stored.LastError, stored.RunAt)
+	}
+	if again, _ := q.claim(time.Minute); again != nil {
+		t.Fatal("a job was retried before its backoff")
+	}
+
+	q.DB.Model(&stored).Update("run_at", time.Now().Add(-time.Minute))
+	job, _ = q.claim(time.Minute)
+	if dead, err := q.fail(job, cause); !dead || err != nil {
+		t.Fatalf("last failure: dead = %v, err = %v", dead, err)
+	}
+	q.DB.First(&stored, "id = ?", job.ID)
+	if stored.Status != models.JobStatusDead {
+		t.Fatalf("status = %q, want dead", stored.Status)
+	}
+
+	if _, err := q.Retry(job.ID.String()); err != nil {
+		t.Fatalf("Retry: %v", err)
+	}
+	if _, err := q.Retry(job.ID.String()); !errors.Is(err, ErrNotDead) {
+		t.Fatalf("retrying a pending job: err = %v, want ErrNotDead", err)
+	}
+}
+
+func TestDedupeCoalescesPendingJobs(t *testing.T) {
+	q := &Queue{DB: dbtest.Open(t, &models.Job{})}
+	for i := 0; i < 3; i++ {
+		if err := q.Enqueue(TypeDigest, Digest{}, due, Dedupe("digest:amina")); err != nil {
+			t.Fatalf("Enqueue %d: %v", i, err)
+		}
+	}
+	var n int64
+	q.DB.Model(&models.Job{}).Count(&n)
+	if n != 1 {
+		t.Fatalf("%d jobs queued, want 1", n)
+	}
+
+	// Once it's running a new event needs a fresh job
+	job, _ := q.claim(time.Minute)
+	if err := q.Enqueue(TypeDigest, Digest{}, due, Dedupe("digest:amina")); err != nil {
+		t.Fatalf("Enqueue while running: %v", err)
+	}
+	// ...which supersedes the running one if it fails
+	if dead, err := q.fail(job, errors.New("boom")); dead || err != nil {
+		t.Fatalf("fail: dead = %v, err = %v", dead, err)
+	}
+	var stored models.Job
+	q.DB.First(&stored, "id = ?", job.ID)
+	if stored.Status != models.JobStatusDone {
+		t.Fatalf("superseded job status = %q, want done", stored.Status)
+	}
+}
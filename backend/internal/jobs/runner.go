@@ -0,0 +1,122 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Handler executes one job. payload is the raw JSON given to Enqueue.
+// Returning an error schedules a retry (or dead-letters the job once it is out of attempts).
+type Handler func(ctx context.Context, payload []byte) error
+
+// Runner polls the queue and executes jobs with a fixed number of workers.
+// The worker count is the backpressure: a burst of traffic grows the queue, not the goroutine count.
+type Runner struct {
+	Queue        *Queue
+	Concurrency  int           // Number of workers (default 4)
+	PollInterval time.Duration // How long an idle worker waits before checking again (default 1s)
+	Lease        time.Duration // Per-job timeout; running jobs older than this are reclaimed (default 5m)
+	Retention    time.Duration // How long finished jobs are kept (default 7 days)
+
+	handlers map[string]Handler
+}
+
+// Handle registers the handler for a job type. Call before Start.
+func (r *Runner) Handle(jobType string, h Handler) {
+	if r.handlers == nil {
+		r.handlers = map[string]Handler{}
+	}
+	r.handlers[jobType] = h
+}
+
+// Start launches the workers.
+func (r *Runner) Start() {
+	if r.Concurrency <= 0 {
+		r.Concurrency = 4
+	}
+	if r.PollInterval <= 0 {
+		r.PollInterval = time.Second
+	}
+	if r.Lease <= 0 {
+		r.Lease = 5 * time.Minute
+	}
+
+	if r.Retention <= 0 {
+		r.Retention = 7 * 24 * time.Hour
+	}
+
+	log.Printf("🚀 Starting Job Runner with %d workers...", r.Concurrency)
+	for i := 0; i < r.Concurrency; i++ {
+		go r.work()
+	}
+	go r.prune()
+}
+
+// prune deletes old finished jobs every hour so the table doesn't grow forever.
+func (r *Runner) prune() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for ; ; <-ticker.C {
+		if n, err := r.Queue.Prune(r.Retention); err != nil {
+			log.Println("⚠️ Job Runner: prune failed:", err)
+		} else if n > 0 {
+			log.Printf("🧹 Job Runner: pruned %d finished jobs", n)
+		}
+	}
+}
+
+func (r *Runner) work() {
+	for {
+		job, err := r.Queue.claim(r.Lease)
+		if err != nil {
+			log.Println("⚠️ Job Runner: claim failed:", err)
+		}
+		if job == nil {
+			time.Sleep(r.PollInterval)
+			continue
+		}
+
+		// A job reclaimed after its worker died may already have used its last attempt.
+		if job.Attempts > job.MaxAttempts {
+			err = errors.New("lease expired on final attempt")
+		} else {
+			err = r.run(job.Type, []byte(job.Payload))
+		}
+
+		if err != nil {
+			dead, ferr := r.Queue.fail(job, err)
+			if ferr != nil {
+				log.Printf("⚠️ Job Runner: could not record failure of %s %s: %v", job.Type, job.ID, ferr)
+			}
+			if dead {
+				log.Printf("💀 Job Runner: %s %s dead after %d attempts: %v", job.Type, job.ID, job.Attempts, err)
+			}
+			continue
+		}
+
+		if err := r.Queue.complete(job); err != nil {
+			log.Printf("⚠️ Job Runner: could not mark %s %s done: %v", job.Type, job.ID, err)
+		}
+	}
+}
+
+// run executes a handler with the lease as its deadline, turning panics into errors.
+func (r *Runner) run(jobType string, payload []byte) (err error) {
+	h, ok := r.handlers[jobType]
+	if !ok {
+		return fmt.Errorf("no handler registered for job type %q", jobType)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.Lease)
+	defer cancel()
+	return h(ctx, payload)
+}
@@ -0,0 +1,166 @@
+// Package mail sends outgoing email. SMTP is used when configured; otherwise messages
+// are written to the log so local development works without a mail server.
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Message is a single outgoing email.
+type Message struct {
+	To      string
+	ReplyTo string
+	Subject string
+	Text    string
+	HTML    string            // Optional; sent as a multipart/alternative with Text when set
+	Headers map[string]string // Extra headers, e.g. List-Unsubscribe
+}
+
+// Sender delivers a Message.
+type Sender interface {
+	Send(msg Message) error
+}
+
+// NewSenderFromEnv returns an SMTPSender when SMTP_HOST is set, or a LogSender otherwise.
+//
+// Environment: SMTP_HOST, SMTP_PORT (default 587), SMTP_USERNAME, SMTP_PASSWORD, MAIL_FROM.
+func NewSenderFromEnv() Sender {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "Spotlight Africa <no-reply@spotlightafrica.com>"
+	}
+
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return &LogSender{From: from}
+	}
+
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	return &SMTPSender{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
+	}
+}
+
+// Link turns an app path ("/dashboard") into an absolute URL for use in emails.
+// APP_URL sets the web app's base URL (default http://localhost:5173).
+func Link(path string) string {
+	base := os.Getenv("APP_URL")
+	if base == "" {
+		base = "http://localhost:5173"
+	}
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+// APILink turns an API path into an absolute URL, for links that must reach the backend
+// directly (e.g. one-click unsubscribe). API_URL sets the base (default http://localhost:8080).
+func APILink(path string) string {
+	base := os.Getenv("API_URL")
+	if base == "" {
+		base = "http://localhost:8080"
+	}
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+// SMTPSender delivers mail through an SMTP relay.
+// net/smtp upgrades to STARTTLS automatically when the server offers it.
+type SMTPSender struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (s *SMTPSender) Send(msg Message) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+	return smtp.SendMail(s.Host+":"+s.Port, auth, address(s.From), []string{msg.To}, build(s.From, msg))
+}
+
+// LogSender prints messages instead of sending them.
+type LogSender struct {
+	From string
+}
+
+func (s *LogSender) Send(msg Message) error {
+	log.Printf("📧 [mail] to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Text)
+	return nil
+}
+
+// build renders the RFC 5322 message.
+func build(from string, msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	if msg.ReplyTo != "" {
+		fmt.Fprintf(&buf, "Reply-To: %s\r\n", msg.ReplyTo)
+	}
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	keys := make([]string, 0, len(msg.Headers))
+	for k := range msg.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "%s: %s\r\n", textproto.CanonicalMIMEHeaderKey(k), strings.NewReplacer("\r", "", "\n", "").Replace(msg.Headers[k]))
+	}
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if msg.HTML == "" {
+		buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+		buf.WriteString(crlf(msg.Text))
+		return buf.Bytes()
+	}
+
+	// Text first, HTML last: clients show the last part they understand.
+	w := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", w.Boundary())
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=UTF-8", msg.Text},
+		{"text/html; charset=UTF-8", msg.HTML},
+	} {
+		pw, _ := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		qp := quotedprintable.NewWriter(pw)
+		qp.Write([]byte(crlf(part.body)))
+		qp.Close()
+	}
+	w.Close()
+	return buf.Bytes()
+}
+
+// crlf normalises line endings to the CRLF that SMTP requires.
+func crlf(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n")
+}
+
+// address extracts the bare address from "Name <addr>" for the SMTP envelope.
+func address(from string) string {
+	if i := strings.LastIndex(from, "<"); i >= 0 {
+		return strings.TrimSuffix(from[i+1:], ">")
+	}
+	return from
+}
@@ -0,0 +1,187 @@
+package mail
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSMTP is just enough of an SMTP server for net/smtp.SendMail. It records every
+// message it accepts and can be told to refuse the next few recipients with a
+// temporary error, the way a relay under load does.
+type fakeSMTP struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	refuse   int
+	messages []smtpMessage
+}
+
+type smtpMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTP{ln: ln}
+	t.Cleanup(func() { ln.Close() })
+	go s.serve()
+	return s
+}
+
+// Sender returns an SMTPSender pointed at the fake server.
+func (s *fakeSMTP) Sender() *SMTPSender {
+	host, port, _ := net.SplitHostPort(s.ln.Addr().String())
+	return &SMTPSender{Host: host, Port: port, From: "Spotlight Africa <no-reply@example.com>"}
+}
+
+// RefuseNext makes the next n deliveries fail with a 451.
+func (s *fakeSMTP) RefuseNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refuse = n
+}
+
+// Messages returns the messages accepted so far.
+func (s *fakeSMTP) Messages() []smtpMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]smtpMessage(nil), s.messages...)
+}
+
+func (s *fakeSMTP) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTP) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 fake.example ESMTP")
+	var msg smtpMessage
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch verb {
+		case "EHLO", "HELO":
+			reply("250 fake.example")
+		case "MAIL":
+			msg = smtpMessage{From: strings.TrimPrefix(line[len("MAIL FROM:"):], " ")}
+			reply("250 OK")
+		case "RCPT":
+			s.mu.Lock()
+			refuse := s.refuse > 0
+			if refuse {
+				s.refuse--
+			}
+			s.mu.Unlock()
+			if refuse {
+				reply("451 4.3.0 Try again later")
+				continue
+			}
+			msg.To = append(msg.To, strings.TrimPrefix(line[len("RCPT TO:"):], " "))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			msg.Data = data.String()
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			reply("250 OK queued")
+		case "RSET", "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTPSenderDeliversMessage(t *testing.T) {
+	server := newFakeSMTP(t)
+
+	err := server.Sender().Send(Message{
+		To:      "amina@example.com",
+		ReplyTo: "support@example.com",
+		Subject: "Karibu, Amina",
+		Text:    "Line one\nLine two",
+		HTML:    "<p>Line one</p>",
+		Headers: map[string]string{"list-unsubscribe": "<https://example.com/u>\r\nBcc: evil@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	msgs := server.Messages()
+	if len(msgs) != 1 {
+		t.Fatalf("got %d messages, want 1", len(msgs))
+	}
+	msg := msgs[0]
+	if msg.From != "<no-reply@example.com>" {
+		t.Errorf("envelope from = %q, want the bare address", msg.From)
+	}
+	if len(msg.To) != 1 || msg.To[0] != "<amina@example.com>" {
+		t.Errorf("envelope to = %v", msg.To)
+	}
+	for _, want := range []string{
+		"From: Spotlight Africa <no-reply@example.com>\r\n",
+		"To: amina@example.com\r\n",
+		"Reply-To: support@example.com\r\n",
+		"Subject: Karibu, Amina\r\n",
+		"List-Unsubscribe: <https://example.com/u>Bcc: evil@example.com\r\n",
+		"Content-Type: multipart/alternative; boundary=",
+		"Line one\r\nLine two",
+	} {
+		if !strings.Contains(msg.Data, want) {
+			t.Errorf("message is missing %q:\n%s", want, msg.Data)
+		}
+	}
+	if strings.Contains(msg.Data, "\r\nBcc:") {
+		t.Error("a header value was able to inject another header")
+	}
+}
+
+func TestSMTPSenderReportsRelayFailure(t *testing.T) {
+	server := newFakeSMTP(t)
+	server.RefuseNext(1)
+
+	err := server.Sender().Send(Message{To: "amina@example.com", Subject: "Hi", Text: "Hello"})
+	if err == nil || !strings.Contains(err.Error(), "451") {
+		t.Fatalf("Send error = %v, want the relay's 451", err)
+	}
+	if n := len(server.Messages()); n != 0 {
+		t.Errorf("got %d messages after a refused recipient, want 0", n)
+	}
+}
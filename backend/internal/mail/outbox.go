@@ -0,0 +1,137 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/saidimuKennedy/spotlight-africa/internal/jobs"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Outbox stores outgoing email and delivers it through the job queue, so a message is
+// retried if the relay is down. Pass the caller's transaction to Queue to commit the
+// message together with the change that triggered it.
+type Outbox struct {
+	Repo   *repository.EmailRepository
+	Sender Sender
+}
+
+// Queue renders a template and stores the email for delivery. Pass the caller's
+// transaction as tx to tie the email to it, or nil to queue it on its own.
+// opts apply to the delivery job, e.g. jobs.Delay to hold it back.
+func (o *Outbox) Queue(tx *gorm.DB, to, template, locale string, data interface{}, opts ...jobs.Option) error {
+	return o.QueueWithHeaders(tx, to, template, locale, data, nil, opts...)
+}
+
+// QueueEmail is Queue, returning the stored email so the caller can link to it and
+// follow its delivery.
+func (o *Outbox) QueueEmail(tx *gorm.DB, to, template, locale string, data interface{}, opts ...jobs.Option) (*models.OutboxEmail, error) {
+	return o.queueTemplate(tx, to, template, locale, data, nil, opts)
+}
+
+// QueueWithHeaders is Queue with extra message headers, e.g. List-Unsubscribe on list mail.
+func (o *Outbox) QueueWithHeaders(tx *gorm.DB, to, template, locale string, data interface{}, headers map[string]string, opts ...jobs.Option) error {
+	_, err := o.queueTemplate(tx, to, template, locale, data, headers, opts)
+	return err
+}
+
+// QueueMessage stores an already composed message for delivery.
+func (o *Outbox) QueueMessage(tx *gorm.DB, msg Message, opts ...jobs.Option) error {
+	return o.queue(tx, &models.OutboxEmail{
+		To:      msg.To,
+		ReplyTo: msg.ReplyTo,
+		Subject: msg.Subject,
+		Text:    msg.Text,
+		HTML:    msg.HTML,
+		Headers: msg.Headers,
+	}, opts)
+}
+
+// queueTemplate renders a template in the best locale available and queues the result.
+func (o *Outbox) queueTemplate(tx *gorm.DB, to, template, locale string, data interface{}, headers map[string]string, opts []jobs.Option) (*models.OutboxEmail, error) {
+	locale = ResolveLocale(template, locale)
+	r, err := Render(template, locale, data)
+	if err != nil {
+		return nil, err
+	}
+	email := &models.OutboxEmail{
+		To:       to,
+		Subject:  r.Subject,
+		Text:     r.Text,
+		HTML:     r.HTML,
+		Headers:  headers,
+		Template: template,
+		Locale:   locale,
+	}
+	if err := o.queue(tx, email, opts); err != nil {
+		return nil, err
+	}
+	return email, nil
+}
+
+func (o *Outbox) queue(tx *gorm.DB, email *models.OutboxEmail, opts []jobs.Option) error {
+	if strings.TrimSpace(email.To) == "" {
+		return errors.New("mail: no recipient")
+	}
+	if tx == nil {
+		tx = o.Repo.DB
+	}
+
+	// Suppressed addresses are recorded but never handed to the relay.
+	if o.Repo.IsSuppressed(email.To) {
+		email.Status = models.EmailStatusSuppressed
+		return tx.Create(email).Error
+	}
+
+	email.Status = models.EmailStatusPending
+	return tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(email).Error; err != nil {
+			return err
+		}
+		return (&jobs.Queue{DB: tx}).Enqueue(jobs.TypeEmail, jobs.Email{OutboxID: email.ID}, opts...)
+	})
+}
+
+// Deliver is the TypeEmail job handler. It sends a stored email and records the outcome;
+// returning an error lets the job queue retry with backoff.
+func (o *Outbox) Deliver(ctx context.Context, payload []byte) error {
+	var p jobs.Email
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return err
+	}
+
+	email, err := o.Repo.GetOutbox(p.OutboxID.String())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil // Queued in a transaction that rolled back after all
+		}
+		return err
+	}
+	if email.Status == models.EmailStatusSent || email.Status == models.EmailStatusSuppressed {
+		return nil
+	}
+
+	// The address may have bounced since the email was queued.
+	if o.Repo.IsSuppressed(email.To) {
+		log.Printf("📭 Skipping email to suppressed address %s", email.To)
+		return o.Repo.RecordAttempt(email, models.EmailStatusSuppressed, "")
+	}
+
+	if err := o.Sender.Send(Message{
+		To:      email.To,
+		ReplyTo: email.ReplyTo,
+		Subject: email.Subject,
+		Text:    email.Text,
+		HTML:    email.HTML,
+		Headers: email.Headers,
+	}); err != nil {
+		_ = o.Repo.RecordAttempt(email, models.EmailStatusFailed, err.Error())
+		return err
+	}
+	return o.Repo.RecordAttempt(email, models.EmailStatusSent, "")
+}
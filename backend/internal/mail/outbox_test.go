@@ -0,0 +1,98 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/saidimuKennedy/spotlight-africa/internal/database/dbtest"
+	"github.com/saidimuKennedy/spotlight-africa/internal/jobs"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+)
+
+func newTestOutbox(t *testing.T, sender Sender) *Outbox {
+	db := dbtest.Open(t, &models.OutboxEmail{}, &models.EmailSuppression{}, &models.Job{})
+	return &Outbox{Repo: &repository.EmailRepository{DB: db}, Sender: sender}
+}
+
+// queuedJob returns the delivery job the outbox queued for email.
+func queuedJob(t *testing.T, o *Outbox, email *models.OutboxEmail) []byte {
+	t.Helper()
+	payload, _ := json.Marshal(jobs.Email{OutboxID: email.ID})
+	var job models.Job
+	if err := o.Repo.DB.Where("type = ? AND payload = ?", jobs.TypeEmail, string(payload)).First(&job).Error; err != nil {
+		t.Fatalf("no delivery job queued: %v", err)
+	}
+	return []byte(job.Payload)
+}
+
+func TestOutboxDeliverRetriesUntilSent(t *testing.T) {
+	server := newFakeSMTP(t)
+	o := newTestOutbox(t, server.Sender())
+
+	email, err := o.QueueEmail(nil, "amina@example.com", "inquiry_reply", "en", map[string]interface{}{
+		"Name": "Amina", "Subject": "Listing", "Body": "Thanks for reaching out.",
+	})
+	if err != nil {
+		t.Fatalf("QueueEmail: %v", err)
+	}
+	if email.Status != models.EmailStatusPending {
+		t.Fatalf("status = %q, want pending", email.Status)
+	}
+	payload := queuedJob(t, o, email)
+
+	// The relay is down for the first attempt; the error tells the job queue to retry.
+	server.RefuseNext(1)
+	if err := o.Deliver(context.Background(), payload); err == nil {
+		t.Fatal("Deliver succeeded while the relay refused the message")
+	}
+	stored, _ := o.Repo.GetOutbox(email.ID.String())
+	if stored.Status != models.EmailStatusFailed || stored.Attempts != 1 || stored.LastError == "" {
+		t.Fatalf("after a failed attempt got status=%q attempts=%d error=%q", stored.Status, stored.Attempts, stored.LastError)
+	}
+
+	if err := o.Deliver(context.Background(), payload); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	stored, _ = o.Repo.GetOutbox(email.ID.String())
+	if stored.Status != models.EmailStatusSent || stored.Attempts != 2 || stored.SentAt == nil {
+		t.Fatalf("after the retry got status=%q attempts=%d sent_at=%v", stored.Status, stored.Attempts, stored.SentAt)
+	}
+
+	// A duplicate run of the job must not send the email twice.
+	if err := o.Deliver(context.Background(), payload); err != nil {
+		t.Fatalf("duplicate run: %v", err)
+	}
+	if n := len(server.Messages()); n != 1 {
+		t.Fatalf("relay received %d messages, want 1", n)
+	}
+}
+
+func TestOutboxSkipsSuppressedAddress(t *testing.T) {
+	server := newFakeSMTP(t)
+	o := newTestOutbox(t, server.Sender())
+
+	email, err := o.QueueEmail(nil, "bounced@example.com", "inquiry_reply", "en", map[string]interface{}{
+		"Name": "B", "Subject": "S", "Body": "Body",
+	})
+	if err != nil {
+		t.Fatalf("QueueEmail: %v", err)
+	}
+	payload := queuedJob(t, o, email)
+
+	// The address bounces after the email was queued but before it went out.
+	if err := o.Repo.Suppress("Bounced@example.com", "bounce", "mailbox full"); err != nil {
+		t.Fatalf("Suppress: %v", err)
+	}
+	if err := o.Deliver(context.Background(), payload); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	stored, _ := o.Repo.GetOutbox(email.ID.String())
+	if stored.Status != models.EmailStatusSuppressed {
+		t.Fatalf("status = %q, want suppressed", stored.Status)
+	}
+	if n := len(server.Messages()); n != 0 {
+		t.Fatalf("relay received %d messages for a suppressed address", n)
+	}
+}
@@ -0,0 +1,107 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+)
+
+// DefaultLocale is used when a template has no translation for the requested locale.
+const DefaultLocale = "en"
+
+// Each template is a pair of files per locale under templates/<locale>/:
+// <name>.txt defines "subject" and "text", <name>.html defines "body", which is
+// wrapped in templates/layout.html.
+//
+//go:embed templates
+var templateFS embed.FS
+
+// Rendered is the output of a template, ready to become a Message.
+type Rendered struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+var funcs = map[string]interface{}{
+	"link": Link,
+}
+
+type compiled struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = map[string]*compiled{}
+)
+
+// Render executes the named template in the given locale ("fr", "fr-CA" and "fr_FR" all
+// resolve to "fr"), falling back to English.
+func Render(name, locale string, data interface{}) (Rendered, error) {
+	t, err := lookup(name, ResolveLocale(name, locale))
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := t.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Rendered{}, fmt.Errorf("render %s subject: %w", name, err)
+	}
+	if err := t.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return Rendered{}, fmt.Errorf("render %s text: %w", name, err)
+	}
+	if err := t.html.ExecuteTemplate(&html, "layout", data); err != nil {
+		return Rendered{}, fmt.Errorf("render %s html: %w", name, err)
+	}
+
+	return Rendered{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
+
+// ResolveLocale returns the locale that Render will actually use for the template.
+func ResolveLocale(name, locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locale = locale[:i]
+	}
+	if locale == "" {
+		return DefaultLocale
+	}
+	if _, err := fs.Stat(templateFS, "templates/"+locale+"/"+name+".txt"); err != nil {
+		return DefaultLocale
+	}
+	return locale
+}
+
+func lookup(name, locale string) (*compiled, error) {
+	key := locale + "/" + name
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if t, ok := cache[key]; ok {
+		return t, nil
+	}
+
+	text, err := texttemplate.New(name).Funcs(funcs).ParseFS(templateFS, "templates/"+key+".txt")
+	if err != nil {
+		return nil, fmt.Errorf("unknown email template %q: %w", name, err)
+	}
+	html, err := htmltemplate.New(name).Funcs(funcs).ParseFS(templateFS, "templates/layout.html", "templates/"+key+".html")
+	if err != nil {
+		return nil, fmt.Errorf("unknown email template %q: %w", name, err)
+	}
+
+	t := &compiled{text: text, html: html}
+	cache[key] = t
+	return t, nil
+}
@@ -1,16 +1,38 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/authz"
 	"github.com/saidimuKennedy/spotlight-africa/internal/utils"
+	"gorm.io/gorm"
 )
 
+// SessionActive reports whether the session behind an access token is still live.
+// main sets it at startup; when nil (e.g. in tools) only the token itself is checked.
+var SessionActive func(sessionID uuid.UUID) bool
+
+// CurrentRole returns a user's role and token version as they are now, rather than
+// when the token was issued. It returns gorm.ErrRecordNotFound for a deleted user.
+// main sets it at startup; when nil the token's role is trusted.
+var CurrentRole func(userID uuid.UUID) (role string, tokenVersion int, err error)
+
+// RoleGrants returns the permissions a role holds (see package authz). main sets it at
+// startup; when nil handlers see no permissions and only role lists apply.
+var RoleGrants func(role string) (authz.Grants, error)
+
+// TwoFactorRequired reports whether a role must sign in with a second factor. main sets
+// it at startup; when nil no role requires it.
+var TwoFactorRequired func(role string) bool
+
 // Authorize is a Higher-Order Function that returns a Gin handler.
 // The `...string` syntax is a "Variadic Parameter", allowing us to pass any number of roles.
 // Example: Authorize("admin", "privileged")
+// With no roles it admits any signed-in user; pair it with Require to gate by permission.
 func Authorize(requiredRoles ...string) gin.HandlerFunc {
 
 	return func(c *gin.Context) {
@@ -46,11 +68,49 @@ func Authorize(requiredRoles ...string) gin.HandlerFunc {
 			return
 		}
 
+		// Tokens die with their session, so logout and revocation take effect immediately
+		if SessionActive != nil && (claims.SessionID == uuid.Nil || !SessionActive(claims.SessionID)) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has ended, please log in again"})
+			c.Abort()
+			return
+		}
+
+		// Roles come from the database so changes apply without logging in again.
+		// A token from before the user's last role change must be refreshed first.
+		userRole := claims.Role
+		if CurrentRole != nil {
+			role, version, err := CurrentRole(claims.UserID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				// The database is unreachable, not the token bad; the client should retry
+				// rather than sign the user out.
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Could not check your access, please try again"})
+				c.Abort()
+				return
+			}
+			if err != nil || version != claims.Version {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Your access has changed, please refresh your session"})
+				c.Abort()
+				return
+			}
+			userRole = role
+		}
+
+		// Sessions that skipped the second factor can't act with a role that requires it,
+		// e.g. after being promoted. Signing in again walks the user through setting it up.
+		if TwoFactorRequired != nil && TwoFactorRequired(userRole) && !claims.TwoFactor {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Your role requires two-factor authentication, please sign in again to set it up",
+				"code":  "two_factor_required",
+			})
+			c.Abort()
+			return
+		}
+
 		// 4. Role-Based Access Control (RBAC)
 		// Check if the user's role is in the list of allowed roles for this route
-		roleAllowed := false
+		roleAllowed := len(requiredRoles) == 0
 		for _, role := range requiredRoles {
-			if claims.Role == role {
+			if userRole == role {
 				roleAllowed = true
 				break
 			}
@@ -66,9 +126,67 @@ func Authorize(requiredRoles ...string) gin.HandlerFunc {
 		// This "Context Storage" allows controllers (like InteractionController) 
 		// to see who is logged in without re-validating the token.
 		c.Set("user_id", claims.UserID)
-		c.Set("user_role", claims.Role)
+		c.Set("user_role", userRole)
+		c.Set("session_id", claims.SessionID)
+		c.Set("token_version", claims.Version)
+		c.Set("two_factor", claims.TwoFactor)
+		if RoleGrants != nil {
+			grants, err := RoleGrants(userRole)
+			if err != nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Could not check your access, please try again"})
+				c.Abort()
+				return
+			}
+			c.Set(authz.ContextKey, grants)
+		}
 		
 		// .Next() tells Gin to proceed to the actual controller function.
 		c.Next() 
 	}
-}
\ No newline at end of file
+}
+
+// OptionalAuthorize identifies the caller on public routes that show more to signed-in
+// users. Requests without an Authorization header go through anonymously; a bad token is
+// still rejected, so clients find out their session has ended.
+func OptionalAuthorize() gin.HandlerFunc {
+	authorize := Authorize()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		authorize(c)
+	}
+}
+
+// StillAuthorized re-checks the session and role Authorize let a request in with. For
+// long-lived requests, like the event stream, that should end at logout or a role change.
+func StillAuthorized(c *gin.Context) bool {
+	sessionID, _ := c.Get("session_id")
+	if SessionActive != nil && !SessionActive(sessionID.(uuid.UUID)) {
+		return false
+	}
+	if CurrentRole != nil {
+		userID, _ := c.Get("user_id")
+		tokenRole, _ := c.Get("user_role")
+		tokenVersion, _ := c.Get("token_version")
+		role, version, err := CurrentRole(userID.(uuid.UUID))
+		if err != nil || role != tokenRole || version != tokenVersion {
+			return false
+		}
+	}
+	return true
+}
+
+// TokenFromQuery lets a route accept the JWT as a ?token= query parameter.
+// Browsers can't set headers on an EventSource, so the stream endpoint needs this.
+// Use it only on routes that need it: query strings end up in proxy logs, and Logger
+// redacts them from ours.
+func TokenFromQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := c.Query("token"); token != "" && c.GetHeader("Authorization") == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		c.Next()
+	}
+}
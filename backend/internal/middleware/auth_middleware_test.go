@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/authz"
+	"github.com/saidimuKennedy/spotlight-africa/internal/utils"
+)
+
+func TestAuthorizeAsksToRetryWhenAccessCantBeLoaded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+	token, err := utils.GenerateToken(uuid.New(), "user", uuid.New(), 0, false)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	down := errors.New("connection refused")
+	for _, tc := range []struct {
+		name        string
+		currentRole func(uuid.UUID) (string, int, error)
+		roleGrants  func(string) (authz.Grants, error)
+	}{
+		{
+			name:        "role lookup fails",
+			currentRole: func(uuid.UUID) (string, int, error) { return "", 0, down },
+		},
+		{
+			name:        "permission lookup fails",
+			currentRole: func(uuid.UUID) (string, int, error) { return "user", 0, nil },
+			roleGrants:  func(string) (authz.Grants, error) { return nil, down },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			CurrentRole, RoleGrants = tc.currentRole, tc.roleGrants
+			t.Cleanup(func() { CurrentRole, RoleGrants = nil, nil })
+
+			r := gin.New()
+			r.GET("/", Authorize(), func(c *gin.Context) { c.Status(http.StatusOK) })
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != http.StatusServiceUnavailable {
+				t.Fatalf("status %d, want 503: the client should retry, not sign out", w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RejectBanned blocks banned users from routes that publish content (comments, inquiries, chat...).
+// It must run after Authorize so "user_id" is in the context.
+//
+// isBanned is injected (usually ModerationRepository.IsBanned) to keep this package free of DB access.
+func RejectBanned(isBanned func(userID interface{}) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID, exists := c.Get("user_id"); exists && isBanned(userID) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Your account has been suspended from posting"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidimuKennedy/spotlight-africa/internal/authz"
+)
+
+// Require blocks users whose role lacks perm. A grant for their own resources only
+// (e.g. "business:update:own") passes here; the handler checks the resource with
+// authz.CanAccess. It must run after Authorize.
+func Require(perm authz.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !authz.FromContext(c).Has(perm) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to access this resource"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KeyFunc extracts the identity a request is throttled by (user, IP, ...).
+// Returning "" skips that key for the request.
+type KeyFunc func(c *gin.Context) string
+
+// ByIP throttles by the client's IP address.
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUser throttles by the authenticated user. It must run after Authorize.
+func ByUser(c *gin.Context) string {
+	if val, exists := c.Get("user_id"); exists {
+		return fmt.Sprintf("user:%v", val)
+	}
+	return ""
+}
+
+// window tracks how many hits a key has made in the current fixed window.
+type window struct {
+	start time.Time
+	count int
+}
+
+// rateLimiter is a simple in-memory fixed-window counter.
+// Good enough for a single API instance; swap for Redis if we scale out.
+type rateLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	period  time.Duration
+	windows map[string]*window
+}
+
+// allow records a hit for every key if all of them are within the limit. Otherwise it
+// counts nothing, so a request refused on one key doesn't use up another's quota, and
+// reports how long the caller must wait.
+func (l *rateLimiter) allow(keys []string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var wait time.Duration
+	windows := make([]*window, len(keys))
+	for i, key := range keys {
+		w, ok := l.windows[key]
+		if !ok || now.Sub(w.start) >= l.period {
+			w = &window{start: now}
+			l.windows[key] = w
+		}
+		if w.count >= l.limit {
+			wait = max(wait, w.start.Add(l.period).Sub(now))
+		}
+		windows[i] = w
+	}
+	if wait > 0 {
+		return false, wait
+	}
+	for _, w := range windows {
+		w.count++
+	}
+	return true, 0
+}
+
+// sweep drops expired windows so the map doesn't grow forever.
+func (l *rateLimiter) sweep(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, w := range l.windows {
+		if now.Sub(w.start) >= l.period {
+			delete(l.windows, key)
+		}
+	}
+}
+
+// RateLimit allows at most `limit` requests per `period` for each key produced by keyFuncs.
+// Every key is counted independently, so RateLimit(5, time.Hour, ByUser, ByIP) stops both
+// one account hopping IPs and one IP cycling through accounts.
+func RateLimit(limit int, period time.Duration, keyFuncs ...KeyFunc) gin.HandlerFunc {
+	limiter := &rateLimiter{limit: limit, period: period, windows: make(map[string]*window)}
+
+	go func() {
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			limiter.sweep(now)
+		}
+	}()
+
+	return func(c *gin.Context) {
+		var keys []string
+		for _, keyFn := range keyFuncs {
+			if key := keyFn(c); key != "" {
+				keys = append(keys, key)
+			}
+		}
+		if ok, retryAfter := limiter.allow(keys, time.Now()); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests, please slow down"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimitCountsOnlyAllowedRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/inquiry", func(c *gin.Context) {
+		c.Set("user_id", c.GetHeader("X-User"))
+	}, RateLimit(2, time.Hour, ByUser, ByIP), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	post := func(user, ip string) int {
+		req := httptest.NewRequest("POST", "/inquiry", nil)
+		req.Header.Set("X-User", user)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// One address uses up its quota
+	for i := 0; i < 2; i++ {
+		if code := post("baraka", "10.0.0.1"); code != http.StatusOK {
+			t.Fatalf("request %d: status %d, want 200", i+1, code)
+		}
+	}
+	// Refused on the address: that must not count against the account
+	for i := 0; i < 3; i++ {
+		if code := post("amina", "10.0.0.1"); code != http.StatusTooManyRequests {
+			t.Fatalf("over the address limit: status %d, want 429", code)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if code := post("amina", "10.0.0.2"); code != http.StatusOK {
+			t.Fatalf("same account from another address, request %d: status %d, want 200", i+1, code)
+		}
+	}
+	if code := post("amina", "10.0.0.3"); code != http.StatusTooManyRequests {
+		t.Fatalf("over the account limit: status %d, want 429", code)
+	}
+}
+
+func TestRateLimitSetsRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", RateLimit(1, time.Minute, ByIP), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	var w *httptest.ResponseRecorder
+	for i := 0; i < 2; i++ {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	}
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "60" {
+		t.Fatalf("status %d, Retry-After %q; want 429 and 60", w.Code, w.Header().Get("Retry-After"))
+	}
+}
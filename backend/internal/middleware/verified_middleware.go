@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireVerifiedEmail blocks users who haven't verified their email from routes that
+// reach other people (comments, inquiries, chat...). It must run after Authorize.
+//
+// isVerified is injected (usually UserRepository.IsEmailVerified) to keep this package free of DB access.
+func RequireVerifiedEmail(isVerified func(userID interface{}) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID, exists := c.Get("user_id"); exists && !isVerified(userID) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Please verify your email address first",
+				"code":  "email_unverified",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
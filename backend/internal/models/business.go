@@ -47,6 +47,9 @@ type Business struct {
 	LikeCount    int64 `gorm:"-" json:"like_count"`
 	CommentCount int64 `gorm:"-" json:"comment_count"`
 
+	// ReactionCounts breaks LikeCount down by reaction type, e.g. {"like": 10, "inspiring": 3}.
+	ReactionCounts map[string]int64 `gorm:"-" json:"reaction_counts,omitempty"`
+
 	// Timestamps are automatically managed by GORM if named CreatedAt and UpdatedAt.
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Roles a user can hold within a business. What each may do is defined in authz.MemberPermissions.
+const (
+	MemberRoleOwner   = "owner"   // Exactly one per business; mirrors Business.OwnerID
+	MemberRoleManager = "manager" // Runs the business day to day, including the team
+	MemberRoleEditor  = "editor"  // Keeps the profile up to date
+	MemberRoleViewer  = "viewer"  // Read-only access to the dashboard
+)
+
+// ValidMemberRole reports whether role can be given to a team member. Ownership
+// changes hands through a transfer, so owner isn't one of them.
+func ValidMemberRole(role string) bool {
+	return role == MemberRoleManager || role == MemberRoleEditor || role == MemberRoleViewer
+}
+
+// BusinessMember gives a user a role in a business's team.
+type BusinessMember struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;" json:"id"`
+	BusinessID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_member_business_user" json:"business_id"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_member_business_user;index" json:"user_id"`
+	Role       string    `gorm:"size:20;not null" json:"role"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"user"`
+}
+
+func (m *BusinessMember) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return
+}
+
+// BusinessInvitation is an emailed offer to join a business's team. Like other emailed
+// tokens only a SHA-256 hash is stored. It must be accepted by an account with the same email.
+type BusinessInvitation struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;" json:"id"`
+	BusinessID uuid.UUID  `gorm:"type:uuid;not null;index" json:"business_id"`
+	Email      string     `gorm:"size:255;not null" json:"email"`
+	Role       string     `gorm:"size:20;not null" json:"role"`
+	TokenHash  string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	InvitedBy  uuid.UUID  `gorm:"type:uuid" json:"invited_by"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (i *BusinessInvitation) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BusinessView remembers who last had a view of a business counted, so reloading a profile
+// or scripting requests against it counts once per viewer per day. ViewerKey is
+// "user:<id>" for signed-in visitors and "ip:<address>" for everyone else.
+type BusinessView struct {
+	BusinessID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ViewerKey  string    `gorm:"size:100;primaryKey"`
+	CountedAt  time.Time `gorm:"not null;index"`
+}
@@ -29,10 +29,41 @@ type Comment struct {
 	// Using *uuid.UUID (pointer) allows for null values.
 	ParentID *uuid.UUID `gorm:"type:uuid;index" json:"parent_id"`
 
+	// RootID is the top-level comment of the thread (nil for top-level comments).
+	// It lets us load a whole thread with one query instead of walking parent links.
+	RootID *uuid.UUID `gorm:"type:uuid;index" json:"root_id"`
+
+	// Depth is the nesting level: 0 for top-level comments, 1 for direct replies, etc.
+	Depth int `gorm:"default:0" json:"depth"`
+
 	// Content is the actual comment text.
 	// Using type:text allows for longer comments.
 	Content string `gorm:"type:text;not null" json:"content"`
 
+	// IsQuarantined hides the comment until an admin reviews it (set by the spam checks).
+	IsQuarantined bool `gorm:"default:false;index" json:"is_quarantined,omitempty"`
+
+	// IsHidden is set by the business owner or an admin to take a comment out of public view.
+	// Hidden comments don't count towards the health score.
+	IsHidden bool       `gorm:"default:false;index" json:"is_hidden"`
+	HiddenBy *uuid.UUID `gorm:"type:uuid" json:"-"`
+
+	// IsPinned keeps a top-level comment at the top of the business profile.
+	IsPinned bool `gorm:"default:false" json:"is_pinned"`
+
+	// EditedAt is set when the author changes the content; it drives the "edited" marker.
+	EditedAt *time.Time `json:"edited_at"`
+
+	// DeletedAt marks a soft-deleted comment. It is deliberately a plain timestamp rather than
+	// gorm.DeletedAt so deleted comments still load and keep their replies attached to the thread.
+	DeletedAt *time.Time `gorm:"index" json:"deleted_at"`
+
+	// Replies is populated when building a thread tree (not stored).
+	Replies []Comment `gorm:"-" json:"replies,omitempty"`
+
+	// ReplyCount is the number of visible direct replies (populated with Replies).
+	ReplyCount int `gorm:"-" json:"reply_count"`
+
 	// CreatedAt records when the comment was created.
 	CreatedAt time.Time `json:"created_at"`
 
@@ -42,7 +73,9 @@ type Comment struct {
 
 // BeforeCreate is a GORM hook that generates a UUID before inserting.
 func (c *Comment) BeforeCreate(tx *gorm.DB) (err error) {
-	c.ID = uuid.New()
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
 	return
 }
 
@@ -50,3 +83,23 @@ func (c *Comment) BeforeCreate(tx *gorm.DB) (err error) {
 func (Comment) TableName() string {
 	return "comments"
 }
+
+// CommentRevision keeps the previous content of a comment each time its author edits it.
+type CommentRevision struct {
+	ID uuid.UUID `gorm:"type:uuid;primaryKey;" json:"id"`
+
+	// CommentID is the comment that was edited.
+	CommentID uuid.UUID `gorm:"type:uuid;not null;index" json:"comment_id"`
+
+	// Content is the text as it was before the edit.
+	Content string `gorm:"type:text;not null" json:"content"`
+
+	// CreatedAt is when the edit replaced this content.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BeforeCreate is a GORM hook that generates a UUID before inserting.
+func (r *CommentRevision) BeforeCreate(tx *gorm.DB) (err error) {
+	r.ID = uuid.New()
+	return
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReportReason is why a user flagged a comment.
+type ReportReason string
+
+const (
+	ReportReasonSpam           ReportReason = "spam"
+	ReportReasonHarassment     ReportReason = "harassment"
+	ReportReasonHate           ReportReason = "hate"
+	ReportReasonMisinformation ReportReason = "misinformation"
+	ReportReasonOffTopic       ReportReason = "off_topic"
+	ReportReasonOther          ReportReason = "other"
+)
+
+// ValidReportReasons lists the reasons a report may be filed with.
+var ValidReportReasons = map[ReportReason]bool{
+	ReportReasonSpam:           true,
+	ReportReasonHarassment:     true,
+	ReportReasonHate:           true,
+	ReportReasonMisinformation: true,
+	ReportReasonOffTopic:       true,
+	ReportReasonOther:          true,
+}
+
+// ReportStatus tracks an admin's handling of a report.
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusResolved  ReportStatus = "resolved"  // Action was taken on the comment
+	ReportStatusDismissed ReportStatus = "dismissed" // The comment was fine
+)
+
+// CommentReport is a user's flag on an abusive comment.
+// Each user can report a given comment once.
+type CommentReport struct {
+	ID uuid.UUID `gorm:"type:uuid;primaryKey;" json:"id"`
+
+	CommentID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_report_comment_reporter" json:"comment_id"`
+	ReporterID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_report_comment_reporter" json:"reporter_id"`
+
+	Reason  ReportReason `gorm:"size:30;not null" json:"reason"`
+	Details string       `gorm:"type:text" json:"details"`
+
+	Status     ReportStatus `gorm:"size:20;default:'open';index" json:"status"`
+	ResolvedBy *uuid.UUID   `gorm:"type:uuid" json:"resolved_by"`
+	ResolvedAt *time.Time   `json:"resolved_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (r *CommentReport) BeforeCreate(tx *gorm.DB) (err error) {
+	r.ID = uuid.New()
+	return
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Outbox email statuses.
+const (
+	EmailStatusPending    = "pending"
+	EmailStatusSent       = "sent"
+	EmailStatusFailed     = "failed"     // Last attempt failed; the job queue may still retry it
+	EmailStatusSuppressed = "suppressed" // Not sent: the address bounced or complained
+)
+
+// OutboxEmail is a rendered email waiting for (or done with) delivery. Callers holding a
+// transaction write it in that transaction, so the email commits or rolls back with the
+// change. Mail queued from an event subscriber is written after the change has committed;
+// if that write fails the change stands, and the event bus logs the failure.
+type OutboxEmail struct {
+	ID       uuid.UUID         `gorm:"type:uuid;primaryKey;" json:"id"`
+	To       string            `gorm:"size:255;not null;index" json:"to"`
+	ReplyTo  string            `gorm:"size:255" json:"reply_to,omitempty"`
+	Subject  string            `gorm:"size:255;not null" json:"subject"`
+	Text     string            `gorm:"type:text" json:"text"`
+	HTML     string            `gorm:"type:text" json:"-"`
+	Headers  map[string]string `gorm:"serializer:json;type:text" json:"headers,omitempty"`
+	Template string            `gorm:"size:50;index" json:"template"` // Template name, empty for ad-hoc messages
+	Locale   string            `gorm:"size:10" json:"locale"`
+
+	Status    string     `gorm:"size:20;not null;default:'pending';index" json:"status"`
+	Attempts  int        `gorm:"default:0" json:"attempts"`
+	LastError string     `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (e *OutboxEmail) BeforeCreate(tx *gorm.DB) (err error) {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return
+}
+
+// Suppression reasons.
+const (
+	SuppressionBounce    = "bounce"
+	SuppressionComplaint = "complaint"
+	SuppressionManual    = "manual"
+)
+
+// EmailSuppression is an address we must not send to: it hard-bounced, the recipient
+// marked us as spam, or an admin blocked it.
+type EmailSuppression struct {
+	Email     string    `gorm:"size:255;primaryKey" json:"email"` // Stored lower-cased
+	Reason    string    `gorm:"size:20;not null" json:"reason"`
+	Detail    string    `gorm:"type:text" json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// HealthSnapshot is a numeric record of a business's health score at a point in time.
+// Snapshots feed the history/trend charts on the owner dashboard.
+type HealthSnapshot struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;" json:"id"`
+	BusinessID uuid.UUID `gorm:"type:uuid;not null;index:idx_health_snapshots_business_time,priority:1" json:"business_id"`
+
+	// Score is the normalized 0-100 score; Raw is the weighted sum before normalization.
+	Score int     `gorm:"not null" json:"score"`
+	Raw   float64 `json:"raw"`
+
+	// Components is the JSON-encoded scoring breakdown at the time of the snapshot.
+	Components string `gorm:"type:text" json:"components"`
+
+	CreatedAt time.Time `gorm:"index:idx_health_snapshots_business_time,priority:2" json:"created_at"`
+}
+
+func (h *HealthSnapshot) BeforeCreate(tx *gorm.DB) (err error) {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	return
+}
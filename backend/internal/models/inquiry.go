@@ -55,13 +55,18 @@ type Inquiry struct {
 	// Defaults to 'pending' when created.
 	Status InquiryStatus `gorm:"size:20;default:'pending'" json:"status"`
 
+	// IsQuarantined hides the inquiry from the business until an admin reviews it.
+	IsQuarantined bool `gorm:"default:false;index" json:"is_quarantined,omitempty"`
+
 	// CreatedAt records when the inquiry was sent.
 	CreatedAt time.Time `json:"created_at"`
 }
 
 // BeforeCreate is a GORM hook that generates a UUID before inserting.
 func (i *Inquiry) BeforeCreate(tx *gorm.DB) (err error) {
-	i.ID = uuid.New()
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
 	return
 }
 
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// JobStatus tracks a background job through the queue.
+type JobStatus string
+
+const (
+	JobStatusPending JobStatus = "pending" // Waiting for RunAt
+	JobStatusRunning JobStatus = "running" // Claimed by a worker
+	JobStatusDone    JobStatus = "done"
+	JobStatusDead    JobStatus = "dead" // Out of attempts; needs an admin to retry
+)
+
+// Job is a unit of background work persisted in Postgres so it survives restarts.
+// Workers claim jobs with SELECT ... FOR UPDATE SKIP LOCKED, so several processes can share the table.
+type Job struct {
+	ID     uuid.UUID `gorm:"type:uuid;primaryKey;" json:"id"`
+	Type   string    `gorm:"size:50;not null;index;uniqueIndex:idx_jobs_pending_dedupe,priority:1" json:"type"` // e.g. "health.recompute"
+	Status JobStatus `gorm:"size:20;not null;default:'pending';index:idx_jobs_status_run_at,priority:1" json:"status"`
+
+	// Payload is the JSON-encoded job arguments.
+	Payload string `gorm:"type:text" json:"payload"`
+
+	// DedupeKey, when set, stops a second pending job with the same type and key from being
+	// queued. A partial unique index enforces it, so concurrent enqueues can't both get in.
+	DedupeKey string `gorm:"size:120;index;uniqueIndex:idx_jobs_pending_dedupe,priority:2,where:status = 'pending' AND dedupe_key <> ''" json:"dedupe_key,omitempty"`
+
+	Attempts    int    `gorm:"default:0" json:"attempts"`
+	MaxAttempts int    `gorm:"default:5" json:"max_attempts"`
+	LastError   string `gorm:"type:text" json:"last_error,omitempty"`
+
+	RunAt       time.Time  `gorm:"not null;index:idx_jobs_status_run_at,priority:2" json:"run_at"`
+	LockedAt    *time.Time `json:"locked_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (j *Job) BeforeCreate(tx *gorm.DB) (err error) {
+	if j.ID == uuid.Nil {
+		j.ID = uuid.New()
+	}
+	return
+}
@@ -7,25 +7,46 @@ import (
 	"gorm.io/gorm"
 )
 
-// Like represents a user's like on a business.
-// Users can like businesses to show appreciation and help with ranking.
+// ReactionType is the kind of reaction a user leaves on a business.
+// A plain "like" is the default; the others let people say why.
+type ReactionType string
+
+const (
+	ReactionLike          ReactionType = "like"
+	ReactionInspiring     ReactionType = "inspiring"
+	ReactionWantToPartner ReactionType = "want_to_partner"
+)
+
+// ValidReactionTypes lists the reactions the API accepts.
+var ValidReactionTypes = map[ReactionType]bool{
+	ReactionLike:          true,
+	ReactionInspiring:     true,
+	ReactionWantToPartner: true,
+}
+
+// Like represents a user's reaction on a business.
+// Users can react to businesses to show appreciation and help with ranking.
 //
-// A unique constraint on (user_id, business_id) ensures one like per user per business.
+// A unique index on (user_id, business_id) ensures one reaction per user per business;
+// changing the reaction type updates the existing row.
 type Like struct {
 	// ID is the unique identifier for the like.
 	ID uuid.UUID `gorm:"type:uuid;primaryKey;" json:"id"`
 
 	// UserID is the ID of the user who liked the business.
 	// Indexed for efficient queries of "what did this user like?"
-	UserID uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_likes_user_business" json:"user_id"`
 
 	// BusinessID is the ID of the business that was liked.
 	// Indexed for efficient queries of "who liked this business?"
-	BusinessID uuid.UUID `gorm:"type:uuid;not null;index" json:"business_id"`
+	BusinessID uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_likes_user_business" json:"business_id"`
 
 	// Business is the associated business record.
 	Business Business `gorm:"foreignKey:BusinessID" json:"-"`
 
+	// Type is the reaction kind. Existing rows (from before typed reactions) default to "like".
+	Type ReactionType `gorm:"size:30;not null;default:'like'" json:"type"`
+
 	// CreatedAt records when the like was created.
 	CreatedAt time.Time `json:"created_at"`
 }
@@ -37,7 +58,6 @@ func (l *Like) BeforeCreate(tx *gorm.DB) (err error) {
 }
 
 // TableName overrides the default table name.
-// We also define a unique constraint here via migration if needed.
 func (Like) TableName() string {
 	return "likes"
 }
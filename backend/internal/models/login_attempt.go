@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Login attempt outcomes.
+const (
+	LoginSucceeded          = "success"
+	LoginInvalidCredentials = "invalid_credentials"
+	LoginThrottled          = "throttled"          // Refused before checking the password
+	LoginTwoFactorPending   = "two_factor_pending" // Password was right; the second factor is next
+	LoginTwoFactorFailed    = "two_factor_failed"
+)
+
+// LoginAttempt is the audit record of one attempt to sign in.
+type LoginAttempt struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;" json:"id"`
+	UserID    *uuid.UUID `gorm:"type:uuid;index" json:"user_id,omitempty"` // Nil when no account has the email
+	Email     string     `gorm:"size:255;index" json:"email"`
+	IP        string     `gorm:"size:45;index" json:"ip"`
+	UserAgent string     `gorm:"size:255" json:"user_agent"`
+	Method    string     `gorm:"size:20" json:"method"` // "password", "two_factor", or an identity provider
+	Outcome   string     `gorm:"size:30;index" json:"outcome"`
+	Success   bool       `json:"success"`
+	CreatedAt time.Time  `gorm:"index" json:"created_at"`
+}
+
+func (a *LoginAttempt) BeforeCreate(tx *gorm.DB) (err error) {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return
+}
+
+// LoginThrottle counts recent failed logins for one account ("account:<email>") or one
+// IP address ("ip:<ip>"). Failures past a few make the next attempt wait longer and longer.
+type LoginThrottle struct {
+	ThrottleKey   string     `gorm:"size:300;primaryKey"`
+	Failures      int        `gorm:"not null;default:0"`
+	LastFailureAt time.Time  `gorm:"not null"`
+	LockedUntil   *time.Time // Set when an account is locked after too many failures
+	NotifiedAt    *time.Time // When the account's owner was last emailed about a lock
+}
@@ -6,10 +6,53 @@ import (
 	"gorm.io/gorm"
 )
 
+// Newsletter topics a subscriber can opt in to.
+const (
+	NewsletterTopicEvents         = "events"
+	NewsletterTopicNews           = "news"
+	NewsletterTopicFounderStories = "founder_stories"
+)
+
+// NewsletterTopics lists every topic. New subscribers get all of them unless they choose.
+var NewsletterTopics = []string{NewsletterTopicEvents, NewsletterTopicNews, NewsletterTopicFounderStories}
+
+// NewsletterSubscriber is an address on the newsletter list. It only receives issues once
+// ConfirmedAt is set (double opt-in); unsubscribing soft-deletes the row.
 type NewsletterSubscriber struct {
-	ID        string         `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	Email     string         `gorm:"uniqueIndex;not null" json:"email"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID          string         `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Email       string         `gorm:"uniqueIndex;not null" json:"email"`
+	Topics      []string       `gorm:"serializer:json;type:text" json:"topics"`
+	Locale      string         `gorm:"size:10;default:'en'" json:"locale"`
+	ConfirmedAt *time.Time     `gorm:"index" json:"confirmed_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// HasTopic reports whether the subscriber wants issues about topic. Subscribers from
+// before topics existed have none recorded and receive everything.
+func (s *NewsletterSubscriber) HasTopic(topic string) bool {
+	if len(s.Topics) == 0 {
+		return true
+	}
+	for _, t := range s.Topics {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidNewsletterTopics reports whether every topic is known.
+func ValidNewsletterTopics(topics []string) bool {
+	for _, t := range topics {
+		known := false
+		for _, k := range NewsletterTopics {
+			known = known || t == k
+		}
+		if !known {
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IssueStatus tracks a newsletter issue from draft to sent.
+type IssueStatus string
+
+const (
+	IssueStatusDraft     IssueStatus = "draft"
+	IssueStatusScheduled IssueStatus = "scheduled"
+	IssueStatusSending   IssueStatus = "sending" // Batches are going out
+	IssueStatusSent      IssueStatus = "sent"
+	IssueStatusCancelled IssueStatus = "cancelled" // Stopped part-way; remaining recipients are skipped
+)
+
+// IssueItem is one entry in a newsletter section: a news story, event, business or blog post.
+type IssueItem struct {
+	Title    string `json:"title"`
+	Summary  string `json:"summary,omitempty"`
+	URL      string `json:"url"`
+	ImageURL string `json:"image_url,omitempty"`
+	Meta     string `json:"meta,omitempty"` // Secondary line, e.g. an event's date and location
+}
+
+// IssueSection groups items under a heading. Subscribers only get the sections whose
+// Topic they follow.
+type IssueSection struct {
+	Key   string      `json:"key"` // news, events, businesses, blogs
+	Topic string      `json:"topic"`
+	Title string      `json:"title"`
+	Items []IssueItem `json:"items"`
+}
+
+// NewsletterIssue is one edition of the newsletter. It is composed from platform content,
+// edited by an admin, then sent in batches to confirmed subscribers.
+type NewsletterIssue struct {
+	ID       uuid.UUID      `gorm:"type:uuid;primaryKey;" json:"id"`
+	Subject  string         `gorm:"size:255;not null" json:"subject"`
+	Intro    string         `gorm:"type:text" json:"intro"`
+	Sections []IssueSection `gorm:"serializer:json;type:text" json:"sections"`
+
+	Status      IssueStatus `gorm:"size:20;not null;default:'draft';index" json:"status"`
+	ScheduledAt *time.Time  `json:"scheduled_at"`
+	StartedAt   *time.Time  `json:"started_at,omitempty"`
+	SentAt      *time.Time  `json:"sent_at,omitempty"`
+
+	CreatedBy uuid.UUID `gorm:"type:uuid" json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (i *NewsletterIssue) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return
+}
+
+// Topics returns the topics covered by the issue's non-empty sections.
+func (i *NewsletterIssue) Topics() []string {
+	seen := map[string]bool{}
+	var topics []string
+	for _, s := range i.Sections {
+		if len(s.Items) > 0 && !seen[s.Topic] {
+			seen[s.Topic] = true
+			topics = append(topics, s.Topic)
+		}
+	}
+	return topics
+}
+
+// Editable reports whether the issue can still be changed.
+func (i *NewsletterIssue) Editable() bool {
+	return i.Status == IssueStatusDraft || i.Status == IssueStatusScheduled
+}
+
+// Delivery statuses.
+const (
+	DeliveryStatusPending = "pending"
+	DeliveryStatusQueued  = "queued"  // Handed to the email outbox
+	DeliveryStatusSkipped = "skipped" // Unsubscribed or cancelled before their batch
+)
+
+// NewsletterDelivery is one recipient of an issue. It drives batching and records
+// opens and clicks.
+type NewsletterDelivery struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey;" json:"id"`
+	IssueID      uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_delivery_issue_subscriber;index:idx_delivery_issue_status,priority:1" json:"issue_id"`
+	SubscriberID string     `gorm:"type:uuid;not null;uniqueIndex:idx_delivery_issue_subscriber" json:"subscriber_id"`
+	Email        string     `gorm:"size:255;not null" json:"email"`
+	Status       string     `gorm:"size:20;not null;default:'pending';index:idx_delivery_issue_status,priority:2" json:"status"`
+	QueuedAt     *time.Time `json:"queued_at,omitempty"`
+	OpenedAt     *time.Time `json:"opened_at,omitempty"`
+	ClickedAt    *time.Time `json:"clicked_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func (d *NewsletterDelivery) BeforeCreate(tx *gorm.DB) (err error) {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return
+}
+
+// NewsletterClick records a click on a tracked link.
+type NewsletterClick struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;" json:"id"`
+	DeliveryID uuid.UUID `gorm:"type:uuid;not null;index" json:"delivery_id"`
+	IssueID    uuid.UUID `gorm:"type:uuid;not null;index" json:"issue_id"`
+	URL        string    `gorm:"size:1000;not null" json:"url"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (c *NewsletterClick) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
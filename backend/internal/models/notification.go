@@ -7,15 +7,37 @@ import (
 	"gorm.io/gorm"
 )
 
+// Notification types.
+const (
+	NotificationTypeMeeting       = "meeting"
+	NotificationTypeInquiry       = "inquiry"
+	NotificationTypeInquiryStatus = "inquiry_status"
+	NotificationTypeComment       = "comment"
+	NotificationTypeLike          = "like"
+	NotificationTypeSystem        = "system"
+)
+
 type Notification struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey;" json:"id"`
-	UserID    uuid.UUID `gorm:"type:uuid;index;not null" json:"user_id"`
-	Title     string    `gorm:"size:255;not null" json:"title"`
-	Message   string    `gorm:"type:text;not null" json:"message"`
-	Type      string    `gorm:"size:50" json:"type"` // "meeting", "inquiry", "comment", "system"
-	IsRead    bool      `gorm:"default:false" json:"is_read"`
-	Link      string    `json:"link"` // Optional link to redirect user
+	ID      uuid.UUID `gorm:"type:uuid;primaryKey;" json:"id"`
+	UserID  uuid.UUID `gorm:"type:uuid;index;not null" json:"user_id"`
+	Title   string    `gorm:"size:255;not null" json:"title"`
+	Message string    `gorm:"type:text;not null" json:"message"`
+	Type    string    `gorm:"size:50" json:"type"` // See the NotificationType constants
+	IsRead  bool      `gorm:"default:false" json:"is_read"`
+	Link    string    `json:"link"` // Optional link to redirect user
+
+	// GroupKey merges repeat notifications while they are unread, e.g. every like on a
+	// business in one day becomes "5 people liked your business today".
+	GroupKey string `gorm:"size:120;index" json:"group_key,omitempty"`
+	Count    int    `gorm:"default:1" json:"count"`
+	// ActorIDs lists who has been counted in the group (comma separated) so repeats aren't double counted.
+	ActorIDs string `gorm:"type:text" json:"-"`
+
+	// ArchivedAt hides the notification from the inbox without deleting it.
+	ArchivedAt *time.Time `gorm:"index" json:"archived_at,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"` // Last time the group grew
 }
 
 func (n *Notification) BeforeCreate(tx *gorm.DB) (err error) {
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Notification delivery channels. SMS and WhatsApp can be configured but aren't delivered yet.
+const (
+	ChannelInApp    = "in_app"
+	ChannelEmail    = "email"
+	ChannelSMS      = "sms"
+	ChannelWhatsApp = "whatsapp"
+)
+
+// NotificationChannels lists every channel a preference can target.
+var NotificationChannels = []string{ChannelInApp, ChannelEmail, ChannelSMS, ChannelWhatsApp}
+
+// NotificationTypes lists every notification type a user can configure.
+var NotificationTypes = []string{
+	NotificationTypeInquiry,
+	NotificationTypeInquiryStatus,
+	NotificationTypeMeeting,
+	NotificationTypeComment,
+	NotificationTypeLike,
+	NotificationTypeSystem,
+}
+
+// Digest frequencies.
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// NotificationPreference overrides the default for one (type, channel) pair.
+// Users without a row get DefaultChannelEnabled.
+type NotificationPreference struct {
+	ID      uuid.UUID `gorm:"type:uuid;primaryKey;" json:"-"`
+	UserID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_notif_pref_user_type_channel,priority:1" json:"-"`
+	Type    string    `gorm:"size:50;not null;uniqueIndex:idx_notif_pref_user_type_channel,priority:2" json:"type"`
+	Channel string    `gorm:"size:20;not null;uniqueIndex:idx_notif_pref_user_type_channel,priority:3" json:"channel"`
+	Enabled bool      `gorm:"not null" json:"enabled"`
+}
+
+func (p *NotificationPreference) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return
+}
+
+// DefaultChannelEnabled is the setting for users who haven't chosen: everything in-app,
+// and immediate email only for inquiries and meetings. Everything else reaches email via the digest.
+func DefaultChannelEnabled(notifType, channel string) bool {
+	switch channel {
+	case ChannelInApp:
+		return true
+	case ChannelEmail:
+		return notifType == NotificationTypeInquiry || notifType == NotificationTypeMeeting
+	default:
+		return false
+	}
+}
+
+// NotificationSettings holds a user's quiet hours and digest schedule.
+type NotificationSettings struct {
+	UserID uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+
+	// Quiet hours as "HH:MM" in Timezone. Empty disables them. The window may cross midnight (22:00-07:00).
+	QuietHoursStart string `gorm:"size:5" json:"quiet_hours_start"`
+	QuietHoursEnd   string `gorm:"size:5" json:"quiet_hours_end"`
+	Timezone        string `gorm:"size:64;default:'Africa/Nairobi'" json:"timezone"`
+
+	DigestFrequency string     `gorm:"size:10;default:'weekly'" json:"digest_frequency"`
+	LastDigestAt    *time.Time `json:"last_digest_at"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultNotificationSettings applies to users who haven't saved any settings.
+func DefaultNotificationSettings(userID uuid.UUID) NotificationSettings {
+	return NotificationSettings{UserID: userID, Timezone: "Africa/Nairobi", DigestFrequency: DigestWeekly}
+}
+
+// ParseClock parses an "HH:MM" time of day into minutes after midnight.
+func ParseClock(s string) (int, error) {
+	var h, m int
+	if _, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil || h < 0 || h > 23 || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return h*60 + m, nil
+}
+
+// QuietUntil reports whether t falls inside the quiet hours and, if so, when they end.
+func (s NotificationSettings) QuietUntil(t time.Time) (time.Time, bool) {
+	if s.QuietHoursStart == "" || s.QuietHoursEnd == "" {
+		return time.Time{}, false
+	}
+	start, err1 := ParseClock(s.QuietHoursStart)
+	end, err2 := ParseClock(s.QuietHoursEnd)
+	if err1 != nil || err2 != nil || start == end {
+		return time.Time{}, false
+	}
+
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	now := local.Hour()*60 + local.Minute()
+
+	var quiet bool
+	if start < end {
+		quiet = now >= start && now < end
+	} else { // Crosses midnight
+		quiet = now >= start || now < end
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	until := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, loc)
+	if !until.After(local) {
+		until = until.AddDate(0, 0, 1)
+	}
+	return until, true
+}
+
+// DigestPeriod is how far apart digests are sent; zero when digests are off.
+func (s NotificationSettings) DigestPeriod() time.Duration {
+	switch s.DigestFrequency {
+	case DigestDaily:
+		return 24 * time.Hour
+	case DigestWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
@@ -7,19 +7,80 @@ import (
 	"gorm.io/gorm"
 )
 
+// Platform inquiry lifecycle, driven by the admin triage console.
+const (
+	PlatformInquiryStatusPending    = "pending"     // New, nobody has looked at it yet
+	PlatformInquiryStatusInProgress = "in_progress" // Assigned and being worked on
+	PlatformInquiryStatusResponded  = "responded"   // We replied, waiting on the sender
+	PlatformInquiryStatusClosed     = "closed"      // Done
+)
+
 type PlatformInquiry struct {
+	ID      uuid.UUID `gorm:"type:uuid;primaryKey;" json:"id"`
+	Name    string    `gorm:"size:100;not null" json:"name"`
+	Email   string    `gorm:"size:100;not null" json:"email"`
+	Subject string    `gorm:"size:255;not null" json:"subject"`
+	Message string    `gorm:"type:text;not null" json:"message"`
+	Status  string    `gorm:"size:20;default:'pending';index" json:"status"`
+	// IsQuarantined marks submissions the spam checks held back for review.
+	IsQuarantined bool `gorm:"default:false;index" json:"is_quarantined,omitempty"`
+
+	// AssignedTo is the admin currently handling the inquiry.
+	AssignedTo *uuid.UUID `gorm:"type:uuid;index" json:"assigned_to"`
+	Assignee   *User      `gorm:"foreignKey:AssignedTo" json:"assignee,omitempty"`
+
+	Replies []PlatformInquiryReply `gorm:"foreignKey:InquiryID" json:"replies,omitempty"`
+
+	RespondedAt *time.Time `json:"responded_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (p *PlatformInquiry) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return
+}
+
+// PlatformInquiryReply is a response an admin sent back to the person who contacted us.
+type PlatformInquiryReply struct {
 	ID        uuid.UUID `gorm:"type:uuid;primaryKey;" json:"id"`
-	Name      string    `gorm:"size:100;not null" json:"name"`
-	Email     string    `gorm:"size:100;not null" json:"email"`
-	Subject   string    `gorm:"size:255;not null" json:"subject"`
-	Message   string    `gorm:"type:text;not null" json:"message"`
-	Status    string    `gorm:"size:20;default:'pending'" json:"status"`
+	InquiryID uuid.UUID `gorm:"type:uuid;not null;index" json:"inquiry_id"`
+	AuthorID  uuid.UUID `gorm:"type:uuid;not null" json:"author_id"`
+	Author    User      `gorm:"foreignKey:AuthorID" json:"author"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+
+	// Email is the outbox message that carries the reply to the sender. Its status shows
+	// whether the reply went out, failed (the job queue retries it) or was suppressed.
+	OutboxEmailID *uuid.UUID   `gorm:"type:uuid" json:"-"`
+	Email         *OutboxEmail `gorm:"foreignKey:OutboxEmailID" json:"email,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
 }
 
-func (p *PlatformInquiry) BeforeCreate(tx *gorm.DB) (err error) {
+func (p *PlatformInquiryReply) BeforeCreate(tx *gorm.DB) (err error) {
 	if p.ID == uuid.Nil {
 		p.ID = uuid.New()
 	}
 	return
 }
+
+// CannedResponse is a reusable reply template for common platform inquiries.
+// Body may contain {{name}}, {{email}} and {{subject}} placeholders.
+type CannedResponse struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;" json:"id"`
+	Title     string    `gorm:"size:100;not null;uniqueIndex" json:"title"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	CreatedBy uuid.UUID `gorm:"type:uuid" json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (r *CannedResponse) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// QuarantineStatus tracks an admin's review of held-back content.
+type QuarantineStatus string
+
+const (
+	QuarantineStatusPending  QuarantineStatus = "pending"
+	QuarantineStatusApproved QuarantineStatus = "approved"
+	QuarantineStatusRejected QuarantineStatus = "rejected"
+)
+
+// Entity types that can be quarantined.
+const (
+	QuarantineEntityComment         = "comment"
+	QuarantineEntityInquiry         = "inquiry"
+	QuarantineEntityPlatformInquiry = "platform_inquiry"
+)
+
+// QuarantineEntry records a submission that the spam checks held back for admin review.
+// The content itself stays in its own table with IsQuarantined=true so it is hidden
+// from owners and public listings until approved.
+type QuarantineEntry struct {
+	ID uuid.UUID `gorm:"type:uuid;primaryKey;" json:"id"`
+
+	// EntityType/EntityID point at the held-back record ("comment", "inquiry", "platform_inquiry").
+	EntityType string    `gorm:"size:30;not null;index" json:"entity_type"`
+	EntityID   uuid.UUID `gorm:"type:uuid;not null;index" json:"entity_id"`
+
+	// Who submitted it. UserID is nil for public forms.
+	UserID *uuid.UUID `gorm:"type:uuid;index" json:"user_id"`
+	IP     string     `gorm:"size:64" json:"ip"`
+
+	// Snapshot of the content so reviewers don't need a second lookup.
+	Excerpt   string  `gorm:"type:text" json:"excerpt"`
+	SpamScore float64 `json:"spam_score"`
+	Reasons   string  `gorm:"type:text" json:"reasons"` // Semicolon-separated heuristics that fired
+
+	// IsEdit is set when an edit to an already published comment was held back. Approving it
+	// doesn't announce the comment again; rejecting it deletes the comment but keeps its replies.
+	IsEdit bool `gorm:"not null;default:false" json:"is_edit"`
+
+	Status     QuarantineStatus `gorm:"size:20;default:'pending';index" json:"status"`
+	ReviewedBy *uuid.UUID       `gorm:"type:uuid" json:"reviewed_by"`
+	ReviewedAt *time.Time       `json:"reviewed_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *QuarantineEntry) BeforeCreate(tx *gorm.DB) (err error) {
+	if q.ID == uuid.Nil {
+		q.ID = uuid.New()
+	}
+	return
+}
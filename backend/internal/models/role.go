@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Role is a named set of permissions. Users hold one role (User.Role holds its name).
+// Built-in roles (System) can be edited but not deleted; admin always has every permission.
+// See package authz for the permission format.
+type Role struct {
+	Name        string   `gorm:"size:30;primaryKey" json:"name"`
+	Description string   `gorm:"size:255" json:"description"`
+	Permissions []string `gorm:"serializer:json;type:text" json:"permissions"`
+	System      bool     `gorm:"not null;default:false" json:"system"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
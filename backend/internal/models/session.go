@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Session is one signed-in device. It holds the refresh token that mints short-lived
+// access tokens; revoking the session logs that device out. Refresh tokens rotate on
+// every use and only their SHA-256 hashes are stored.
+type Session struct {
+	ID                uuid.UUID `gorm:"type:uuid;primaryKey;" json:"id"`
+	UserID            uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	RefreshTokenHash  string    `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	PreviousTokenHash string    `gorm:"size:64;index" json:"-"` // The token the current one replaced, to spot reuse
+	UserAgent         string    `gorm:"size:255" json:"user_agent"`
+	IP                string    `gorm:"size:45" json:"ip"`
+
+	// TwoFactorAt is set when the user passed a second factor for this session. Its access
+	// tokens say so, which roles that require two-factor authentication check.
+	TwoFactorAt *time.Time `json:"two_factor_at,omitempty"`
+
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (s *Session) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return
+}
+
+// Active reports whether the session can still be used.
+func (s *Session) Active() bool {
+	return s.RevokedAt == nil && s.ExpiresAt.After(time.Now())
+}
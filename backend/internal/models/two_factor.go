@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TwoFactor is a user's authenticator app enrollment. It exists from the moment the user
+// starts setting it up, but only counts once EnabledAt is set, after they've entered a code.
+type TwoFactor struct {
+	UserID    uuid.UUID  `gorm:"type:uuid;primaryKey" json:"-"`
+	Secret    string     `gorm:"size:255;not null" json:"-"` // Sealed with totp.Seal
+	EnabledAt *time.Time `json:"enabled_at"`
+	LastStep  int64      `gorm:"not null;default:0" json:"-"` // The last time step a code was accepted for; codes can't be replayed
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TableName overrides the default table name (two_factors).
+func (TwoFactor) TableName() string {
+	return "user_two_factors"
+}
+
+// RecoveryCode signs a user in once when they can't use their authenticator app.
+// Only a SHA-256 hash is stored.
+type RecoveryCode struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (r *RecoveryCode) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return
+}
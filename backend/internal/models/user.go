@@ -22,9 +22,46 @@ type User struct {
 	// Defaulting to 'viewer' follows the 'Principle of Least Privilege'.
 	Role      string    `gorm:"type:varchar(20);default:'viewer'" json:"role"` // admin, privileged, viewer
 
+	// TokenVersion is stamped into access tokens. Bumping it (e.g. on a role change)
+	// makes every token issued before unusable; clients refresh to pick up the change.
+	TokenVersion int `gorm:"not null;default:0" json:"-"`
+
 	// Name is the user's full name.
 	Name      string    `gorm:"size:100" json:"name"`
 
+	// Locale picks the language of emails we send ("en", "fr"). Unsupported locales fall back to English.
+	Locale string `gorm:"size:10;default:'en'" json:"locale"`
+
+	// EmailVerifiedAt is set once the user follows the link in their verification email.
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+
+	// BannedAt is set when an admin bans the user from posting. BannedUntil is nil for a permanent ban.
+	BannedAt    *time.Time `json:"banned_at,omitempty"`
+	BannedUntil *time.Time `json:"banned_until,omitempty"`
+	BanReason   string     `gorm:"size:255" json:"ban_reason,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
-}
\ No newline at end of file
+}
+
+// IsBanned reports whether a ban is currently in effect.
+func (u *User) IsBanned() bool {
+	if u.BannedAt == nil {
+		return false
+	}
+	return u.BannedUntil == nil || u.BannedUntil.After(time.Now())
+}
+
+// DisplayName is how emails greet the user: their name, or their email address if they
+// never set one.
+func (u *User) DisplayName() string {
+	if u.Name != "" {
+		return u.Name
+	}
+	return u.Email
+}
+
+// IsVerified reports whether the user has confirmed they own their email address.
+func (u *User) IsVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserIdentity links a user to an account at an external identity provider, so they
+// can sign in with it. A provider account belongs to at most one user.
+type UserIdentity struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;" json:"id"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	Provider   string    `gorm:"size:20;not null;uniqueIndex:idx_identity_provider_subject" json:"provider"`
+	Subject    string    `gorm:"size:255;not null;uniqueIndex:idx_identity_provider_subject" json:"-"`
+	Email      string    `gorm:"size:255" json:"email"` // The provider's address when last used, for display
+	LastUsedAt time.Time `json:"last_used_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (i *UserIdentity) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return
+}
+
+// OAuthState is a sign-in with a provider that has started but not finished. It is
+// keyed by the SHA-256 hash of the state parameter and used once.
+type OAuthState struct {
+	StateHash    string     `gorm:"size:64;primaryKey"`
+	Provider     string     `gorm:"size:20;not null"`
+	CodeVerifier string     `gorm:"size:128;not null"` // PKCE verifier, sent with the code
+	Nonce        string     `gorm:"size:128;not null"` // Must come back in the ID token
+	UserID       *uuid.UUID `gorm:"type:uuid"`         // Set when a signed-in user is linking an account
+	ExpiresAt    time.Time  `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
+
+// TableName overrides the default table name (o_auth_states).
+func (OAuthState) TableName() string {
+	return "oauth_states"
+}
@@ -0,0 +1,333 @@
+package newsletter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/jobs"
+	"github.com/saidimuKennedy/spotlight-africa/internal/mail"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+	"github.com/saidimuKennedy/spotlight-africa/internal/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	purposeOpen  = "newsletter-open"
+	purposeClick = "newsletter-click"
+)
+
+// Campaigns composes newsletter issues and sends them. Sending runs on the job queue:
+// each TypeNewsletterSend job queues one batch of emails through the outbox and, if
+// recipients remain, schedules the next batch after BatchDelay.
+type Campaigns struct {
+	Repo       *repository.NewsletterIssueRepository
+	Outbox     *mail.Outbox
+	Jobs       *jobs.Queue
+	BatchSize  int           // Recipients per batch (default 200)
+	BatchDelay time.Duration // Pause between batches (default 1m)
+}
+
+// Compose drafts an issue from the content published since the given time.
+func (c *Campaigns) Compose(createdBy uuid.UUID, since time.Time) (*models.NewsletterIssue, error) {
+	content, err := c.Repo.Content(since, 5)
+	if err != nil {
+		return nil, err
+	}
+
+	issue := &models.NewsletterIssue{
+		Subject:   "This week on Spotlight Africa: " + time.Now().Format("2 Jan 2006"),
+		Intro:     "Here's what moved across the African business ecosystem this week.",
+		Sections:  sections(content),
+		Status:    models.IssueStatusDraft,
+		CreatedBy: createdBy,
+	}
+	if err := c.Repo.Create(issue); err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// sections turns platform content into the default issue layout. Empty sections are left out.
+func sections(content *repository.IssueContent) []models.IssueSection {
+	var out []models.IssueSection
+	add := func(key, topic, title string, items []models.IssueItem) {
+		if len(items) > 0 {
+			out = append(out, models.IssueSection{Key: key, Topic: topic, Title: title, Items: items})
+		}
+	}
+
+	var news []models.IssueItem
+	for _, n := range content.News {
+		news = append(news, models.IssueItem{
+			Title: n.Title, Summary: truncate(n.Excerpt, 200), URL: mail.Link("/news/" + n.Slug),
+			ImageURL: n.ImageURL, Meta: n.Source,
+		})
+	}
+	add("news", models.NewsletterTopicNews, "Top stories", news)
+
+	var events []models.IssueItem
+	for _, e := range content.Events {
+		link := e.Link
+		if link == "" {
+			link = mail.Link("/events")
+		}
+		meta := e.StartDate.Format("Mon 2 Jan")
+		if e.Location != "" {
+			meta += " · " + e.Location
+		}
+		events = append(events, models.IssueItem{
+			Title: e.Title, Summary: truncate(e.Description, 160), URL: link, ImageURL: e.ImageURL, Meta: meta,
+		})
+	}
+	add("events", models.NewsletterTopicEvents, "Coming up", events)
+
+	var businesses []models.IssueItem
+	for _, b := range content.Businesses {
+		businesses = append(businesses, models.IssueItem{
+			Title: b.Name, Summary: truncate(b.Description, 160), URL: mail.Link("/business/" + b.ID.String()),
+			ImageURL: b.AvatarURL, Meta: b.Industry,
+		})
+	}
+	add("businesses", models.NewsletterTopicFounderStories, "Featured businesses", businesses)
+
+	var blogs []models.IssueItem
+	for _, b := range content.Blogs {
+		blogs = append(blogs, models.IssueItem{
+			Title: b.Title, Summary: truncate(b.Content, 200), URL: mail.Link("/blog/" + b.Slug),
+			ImageURL: b.ImageURL, Meta: b.Author,
+		})
+	}
+	add("blogs", models.NewsletterTopicFounderStories, "From the blog", blogs)
+
+	return out
+}
+
+// Schedule sets an issue to go out at the given time. Rescheduling is allowed until sending starts.
+func (c *Campaigns) Schedule(issueID string, at time.Time) (bool, error) {
+	ok, err := c.Repo.Schedule(issueID, at)
+	if err != nil || !ok {
+		return ok, err
+	}
+	id, _ := uuid.Parse(issueID)
+	return true, c.Jobs.Enqueue(jobs.TypeNewsletterSend, jobs.NewsletterSend{IssueID: id}, jobs.Delay(time.Until(at)))
+}
+
+// Send is the TypeNewsletterSend job handler.
+func (c *Campaigns) Send(ctx context.Context, payload []byte) error {
+	var p jobs.NewsletterSend
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return err
+	}
+
+	issue, err := c.Repo.Get(p.IssueID.String())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch issue.Status {
+	case models.IssueStatusScheduled:
+		// A rescheduled issue leaves its old job behind; the job for the new time takes over.
+		if issue.ScheduledAt == nil || issue.ScheduledAt.After(time.Now()) {
+			return nil
+		}
+		started, recipients, err := c.Repo.StartSending(issue, time.Now())
+		if err != nil || !started {
+			return err
+		}
+		log.Printf("📰 Sending newsletter %q to %d subscribers", issue.Subject, recipients)
+	case models.IssueStatusSending:
+	default:
+		return nil // Cancelled, sent or back in draft
+	}
+
+	queued, err := c.sendBatch(issue)
+	if err != nil {
+		return err
+	}
+	if queued < c.batchSize() {
+		_, err := c.Repo.FinishIfDone(issue.ID)
+		return err
+	}
+	return c.Jobs.Enqueue(jobs.TypeNewsletterSend, p, jobs.Delay(c.batchDelay()))
+}
+
+// sendBatch claims the next batch of recipients and queues their emails in one transaction,
+// so a failure leaves the whole batch pending for the retry. Returns how many were claimed.
+func (c *Campaigns) sendBatch(issue *models.NewsletterIssue) (int, error) {
+	var claimedCount int
+	err := c.Repo.DB.Transaction(func(tx *gorm.DB) error {
+		claimed, err := c.Repo.ClaimDeliveries(tx, issue.ID, c.batchSize())
+		if err != nil || len(claimed) == 0 {
+			return err
+		}
+		claimedCount = len(claimed)
+
+		ids := make([]string, len(claimed))
+		for i, d := range claimed {
+			ids[i] = d.SubscriberID
+		}
+		subs, err := c.Repo.ActiveSubscribers(tx, ids)
+		if err != nil {
+			return err
+		}
+
+		var skipped []uuid.UUID
+		for i := range claimed {
+			d := &claimed[i]
+			sub, ok := subs[d.SubscriberID]
+			if !ok {
+				skipped = append(skipped, d.ID) // Unsubscribed since the send started
+				continue
+			}
+			msg, ok, err := c.Render(issue, &sub, d)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				skipped = append(skipped, d.ID) // Changed topics; nothing left for them
+				continue
+			}
+			if err := c.Outbox.QueueMessage(tx, msg); err != nil {
+				return err
+			}
+		}
+		return c.Repo.MarkSkipped(tx, skipped)
+	})
+	return claimedCount, err
+}
+
+// Render builds the issue as sub will receive it: only the sections for their topics,
+// with tracked links when d is set. ok is false when none of the sections apply.
+func (c *Campaigns) Render(issue *models.NewsletterIssue, sub *models.NewsletterSubscriber, d *models.NewsletterDelivery) (msg mail.Message, ok bool, err error) {
+	var secs []models.IssueSection
+	for _, s := range issue.Sections {
+		if len(s.Items) == 0 || !sub.HasTopic(s.Topic) {
+			continue
+		}
+		if d != nil {
+			items := make([]models.IssueItem, len(s.Items))
+			for i, item := range s.Items {
+				item.URL = ClickURL(d.ID, item.URL)
+				items[i] = item
+			}
+			s.Items = items
+		}
+		secs = append(secs, s)
+	}
+	if len(secs) == 0 {
+		return mail.Message{}, false, nil
+	}
+
+	data := map[string]interface{}{
+		"Subject":   issue.Subject,
+		"Intro":     issue.Intro,
+		"Sections":  secs,
+		"ManageURL": ManageURL(sub.Email),
+		"OpenPixel": "",
+	}
+	if d != nil {
+		data["OpenPixel"] = OpenURL(d.ID)
+	}
+
+	r, err := mail.Render("newsletter_issue", sub.Locale, data)
+	if err != nil {
+		return mail.Message{}, false, err
+	}
+	return mail.Message{
+		To:      sub.Email,
+		Subject: r.Subject,
+		Text:    r.Text,
+		HTML:    r.HTML,
+		Headers: Headers(sub.Email),
+	}, true, nil
+}
+
+// Preview renders the issue for a reader who follows every topic, without tracking.
+func (c *Campaigns) Preview(issue *models.NewsletterIssue, email string) (mail.Message, error) {
+	msg, ok, err := c.Render(issue, &models.NewsletterSubscriber{Email: email}, nil)
+	if err == nil && !ok {
+		err = errors.New("the issue has no content yet")
+	}
+	return msg, err
+}
+
+// SendTest queues an untracked copy of the issue to a single address.
+func (c *Campaigns) SendTest(issue *models.NewsletterIssue, email string) error {
+	msg, err := c.Preview(issue, email)
+	if err != nil {
+		return err
+	}
+	msg.Subject = "[Test] " + msg.Subject
+	return c.Outbox.QueueMessage(nil, msg)
+}
+
+func (c *Campaigns) batchSize() int {
+	if c.BatchSize <= 0 {
+		return 200
+	}
+	return c.BatchSize
+}
+
+func (c *Campaigns) batchDelay() time.Duration {
+	if c.BatchDelay <= 0 {
+		return time.Minute
+	}
+	return c.BatchDelay
+}
+
+// OpenURL is the tracking pixel for a delivery.
+func OpenURL(deliveryID uuid.UUID) string {
+	return mail.APILink("/newsletter/o/" + utils.SignValue(purposeOpen, deliveryID.String(), 0))
+}
+
+// ClickURL wraps a link so the click is recorded before redirecting. The target is
+// signed into the token, so the endpoint can't be used as an open redirect.
+func ClickURL(deliveryID uuid.UUID, target string) string {
+	return mail.APILink("/newsletter/c/" + utils.SignValue(purposeClick, deliveryID.String()+" "+target, 0))
+}
+
+// ParseOpenToken returns the delivery a tracking pixel belongs to.
+func ParseOpenToken(token string) (string, error) {
+	return utils.VerifySignedValue(purposeOpen, token)
+}
+
+// ParseClickToken returns the delivery and target of a tracked link.
+func ParseClickToken(token string) (deliveryID, target string, err error) {
+	value, err := utils.VerifySignedValue(purposeClick, token)
+	if err != nil {
+		return "", "", err
+	}
+	deliveryID, target, ok := strings.Cut(value, " ")
+	if !ok {
+		return "", "", utils.ErrInvalidSignedValue
+	}
+	if u, err := url.Parse(target); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", "", fmt.Errorf("unsupported link %q", target)
+	}
+	return deliveryID, target, nil
+}
+
+// truncate shortens s to at most n runes, ending on a word boundary.
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	cut := string([]rune(s)[:n])
+	if i := strings.LastIndex(cut, " "); i > n/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, ".,;: ") + "…"
+}
@@ -0,0 +1,69 @@
+// Package newsletter issues the signed links that let subscribers confirm, manage and
+// leave the newsletter without an account.
+package newsletter
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/saidimuKennedy/spotlight-africa/internal/mail"
+	"github.com/saidimuKennedy/spotlight-africa/internal/utils"
+)
+
+const (
+	purposeConfirm = "newsletter-confirm"
+	purposeManage  = "newsletter-manage"
+
+	// ConfirmTTL is how long a confirmation link stays valid.
+	ConfirmTTL = 72 * time.Hour
+)
+
+// ConfirmToken returns a short-lived token that confirms the address's subscription.
+func ConfirmToken(email string) string {
+	return utils.SignValue(purposeConfirm, normalize(email), ConfirmTTL)
+}
+
+// ManageToken returns a token for changing topics or unsubscribing. It never expires,
+// so unsubscribe links in old issues keep working.
+func ManageToken(email string) string {
+	return utils.SignValue(purposeManage, normalize(email), 0)
+}
+
+// ParseConfirmToken returns the address a confirmation token was issued for.
+func ParseConfirmToken(token string) (string, error) {
+	return utils.VerifySignedValue(purposeConfirm, token)
+}
+
+// ParseManageToken returns the address a manage token was issued for.
+func ParseManageToken(token string) (string, error) {
+	return utils.VerifySignedValue(purposeManage, token)
+}
+
+// ConfirmURL is the web app page that confirms a subscription.
+func ConfirmURL(email string) string {
+	return mail.Link("/newsletter?action=confirm&token=" + url.QueryEscape(ConfirmToken(email)))
+}
+
+// ManageURL is the web app page where a subscriber picks topics or unsubscribes.
+func ManageURL(email string) string {
+	return mail.Link("/newsletter?token=" + url.QueryEscape(ManageToken(email)))
+}
+
+// UnsubscribeURL is the API endpoint for one-click unsubscribe.
+func UnsubscribeURL(email string) string {
+	return mail.APILink("/newsletter/unsubscribe?token=" + url.QueryEscape(ManageToken(email)))
+}
+
+// Headers returns the RFC 2369 / RFC 8058 headers that let mail clients show an
+// unsubscribe button which works with a single POST.
+func Headers(email string) map[string]string {
+	return map[string]string{
+		"List-Unsubscribe":      "<" + UnsubscribeURL(email) + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+}
+
+func normalize(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,100 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type githubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+// newFakeGitHub serves GitHub's token endpoint and the two REST calls Exchange makes.
+// A code other than "good-code" is refused the way GitHub does, with 200 and an error.
+func newFakeGitHub(t *testing.T, emails []githubEmail) *GitHubProvider {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("code") != "good-code" || r.PostForm.Get("code_verifier") == "" {
+			json.NewEncoder(w).Encode(map[string]string{"error": "bad_verification_code"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "gh-token"})
+	})
+	authorized := func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Header.Get("Authorization") != "Bearer gh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return false
+		}
+		return true
+	}
+	mux.HandleFunc("/api/user", func(w http.ResponseWriter, r *http.Request) {
+		if authorized(w, r) {
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 42, "login": "amina", "name": "", "avatar_url": "https://example.com/a.png"})
+		}
+	})
+	mux.HandleFunc("/api/user/emails", func(w http.ResponseWriter, r *http.Request) {
+		if authorized(w, r) {
+			json.NewEncoder(w).Encode(emails)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return &GitHubProvider{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  "https://app.example.com/auth/callback/github",
+		BaseURL:      server.URL,
+		APIURL:       server.URL + "/api",
+		Client:       server.Client(),
+	}
+}
+
+func TestGitHubExchangeUsesVerifiedPrimaryEmail(t *testing.T) {
+	p := newFakeGitHub(t, []githubEmail{
+		{Email: "old@example.com", Primary: false, Verified: true},
+		{Email: "amina@example.com", Primary: true, Verified: true},
+	})
+
+	identity, err := p.Exchange(context.Background(), "good-code", "the-verifier", "")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if identity.Subject != "42" || identity.Email != "amina@example.com" || !identity.EmailVerified {
+		t.Fatalf("identity = %+v", identity)
+	}
+	if identity.Name != "amina" {
+		t.Fatalf("name = %q, want the login when the profile has no name", identity.Name)
+	}
+}
+
+func TestGitHubExchangeReportsUnverifiedPrimaryEmail(t *testing.T) {
+	// A verified secondary address must not vouch for an unverified primary one
+	p := newFakeGitHub(t, []githubEmail{
+		{Email: "amina@example.com", Primary: true, Verified: false},
+		{Email: "other@example.com", Primary: false, Verified: true},
+	})
+
+	identity, err := p.Exchange(context.Background(), "good-code", "the-verifier", "")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if identity.Email != "amina@example.com" || identity.EmailVerified {
+		t.Fatalf("identity = %+v, want the primary email marked unverified", identity)
+	}
+}
+
+func TestGitHubExchangeRejectsBadCode(t *testing.T) {
+	p := newFakeGitHub(t, nil)
+	if _, err := p.Exchange(context.Background(), "bad-code", "the-verifier", ""); !errors.Is(err, ErrExchangeFailed) {
+		t.Fatalf("err = %v, want ErrExchangeFailed", err)
+	}
+}
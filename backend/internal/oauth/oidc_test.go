@@ -0,0 +1,198 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fakeOIDC is an OpenID Connect provider serving discovery, keys and a token endpoint
+// that answers with whatever ID token the test signs.
+type fakeOIDC struct {
+	*httptest.Server
+	key     *rsa.PrivateKey
+	issuer  string // Issuer claimed by the discovery document; the server URL by default
+	claims  jwt.MapClaims
+	gotForm map[string]string
+}
+
+func newFakeOIDC(t *testing.T) *fakeOIDC {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeOIDC{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 f.issuer,
+			"authorization_endpoint": f.URL + "/authorize",
+			"token_endpoint":         f.URL + "/token",
+			"jwks_uri":               f.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kid": "test-key",
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(f.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(f.key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		f.gotForm = map[string]string{}
+		for k := range r.PostForm {
+			f.gotForm[k] = r.PostForm.Get(k)
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, f.claims)
+		token.Header["kid"] = "test-key"
+		signed, err := token.SignedString(f.key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": signed})
+	})
+
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	f.issuer = f.URL
+	f.claims = jwt.MapClaims{
+		"iss":            f.URL,
+		"sub":            "1234567890",
+		"aud":            "client-id",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          "the-nonce",
+		"email":          "amina@example.com",
+		"email_verified": true,
+		"name":           "Amina",
+	}
+	return f
+}
+
+func (f *fakeOIDC) provider() *OIDCProvider {
+	return &OIDCProvider{
+		ProviderName: "google",
+		Issuer:       f.URL,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  "https://app.example.com/auth/callback/google",
+		Scopes:       []string{"openid", "email"},
+		Client:       f.Client(),
+	}
+}
+
+func TestOIDCExchangeVerifiesIDToken(t *testing.T) {
+	f := newFakeOIDC(t)
+	p := f.provider()
+
+	authURL := p.AuthCodeURL("the-state", "the-challenge", "the-nonce")
+	if !strings.HasPrefix(authURL, f.URL+"/authorize?") {
+		t.Fatalf("AuthCodeURL = %q, want the discovered authorization endpoint", authURL)
+	}
+	for _, want := range []string{"state=the-state", "nonce=the-nonce", "code_challenge=the-challenge", "code_challenge_method=S256"} {
+		if !strings.Contains(authURL, want) {
+			t.Errorf("AuthCodeURL is missing %s: %s", want, authURL)
+		}
+	}
+
+	identity, err := p.Exchange(context.Background(), "the-code", "the-verifier", "the-nonce")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if identity.Subject != "1234567890" || identity.Email != "amina@example.com" || !identity.EmailVerified || identity.Name != "Amina" {
+		t.Fatalf("identity = %+v", identity)
+	}
+	if f.gotForm["code"] != "the-code" || f.gotForm["code_verifier"] != "the-verifier" {
+		t.Fatalf("token request form = %v", f.gotForm)
+	}
+}
+
+func TestOIDCExchangeRejectsUntrustedTokens(t *testing.T) {
+	for name, tamper := range map[string]func(jwt.MapClaims){
+		"wrong nonce":    func(c jwt.MapClaims) { c["nonce"] = "another-nonce" },
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "someone-else" },
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"no expiry":      func(c jwt.MapClaims) { delete(c, "exp") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			f := newFakeOIDC(t)
+			tamper(f.claims)
+			_, err := f.provider().Exchange(context.Background(), "the-code", "the-verifier", "the-nonce")
+			if !errors.Is(err, ErrExchangeFailed) {
+				t.Fatalf("err = %v, want ErrExchangeFailed", err)
+			}
+		})
+	}
+}
+
+func TestOIDCExchangeRejectsOtherSigningKey(t *testing.T) {
+	f := newFakeOIDC(t)
+	p := f.provider()
+	if _, err := p.Exchange(context.Background(), "the-code", "the-verifier", "the-nonce"); err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	// Same key ID, different key: the cached public key must not verify it
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.key = other
+	if _, err := p.Exchange(context.Background(), "the-code", "the-verifier", "the-nonce"); !errors.Is(err, ErrExchangeFailed) {
+		t.Fatalf("err = %v, want ErrExchangeFailed", err)
+	}
+}
+
+func TestOIDCDiscoveryRejectsOtherIssuer(t *testing.T) {
+	f := newFakeOIDC(t)
+	// The document claims another issuer, and tokens to match, e.g. a misconfigured proxy
+	f.issuer = "https://evil.example.com"
+	f.claims["iss"] = "https://evil.example.com"
+
+	p := f.provider()
+	if url := p.AuthCodeURL("s", "c", "n"); url != "" {
+		t.Fatalf("AuthCodeURL = %q, want empty after failed discovery", url)
+	}
+	if _, err := p.Exchange(context.Background(), "the-code", "the-verifier", "the-nonce"); !errors.Is(err, ErrExchangeFailed) {
+		t.Fatalf("err = %v, want ErrExchangeFailed", err)
+	}
+}
+
+func TestOIDCEmailVerifiedAsString(t *testing.T) {
+	f := newFakeOIDC(t)
+	f.claims["email_verified"] = "true"
+	identity, err := f.provider().Exchange(context.Background(), "the-code", "the-verifier", "the-nonce")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if !identity.EmailVerified {
+		t.Fatal(`email_verified "true" was not treated as verified`)
+	}
+
+	f = newFakeOIDC(t)
+	f.claims["email_verified"] = false
+	identity, err = f.provider().Exchange(context.Background(), "the-code", "the-verifier", "the-nonce")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if identity.EmailVerified {
+		t.Fatal("an unverified email was treated as verified")
+	}
+}
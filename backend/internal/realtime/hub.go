@@ -0,0 +1,116 @@
+// Package realtime pushes live updates (notifications, network chat, inquiry changes)
+// to connected clients. Each API instance keeps its own set of connections in a Hub;
+// when a Postgres listener is attached, messages are broadcast with NOTIFY so every
+// instance delivers to its own clients.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Message types sent to clients.
+const (
+	TypeNotification = "notification"
+	TypeChat         = "chat"
+	TypeInquiry      = "inquiry"
+)
+
+// Message is one update pushed to clients.
+type Message struct {
+	Type string `json:"type"`
+	// UserID targets a single user. Nil broadcasts to everyone connected.
+	UserID *uuid.UUID      `json:"user_id,omitempty"`
+	Data   json.RawMessage `json:"data,omitempty"`
+}
+
+// clientBuffer is how many messages a slow client can fall behind before messages are dropped.
+const clientBuffer = 32
+
+// Hub tracks the connected clients of this instance.
+type Hub struct {
+	mu      sync.RWMutex
+	clients map[chan Message]uuid.UUID
+
+	// Broadcaster, when set, carries messages to every instance (see PGBroadcaster).
+	// Without it, messages are only delivered to clients of this instance.
+	Broadcaster Broadcaster
+}
+
+// Broadcaster fans a message out to every API instance, each of which calls Hub.Deliver.
+type Broadcaster interface {
+	Broadcast(ctx context.Context, msg Message) error
+}
+
+// NewHub returns an empty hub.
+func NewHub() *Hub {
+	return &Hub{clients: map[chan Message]uuid.UUID{}}
+}
+
+// Subscribe registers a connection for userID. Call the returned function when it closes.
+func (h *Hub) Subscribe(userID uuid.UUID) (<-chan Message, func()) {
+	ch := make(chan Message, clientBuffer)
+
+	h.mu.Lock()
+	h.clients[ch] = userID
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.clients, ch)
+		h.mu.Unlock()
+	}
+}
+
+// Publish sends a message to its recipients on every instance. data is JSON-encoded.
+// A nil hub is a no-op.
+func (h *Hub) Publish(ctx context.Context, msgType string, userID *uuid.UUID, data interface{}) {
+	if h == nil {
+		return
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		log.Println("⚠️ Realtime: could not encode message:", err)
+		return
+	}
+	msg := Message{Type: msgType, UserID: userID, Data: body}
+
+	if h.Broadcaster == nil {
+		h.Deliver(msg)
+		return
+	}
+	if err := h.Broadcaster.Broadcast(ctx, msg); err != nil {
+		// Better to reach the clients on this instance than nobody.
+		log.Println("⚠️ Realtime: broadcast failed, delivering locally:", err)
+		h.Deliver(msg)
+	}
+}
+
+// Deliver hands a message to matching clients on this instance. Clients that are too far
+// behind miss the message rather than blocking everyone else; they can refetch.
+func (h *Hub) Deliver(msg Message) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch, userID := range h.clients {
+		if msg.UserID != nil && *msg.UserID != userID {
+			continue
+		}
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+// Connections reports how many clients are connected to this instance.
+func (h *Hub) Connections() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
@@ -0,0 +1,83 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// Channel is the Postgres NOTIFY channel used for realtime fan-out.
+const Channel = "spotlight_realtime"
+
+// maxPayload stays under Postgres' 8000 byte NOTIFY limit.
+const maxPayload = 7900
+
+// PGBroadcaster fans messages out across API instances with LISTEN/NOTIFY.
+type PGBroadcaster struct {
+	DB  *gorm.DB // Used to NOTIFY
+	DSN string   // A dedicated connection is held open to LISTEN
+}
+
+// Broadcast sends msg to every instance listening on Channel.
+// Oversized messages are sent without their data; clients refetch when they see them.
+func (b *PGBroadcaster) Broadcast(ctx context.Context, msg Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxPayload {
+		msg.Data = nil
+		if payload, err = json.Marshal(msg); err != nil {
+			return err
+		}
+	}
+	return b.DB.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", Channel, string(payload)).Error
+}
+
+// Listen delivers notifications from Channel to hub until ctx is cancelled,
+// reconnecting with backoff if the connection drops.
+func (b *PGBroadcaster) Listen(ctx context.Context, hub *Hub) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		err := b.listen(ctx, hub, func() { backoff = time.Second })
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("⚠️ Realtime: listener disconnected (%v), retrying in %s", err, backoff)
+		time.Sleep(backoff)
+		if backoff < time.Minute {
+			backoff *= 2
+		}
+	}
+}
+
+func (b *PGBroadcaster) listen(ctx context.Context, hub *Hub, connected func()) error {
+	conn, err := pgx.Connect(ctx, b.DSN)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+	connected()
+	log.Println("📡 Realtime: listening for cross-instance updates")
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var msg Message
+		if err := json.Unmarshal([]byte(n.Payload), &msg); err != nil {
+			log.Println("⚠️ Realtime: bad payload:", err)
+			continue
+		}
+		hub.Deliver(msg)
+	}
+}
@@ -0,0 +1,288 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrInvalidInvitation is returned for invitations that are unknown, expired, revoked or already accepted.
+	ErrInvalidInvitation = errors.New("invalid or expired invitation")
+	// ErrInvitationEmail is returned when an invitation is accepted by an account with a different email.
+	ErrInvitationEmail = errors.New("invitation was sent to another email")
+	// ErrAlreadyMember is returned when inviting or transferring to someone already in the right place.
+	ErrAlreadyMember = errors.New("user is already a member")
+	// ErrNotMember is returned for users who aren't on the business's team.
+	ErrNotMember = errors.New("user is not a member")
+	// ErrOwnerMembership is returned when changing or removing the owner's membership directly.
+	ErrOwnerMembership = errors.New("the owner's membership can only change through a transfer")
+)
+
+// BusinessMemberRepository stores business teams and invitations to join them.
+type BusinessMemberRepository struct {
+	DB *gorm.DB
+}
+
+// Role returns userID's role in a business, or "" if they aren't on its team.
+func (r *BusinessMemberRepository) Role(businessID, userID uuid.UUID) string {
+	var member models.BusinessMember
+	if err := r.DB.Select("role").Where("business_id = ? AND user_id = ?", businessID, userID).
+		First(&member).Error; err != nil {
+		return ""
+	}
+	return member.Role
+}
+
+// List returns a business's team, owner first.
+func (r *BusinessMemberRepository) List(businessID uuid.UUID) ([]models.BusinessMember, error) {
+	var members []models.BusinessMember
+	err := r.DB.Preload("User").Where("business_id = ?", businessID).
+		Order("CASE role WHEN 'owner' THEN 0 WHEN 'manager' THEN 1 WHEN 'editor' THEN 2 ELSE 3 END, created_at").
+		Find(&members).Error
+	return members, err
+}
+
+// MemberBusiness is a business the user is on the team of, for the dashboard's business switcher.
+type MemberBusiness struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	AvatarURL   string    `json:"avatar_url"`
+	Role        string    `json:"role"`
+	HealthScore int       `json:"health_score"`
+	Views       int       `json:"views"`
+	Inquiries   int64     `json:"inquiries"`
+}
+
+// Businesses lists every business userID is on the team of, the ones they own first.
+func (r *BusinessMemberRepository) Businesses(userID uuid.UUID) ([]MemberBusiness, error) {
+	businesses := []MemberBusiness{}
+	err := r.DB.Raw(`SELECT b.id, b.name, b.avatar_url, m.role, b.health_score, b.views,
+			(SELECT COUNT(*) FROM inquiries i WHERE i.business_id = b.id AND i.is_quarantined = false) AS inquiries
+		FROM business_members m JOIN businesses b ON b.id = m.business_id
+		WHERE m.user_id = ?
+		ORDER BY CASE m.role WHEN 'owner' THEN 0 ELSE 1 END, b.name`, userID).
+		Scan(&businesses).Error
+	return businesses, err
+}
+
+// Portfolio adds up the numbers of every business a user is on the team of.
+type Portfolio struct {
+	Businesses       int64   `json:"businesses"`
+	TotalViews       int64   `json:"total_views"`
+	TotalLikes       int64   `json:"total_likes"`
+	TotalInquiries   int64   `json:"total_inquiries"`
+	TotalConversions int64   `json:"total_conversions"`
+	UpcomingMeetings int64   `json:"upcoming_meetings"`
+	AverageHealth    float64 `json:"average_health"`
+}
+
+// Portfolio returns userID's totals across all their businesses.
+func (r *BusinessMemberRepository) Portfolio(userID uuid.UUID) (*Portfolio, error) {
+	var portfolio Portfolio
+	err := r.DB.Raw(`WITH biz AS (
+			SELECT b.id, b.views, b.health_score FROM business_members m
+			JOIN businesses b ON b.id = m.business_id WHERE m.user_id = ?
+		)
+		SELECT
+			(SELECT COUNT(*) FROM biz) AS businesses,
+			(SELECT COALESCE(SUM(views), 0) FROM biz) AS total_views,
+			(SELECT COALESCE(AVG(health_score), 0) FROM biz) AS average_health,
+			(SELECT COUNT(*) FROM likes WHERE business_id IN (SELECT id FROM biz)) AS total_likes,
+			(SELECT COUNT(*) FROM inquiries WHERE business_id IN (SELECT id FROM biz) AND is_quarantined = false) AS total_inquiries,
+			(SELECT COUNT(*) FROM activities WHERE entity_type = 'business' AND type = ? AND entity_id IN (SELECT id FROM biz)) AS total_conversions,
+			(SELECT COUNT(*) FROM meetings WHERE business_id IN (SELECT id FROM biz) AND status = ? AND start_time > NOW()) AS upcoming_meetings`,
+		userID, models.ActivityTypeConversion, models.MeetingStatusScheduled).
+		Scan(&portfolio).Error
+	if err != nil {
+		return nil, err
+	}
+	return &portfolio, nil
+}
+
+// Membership returns userID's membership of a business, or gorm.ErrRecordNotFound.
+func (r *BusinessMemberRepository) Membership(businessID, userID uuid.UUID) (*models.BusinessMember, error) {
+	var member models.BusinessMember
+	if err := r.DB.Where("business_id = ? AND user_id = ?", businessID, userID).First(&member).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// BusinessFor returns the business whose dashboard userID sees by default: one they own,
+// or else the one they joined first. It returns gorm.ErrRecordNotFound if they have none.
+func (r *BusinessMemberRepository) BusinessFor(userID uuid.UUID) (*models.BusinessMember, error) {
+	var member models.BusinessMember
+	err := r.DB.Where("user_id = ?", userID).
+		Order("CASE role WHEN 'owner' THEN 0 ELSE 1 END, created_at").
+		First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// AddOwner records the creator of a new business as its owner.
+func (r *BusinessMemberRepository) AddOwner(businessID, userID uuid.UUID) error {
+	return r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.BusinessMember{
+		BusinessID: businessID,
+		UserID:     userID,
+		Role:       models.MemberRoleOwner,
+	}).Error
+}
+
+// UpdateRole changes a member's role. The owner's role only changes through TransferOwnership.
+func (r *BusinessMemberRepository) UpdateRole(businessID, userID uuid.UUID, role string) (*models.BusinessMember, error) {
+	var member models.BusinessMember
+	if err := r.DB.Where("business_id = ? AND user_id = ?", businessID, userID).First(&member).Error; err != nil {
+		return nil, ErrNotMember
+	}
+	if member.Role == models.MemberRoleOwner {
+		return nil, ErrOwnerMembership
+	}
+	if err := r.DB.Model(&member).Update("role", role).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// Remove takes a member off the team. The owner can't be removed.
+func (r *BusinessMemberRepository) Remove(businessID, userID uuid.UUID) error {
+	res := r.DB.Where("business_id = ? AND user_id = ? AND role <> ?", businessID, userID, models.MemberRoleOwner).
+		Delete(&models.BusinessMember{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		if r.Role(businessID, userID) == models.MemberRoleOwner {
+			return ErrOwnerMembership
+		}
+		return ErrNotMember
+	}
+	return nil
+}
+
+// TransferOwnership makes an existing member the owner. The previous owner stays on as a manager.
+func (r *BusinessMemberRepository) TransferOwnership(businessID, fromUserID, toUserID uuid.UUID) error {
+	if fromUserID == toUserID {
+		return ErrAlreadyMember
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var target models.BusinessMember
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("business_id = ? AND user_id = ?", businessID, toUserID).First(&target).Error; err != nil {
+			return ErrNotMember
+		}
+		if err := tx.Model(&models.BusinessMember{}).
+			Where("business_id = ? AND user_id = ?", businessID, fromUserID).
+			Update("role", models.MemberRoleManager).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&target).Update("role", models.MemberRoleOwner).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Business{}).Where("id = ?", businessID).Update("owner_id", toUserID).Error
+	})
+}
+
+// Invite creates an invitation and returns the raw token to email. Inviting the same
+// address again replaces its pending invitation, so only the latest link works.
+func (r *BusinessMemberRepository) Invite(businessID, invitedBy uuid.UUID, email, role string, ttl time.Duration) (*models.BusinessInvitation, string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	var existing int64
+	r.DB.Model(&models.BusinessMember{}).
+		Joins("JOIN users ON users.id = business_members.user_id").
+		Where("business_members.business_id = ? AND LOWER(users.email) = ?", businessID, email).
+		Count(&existing)
+	if existing > 0 {
+		return nil, "", ErrAlreadyMember
+	}
+
+	raw, err := newRawToken()
+	if err != nil {
+		return nil, "", err
+	}
+	invitation := &models.BusinessInvitation{
+		BusinessID: businessID,
+		Email:      email,
+		Role:       role,
+		TokenHash:  hashToken(raw),
+		InvitedBy:  invitedBy,
+		ExpiresAt:  time.Now().Add(ttl),
+	}
+	err = r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("business_id = ? AND email = ? AND accepted_at IS NULL", businessID, email).
+			Delete(&models.BusinessInvitation{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(invitation).Error
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return invitation, raw, nil
+}
+
+// PendingInvitations lists a business's invitations that can still be accepted.
+func (r *BusinessMemberRepository) PendingInvitations(businessID uuid.UUID) ([]models.BusinessInvitation, error) {
+	var invitations []models.BusinessInvitation
+	err := r.DB.Where("business_id = ? AND accepted_at IS NULL AND expires_at > NOW()", businessID).
+		Order("created_at desc").Find(&invitations).Error
+	return invitations, err
+}
+
+// RevokeInvitation deletes a pending invitation. It reports whether one was deleted.
+func (r *BusinessMemberRepository) RevokeInvitation(businessID uuid.UUID, id string) (bool, error) {
+	res := r.DB.Where("id = ? AND business_id = ? AND accepted_at IS NULL", id, businessID).
+		Delete(&models.BusinessInvitation{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// GetInvitation looks up a pending invitation by its raw token.
+func (r *BusinessMemberRepository) GetInvitation(raw string) (*models.BusinessInvitation, error) {
+	var invitation models.BusinessInvitation
+	err := r.DB.Where("token_hash = ? AND accepted_at IS NULL AND expires_at > NOW()", hashToken(raw)).
+		First(&invitation).Error
+	if err != nil {
+		return nil, ErrInvalidInvitation
+	}
+	return &invitation, nil
+}
+
+// AcceptInvitation adds user to the team the invitation is for. The invitation can only
+// be accepted once, by an account with the email it was sent to.
+func (r *BusinessMemberRepository) AcceptInvitation(raw string, user *models.User) (*models.BusinessMember, error) {
+	var member *models.BusinessMember
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var invitation models.BusinessInvitation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND accepted_at IS NULL AND expires_at > NOW()", hashToken(raw)).
+			First(&invitation).Error; err != nil {
+			return ErrInvalidInvitation
+		}
+		if !strings.EqualFold(invitation.Email, user.Email) {
+			return ErrInvitationEmail
+		}
+
+		var existing models.BusinessMember
+		if err := tx.Where("business_id = ? AND user_id = ?", invitation.BusinessID, user.ID).First(&existing).Error; err == nil {
+			return ErrAlreadyMember
+		}
+
+		member = &models.BusinessMember{BusinessID: invitation.BusinessID, UserID: user.ID, Role: invitation.Role}
+		if err := tx.Create(member).Error; err != nil {
+			return err
+		}
+		return tx.Model(&invitation).Update("accepted_at", time.Now()).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
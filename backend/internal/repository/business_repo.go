@@ -5,6 +5,9 @@
 package repository
 
 import (
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/saidimuKennedy/spotlight-africa/internal/models"
 	"gorm.io/gorm"
 )
@@ -63,21 +66,46 @@ func (r *BusinessRepository) GetByID(id string) (*models.Business, error) {
 
 	// Populate virtual fields
 	r.DB.Model(&models.Like{}).Where("business_id = ?", id).Count(&business.LikeCount)
-	r.DB.Model(&models.Comment{}).Where("business_id = ?", id).Count(&business.CommentCount)
+	r.DB.Model(&models.Comment{}).Where("business_id = ? AND is_quarantined = ? AND is_hidden = ? AND deleted_at IS NULL", id, false, false).Count(&business.CommentCount)
+	business.ReactionCounts = r.getReactionCounts(id)
 	
 	return &business, nil
 }
 
+// getReactionCounts groups a business's reactions by type.
+func (r *BusinessRepository) getReactionCounts(bizID string) map[string]int64 {
+	var rows []struct {
+		Type  string
+		Count int64
+	}
+	r.DB.Model(&models.Like{}).Select("type, count(*) as count").Where("business_id = ?", bizID).Group("type").Scan(&rows)
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Type] = row.Count
+	}
+	return counts
+}
+
 // Update saves changes made to an existing business record.
 // GORM 'Save' updates all fields, so ensure the business struct has the correct ID.
 func (r *BusinessRepository) Update(business *models.Business) error {
 	return r.DB.Save(business).Error
 }
 
-// Delete permanently removes a business record from the database using its ID.
+// Delete permanently removes a business record from the database using its ID,
+// along with its team and pending invitations.
 func (r *BusinessRepository) Delete(id string) error {
-	// We pass &models.Business{} to Delete to tell GORM which table to interact with.
-	return r.DB.Where("id = ?", id).Delete(&models.Business{}).Error
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("business_id = ?", id).Delete(&models.BusinessMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("business_id = ?", id).Delete(&models.BusinessInvitation{}).Error; err != nil {
+			return err
+		}
+		// We pass &models.Business{} to Delete to tell GORM which table to interact with.
+		return tx.Where("id = ?", id).Delete(&models.Business{}).Error
+	})
 }
 
 // GetStats performs an aggregation query to count businesses per category.
@@ -107,42 +135,6 @@ func (r *BusinessRepository) GetStats() (map[string]int64, error) {
 	return result, nil
 }
 
-// GetByOwnerID retrieves the business record owned by a specific user.
-func (r *BusinessRepository) GetByOwnerID(ownerID string) (*models.Business, error) {
-	var business models.Business
-	err := r.DB.Where("owner_id = ?", ownerID).First(&business).Error
-	if err != nil {
-		return nil, err
-	}
-
-	// Populate virtual fields
-	r.DB.Model(&models.Like{}).Where("business_id = ?", business.ID).Count(&business.LikeCount)
-	r.DB.Model(&models.Comment{}).Where("business_id = ?", business.ID).Count(&business.CommentCount)
-
-	return &business, nil
-}
-
-// UpdateHealthScore calculates a 'popularity' score based on interactions and updates the main record.
-// This is often run asynchronously or via a scheduled job to keep the main table efficient (avoiding complex joins on every read).
-func (r *BusinessRepository) UpdateHealthScore(bizID string) error {
-	var likesCount int64
-	var commentsCount int64
-
-	// 1. Calculate the components
-	// Count how many 'Like' records exist for this business
-	r.DB.Model(&models.Like{}).Where("business_id = ?", bizID).Count(&likesCount)
-	// Count how many 'Comment' records exist
-	r.DB.Model(&models.Comment{}).Where("business_id = ?", bizID).Count(&commentsCount)
-
-	// 2. Apply the Business Logic Formula
-	// We give more weight (5x) to comments because they represent higher engagement than a simple like (2x).
-	newScore := (int(likesCount) * 2) + (int(commentsCount) * 5)
-
-	// 3. Atomically update just the health_score column
-	// We use .Update() instead of .Save() to modify only this specific field.
-	return r.DB.Model(&models.Business{}).Where("id = ?", bizID).Update("health_score", newScore).Error
-}
-
 // GetEvents retrieves the latest events.
 func (r *BusinessRepository) GetEvents(limit int) ([]models.Event, error) {
 	var events []models.Event
@@ -154,4 +146,38 @@ func (r *BusinessRepository) GetEvents(limit int) ([]models.Event, error) {
 
 func (r *BusinessRepository) IncrementViews(id string) error {
 	return r.DB.Model(&models.Business{}).Where("id = ?", id).Update("views", gorm.Expr("views + 1")).Error
+}
+
+// RecordView counts a view of the business by viewer, at most once per period. It
+// reports whether this view was counted; the error is gorm.ErrRecordNotFound when no
+// business has the ID.
+func (r *BusinessRepository) RecordView(bizID uuid.UUID, viewer string, period time.Duration) (counted bool, err error) {
+	err = r.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Exec(`INSERT INTO business_views (business_id, viewer_key, counted_at) VALUES (?, ?, NOW())
+			ON CONFLICT (business_id, viewer_key) DO UPDATE SET counted_at = NOW()
+			WHERE business_views.counted_at < NOW() - make_interval(secs => ?)`, bizID, viewer, period.Seconds())
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		res = tx.Model(&models.Business{}).Where("id = ?", bizID).Update("views", gorm.Expr("views + 1"))
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		counted = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return counted, nil
+}
+
+// PruneViews forgets views counted before olderThan ago, once they can no longer stop a
+// repeat from counting.
+func (r *BusinessRepository) PruneViews(olderThan time.Duration) (int64, error) {
+	res := r.DB.Where("counted_at < ?", time.Now().Add(-olderThan)).Delete(&models.BusinessView{})
+	return res.RowsAffected, res.Error
 }
\ No newline at end of file
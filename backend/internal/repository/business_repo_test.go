@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/database/dbtest"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+)
+
+func TestRecordViewCountsEachViewerOncePerPeriod(t *testing.T) {
+	r := &BusinessRepository{DB: dbtest.Open(t, &models.Business{}, &models.BusinessView{})}
+	biz := &models.Business{Name: "Duka"}
+	if err := r.Create(biz); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	for i, tc := range []struct {
+		viewer string
+		want   bool
+	}{
+		{"ip:10.0.0.1", true},
+		{"ip:10.0.0.1", false},
+		{"user:" + uuid.NewString(), true},
+		{"ip:10.0.0.1", false},
+	} {
+		counted, err := r.RecordView(biz.ID, tc.viewer, 24*time.Hour)
+		if err != nil {
+			t.Fatalf("view %d: %v", i, err)
+		}
+		if counted != tc.want {
+			t.Fatalf("view %d by %s: counted = %v, want %v", i, tc.viewer, counted, tc.want)
+		}
+	}
+	var stored models.Business
+	r.DB.First(&stored, "id = ?", biz.ID)
+	if stored.Views != 2 {
+		t.Fatalf("views = %d, want 2", stored.Views)
+	}
+
+	// Once the period is over the same viewer counts again
+	r.DB.Model(&models.BusinessView{}).Where("viewer_key = ?", "ip:10.0.0.1").
+		Update("counted_at", time.Now().Add(-25*time.Hour))
+	if counted, _ := r.RecordView(biz.ID, "ip:10.0.0.1", 24*time.Hour); !counted {
+		t.Fatal("a view after the period was not counted")
+	}
+}
+
+func TestRecordViewOfUnknownBusiness(t *testing.T) {
+	r := &BusinessRepository{DB: dbtest.Open(t, &models.Business{}, &models.BusinessView{})}
+	counted, err := r.RecordView(uuid.New(), "ip:10.0.0.1", 24*time.Hour)
+	if counted || !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("counted = %v, err = %v; want ErrRecordNotFound", counted, err)
+	}
+	var n int64
+	r.DB.Model(&models.BusinessView{}).Count(&n)
+	if n != 0 {
+		t.Fatalf("%d view records kept for a business that doesn't exist", n)
+	}
+}
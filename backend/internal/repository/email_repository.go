@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EmailRepository stores the email outbox and the suppression list.
+type EmailRepository struct {
+	DB *gorm.DB
+}
+
+// GetOutbox fetches a queued email.
+func (r *EmailRepository) GetOutbox(id string) (*models.OutboxEmail, error) {
+	var email models.OutboxEmail
+	if err := r.DB.Where("id = ?", id).First(&email).Error; err != nil {
+		return nil, err
+	}
+	return &email, nil
+}
+
+// RecordAttempt stores the outcome of a delivery attempt.
+func (r *EmailRepository) RecordAttempt(email *models.OutboxEmail, status, errMsg string) error {
+	updates := map[string]interface{}{
+		"status":     status,
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": errMsg,
+	}
+	if status == models.EmailStatusSent {
+		updates["sent_at"] = time.Now()
+	}
+	return r.DB.Model(email).Updates(updates).Error
+}
+
+// ListOutbox returns queued and sent emails newest first, optionally filtered by status.
+func (r *EmailRepository) ListOutbox(status string, limit, offset int) ([]models.OutboxEmail, int64, error) {
+	query := r.DB.Model(&models.OutboxEmail{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var list []models.OutboxEmail
+	err := query.Order("created_at desc").Limit(limit).Offset(offset).Find(&list).Error
+	return list, total, err
+}
+
+// IsSuppressed reports whether mail to the address must not be sent.
+func (r *EmailRepository) IsSuppressed(email string) bool {
+	var count int64
+	r.DB.Model(&models.EmailSuppression{}).Where("email = ?", strings.ToLower(strings.TrimSpace(email))).Count(&count)
+	return count > 0
+}
+
+// Suppress adds an address to the suppression list, or updates the reason if it is already there.
+func (r *EmailRepository) Suppress(email, reason, detail string) error {
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "email"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason", "detail"}),
+	}).Create(&models.EmailSuppression{
+		Email:  strings.ToLower(strings.TrimSpace(email)),
+		Reason: reason,
+		Detail: detail,
+	}).Error
+}
+
+// Unsuppress removes an address from the suppression list. Returns false if it wasn't on it.
+func (r *EmailRepository) Unsuppress(email string) (bool, error) {
+	res := r.DB.Where("email = ?", strings.ToLower(strings.TrimSpace(email))).Delete(&models.EmailSuppression{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// ListSuppressions returns suppressed addresses newest first.
+func (r *EmailRepository) ListSuppressions(limit, offset int) ([]models.EmailSuppression, int64, error) {
+	var total int64
+	if err := r.DB.Model(&models.EmailSuppression{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []models.EmailSuppression
+	err := r.DB.Order("created_at desc").Limit(limit).Offset(offset).Find(&list).Error
+	return list, total, err
+}
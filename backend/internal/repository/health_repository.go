@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/scoring"
+	"gorm.io/gorm"
+)
+
+// HealthRepository gathers engagement signals for the scoring engine and persists the result.
+// It is the one place the health score is calculated; everything else calls Recalculate.
+type HealthRepository struct {
+	DB     *gorm.DB
+	Engine *scoring.Engine
+}
+
+// signal counts rows matching the query and sums their time-decayed weight in SQL,
+// so we never have to pull individual interactions into Go.
+func (r *HealthRepository) signal(query *gorm.DB) (scoring.Signal, error) {
+	var row struct {
+		Count   int64
+		Decayed float64
+	}
+
+	tau := r.Engine.DecayTau()
+	sel := "count(*) AS count, count(*) AS decayed"
+	if tau > 0 {
+		sel = "count(*) AS count, COALESCE(SUM(EXP(-EXTRACT(EPOCH FROM (NOW() - created_at))::float8 / ?)), 0) AS decayed"
+		query = query.Select(sel, tau)
+	} else {
+		query = query.Select(sel)
+	}
+
+	err := query.Scan(&row).Error
+	return scoring.Signal{Count: row.Count, Decayed: row.Decayed}, err
+}
+
+// Signals collects every scoring input for a business.
+func (r *HealthRepository) Signals(bizID string) (scoring.Signals, error) {
+	var s scoring.Signals
+	var err error
+
+	if s.Likes, err = r.signal(r.DB.Model(&models.Like{}).Where("business_id = ?", bizID)); err != nil {
+		return s, err
+	}
+	// Quarantined spam, hidden and deleted comments don't count.
+	if s.Comments, err = r.signal(r.DB.Model(&models.Comment{}).
+		Where("business_id = ? AND is_quarantined = ? AND is_hidden = ? AND deleted_at IS NULL", bizID, false, false)); err != nil {
+		return s, err
+	}
+	if s.Views, err = r.signal(r.DB.Model(&models.Activity{}).
+		Where("entity_id = ? AND entity_type = ? AND type = ?", bizID, "business", models.ActivityTypeView)); err != nil {
+		return s, err
+	}
+	if s.Inquiries, err = r.signal(r.DB.Model(&models.Inquiry{}).
+		Where("business_id = ? AND is_quarantined = ?", bizID, false)); err != nil {
+		return s, err
+	}
+	if s.Meetings, err = r.signal(r.DB.Model(&models.Meeting{}).
+		Where("business_id = ? AND status <> ?", bizID, models.MeetingStatusCancelled)); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// Breakdown scores a business without saving anything.
+func (r *HealthRepository) Breakdown(bizID string) (scoring.Breakdown, error) {
+	signals, err := r.Signals(bizID)
+	if err != nil {
+		return scoring.Breakdown{}, err
+	}
+	return r.Engine.Score(signals), nil
+}
+
+// Recalculate scores a business, writes the result to businesses.health_score and
+// records a snapshot for the history charts.
+func (r *HealthRepository) Recalculate(bizID string) (scoring.Breakdown, error) {
+	breakdown, err := r.Breakdown(bizID)
+	if err != nil {
+		return breakdown, err
+	}
+
+	// Update just the health_score column (not a full Save) to avoid clobbering concurrent edits.
+	if err := r.DB.Model(&models.Business{}).Where("id = ?", bizID).Update("health_score", breakdown.Score).Error; err != nil {
+		return breakdown, err
+	}
+	return breakdown, r.snapshot(bizID, breakdown)
+}
+
+// snapshotHeartbeat is how often we store a snapshot even when the score hasn't moved,
+// so daily rollups have a data point for every day.
+const snapshotHeartbeat = 24 * time.Hour
+
+// snapshot stores the score if it changed since the last snapshot, or if the last one is stale.
+// This keeps the hourly batch job from writing thousands of identical rows.
+func (r *HealthRepository) snapshot(bizID string, breakdown scoring.Breakdown) error {
+	var last models.HealthSnapshot
+	err := r.DB.Where("business_id = ?", bizID).Order("created_at desc").First(&last).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err == nil && last.Score == breakdown.Score && time.Since(last.CreatedAt) < snapshotHeartbeat {
+		return nil
+	}
+
+	id, err := uuid.Parse(bizID)
+	if err != nil {
+		return err
+	}
+	components, _ := json.Marshal(breakdown.Components)
+	return r.DB.Create(&models.HealthSnapshot{
+		BusinessID: id,
+		Score:      breakdown.Score,
+		Raw:        breakdown.Raw,
+		Components: string(components),
+	}).Error
+}
+
+// HistoryPoint is one bucket of a score history rollup.
+type HistoryPoint struct {
+	Bucket  time.Time `json:"bucket"` // Start of the day/week
+	Open    int       `json:"open"`   // First score in the bucket
+	Close   int       `json:"close"`  // Last score in the bucket
+	Min     int       `json:"min"`
+	Max     int       `json:"max"`
+	Average float64   `json:"average"`
+}
+
+// History rolls snapshots up into daily or weekly buckets since `since`, oldest first.
+// interval must be "day" or "week".
+func (r *HealthRepository) History(bizID string, interval string, since time.Time) ([]HistoryPoint, error) {
+	var points []HistoryPoint
+	err := r.DB.Model(&models.HealthSnapshot{}).
+		Select(`date_trunc(?, created_at) AS bucket,
+			(array_agg(score ORDER BY created_at ASC))[1] AS open,
+			(array_agg(score ORDER BY created_at DESC))[1] AS close,
+			min(score) AS min, max(score) AS max, avg(score) AS average`, interval).
+		Where("business_id = ? AND created_at >= ?", bizID, since).
+		Group("bucket").Order("bucket asc").
+		Scan(&points).Error
+	return points, err
+}
+
+// ScoreAt returns the most recent snapshot score at or before t.
+// ok is false when there is no snapshot that old.
+func (r *HealthRepository) ScoreAt(bizID string, t time.Time) (score int, ok bool, err error) {
+	var snap models.HealthSnapshot
+	err = r.DB.Where("business_id = ? AND created_at <= ?", bizID, t).Order("created_at desc").First(&snap).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, false, nil
+	}
+	return snap.Score, err == nil, err
+}
+
+// RecalculateAll rescores every business in batches. Because scores decay over time,
+// this needs to run periodically even when nothing new happens on a profile.
+// It returns how many businesses were updated.
+func (r *HealthRepository) RecalculateAll(batchSize int) (int, error) {
+	updated := 0
+	var lastID uuid.UUID
+
+	for {
+		var ids []uuid.UUID
+		query := r.DB.Model(&models.Business{}).Order("id").Limit(batchSize)
+		if lastID != uuid.Nil {
+			query = query.Where("id > ?", lastID)
+		}
+		if err := query.Pluck("id", &ids).Error; err != nil {
+			return updated, err
+		}
+
+		for _, id := range ids {
+			if _, err := r.Recalculate(id.String()); err != nil {
+				return updated, err
+			}
+			updated++
+		}
+
+		if len(ids) < batchSize {
+			return updated, nil
+		}
+		lastID = ids[len(ids)-1]
+	}
+}
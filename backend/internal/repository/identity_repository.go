@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/oauth"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidOAuthState is returned for sign-in attempts that are unknown, expired or already finished.
+	ErrInvalidOAuthState = errors.New("invalid or expired sign-in attempt")
+	// ErrIdentityTaken is returned when linking a provider account that belongs to another user.
+	ErrIdentityTaken = errors.New("provider account is linked to another user")
+)
+
+// IdentityRepository stores users' external identities and sign-ins in progress.
+type IdentityRepository struct {
+	DB *gorm.DB
+}
+
+// StartSignIn records a sign-in with provider. linkTo is set when a signed-in user is
+// adding the provider to their account.
+func (r *IdentityRepository) StartSignIn(provider, state, verifier, nonce string, linkTo *uuid.UUID, ttl time.Duration) error {
+	// Abandoned sign-ins are cleaned up as new ones start
+	r.DB.Where("expires_at < ?", time.Now()).Delete(&models.OAuthState{})
+
+	return r.DB.Create(&models.OAuthState{
+		StateHash:    hashToken(state),
+		Provider:     provider,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		UserID:       linkTo,
+		ExpiresAt:    time.Now().Add(ttl),
+	}).Error
+}
+
+// FinishSignIn redeems a state for the given provider. It can only succeed once.
+func (r *IdentityRepository) FinishSignIn(provider, state string) (*models.OAuthState, error) {
+	var st models.OAuthState
+	res := r.DB.Raw(`DELETE FROM oauth_states WHERE state_hash = ? AND provider = ? AND expires_at > NOW() RETURNING *`,
+		hashToken(state), provider).Scan(&st)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrInvalidOAuthState
+	}
+	return &st, nil
+}
+
+// UserFor returns the user a provider account is linked to, or gorm.ErrRecordNotFound.
+// It also records that the identity was just used.
+func (r *IdentityRepository) UserFor(provider string, identity *oauth.Identity) (*models.User, error) {
+	var link models.UserIdentity
+	if err := r.DB.Where("provider = ? AND subject = ?", provider, identity.Subject).First(&link).Error; err != nil {
+		return nil, err
+	}
+	r.DB.Model(&link).Updates(map[string]interface{}{"email": identity.Email, "last_used_at": time.Now()})
+
+	var user models.User
+	if err := r.DB.Where("id = ?", link.UserID).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Link adds a provider account to a user. Linking one already on the user is a no-op.
+func (r *IdentityRepository) Link(userID uuid.UUID, provider string, identity *oauth.Identity) error {
+	var existing models.UserIdentity
+	err := r.DB.Where("provider = ? AND subject = ?", provider, identity.Subject).First(&existing).Error
+	if err == nil {
+		if existing.UserID != userID {
+			return ErrIdentityTaken
+		}
+		return nil
+	}
+	return r.DB.Create(&models.UserIdentity{
+		UserID:     userID,
+		Provider:   provider,
+		Subject:    identity.Subject,
+		Email:      identity.Email,
+		LastUsedAt: time.Now(),
+	}).Error
+}
+
+// List returns the provider accounts linked to a user.
+func (r *IdentityRepository) List(userID uuid.UUID) ([]models.UserIdentity, error) {
+	identities := []models.UserIdentity{}
+	err := r.DB.Where("user_id = ?", userID).Order("created_at").Find(&identities).Error
+	return identities, err
+}
+
+// Unlink removes a provider from a user. It reports whether one was removed.
+func (r *IdentityRepository) Unlink(userID uuid.UUID, provider string) (bool, error) {
+	res := r.DB.Where("user_id = ? AND provider = ?", userID, provider).Delete(&models.UserIdentity{})
+	return res.RowsAffected > 0, res.Error
+}
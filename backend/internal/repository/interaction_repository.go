@@ -1,34 +1,237 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/saidimuKennedy/spotlight-africa/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type InteractionRepository struct {
 	DB *gorm.DB
 }
 
-func (r *InteractionRepository) AddLike(like *models.Like) error {
-	return r.DB.Create(like).Error
+// SetReaction records userID's reaction on a business, replacing any earlier reaction type.
+// It is idempotent: changed is false when the user already had exactly this reaction.
+func (r *InteractionRepository) SetReaction(userID, bizID uuid.UUID, reaction models.ReactionType) (like *models.Like, changed bool, err error) {
+	like = &models.Like{UserID: userID, BusinessID: bizID, Type: reaction}
+
+	err = r.DB.Transaction(func(tx *gorm.DB) error {
+		var existing models.Like
+		err := tx.Where("user_id = ? AND business_id = ?", userID, bizID).First(&existing).Error
+		if err == nil {
+			like = &existing
+			if existing.Type == reaction {
+				return nil
+			}
+			changed = true
+			existing.Type = reaction
+			return tx.Model(&existing).Update("type", reaction).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// ON CONFLICT covers the race where two requests insert at the same time;
+		// the unique index guarantees we still end up with a single row. The row only
+		// counts as changed when the insert or the type update actually happened.
+		res := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "business_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"type"}),
+			Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: like.TableName() + ".type <> excluded.type"}}},
+		}).Create(like)
+		changed = res.RowsAffected > 0
+		return res.Error
+	})
+	return like, changed, err
+}
+
+// RemoveReaction deletes the user's reaction on a business. removed is false if there was none.
+func (r *InteractionRepository) RemoveReaction(userID, bizID uuid.UUID) (removed bool, err error) {
+	res := r.DB.Where("user_id = ? AND business_id = ?", userID, bizID).Delete(&models.Like{})
+	return res.RowsAffected > 0, res.Error
 }
 
-func (r *InteractionRepository) AddComment(comment *models.Comment) error {
-	return r.DB.Create(comment).Error
+// ReactedBusiness is a business the user has reacted to, with their reaction.
+type ReactedBusiness struct {
+	Business  models.Business     `json:"business"`
+	Reaction  models.ReactionType `json:"reaction"`
+	ReactedAt time.Time           `json:"reacted_at"`
 }
 
-func (r *InteractionRepository) AddInquiry(inquiry *models.Inquiry) error {
-	return r.DB.Create(inquiry).Error
+// GetReactionsByUser lists the businesses a user has reacted to, most recent first.
+// An empty reaction returns every type.
+func (r *InteractionRepository) GetReactionsByUser(userID uuid.UUID, reaction string, limit, offset int) ([]ReactedBusiness, int64, error) {
+	var likes []models.Like
+	var total int64
+
+	query := r.DB.Model(&models.Like{}).Where("user_id = ?", userID)
+	if reaction != "" {
+		query = query.Where("type = ?", reaction)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Preload("Business").Order("created_at desc").Limit(limit).Offset(offset).Find(&likes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]ReactedBusiness, 0, len(likes))
+	for _, like := range likes {
+		items = append(items, ReactedBusiness{Business: like.Business, Reaction: like.Type, ReactedAt: like.CreatedAt})
+	}
+	return items, total, nil
 }
 
-func (r *InteractionRepository) GetCommentsByBusiness(bizID string) ([]models.Comment, error) {
-	var comments []models.Comment
-	
+// BusinessExists reports whether a business with this ID exists.
+func (r *InteractionRepository) BusinessExists(bizID uuid.UUID) bool {
+	var count int64
+	r.DB.Model(&models.Business{}).Where("id = ?", bizID).Count(&count)
+	return count > 0
+}
+
+// GetCommentThreads returns a page of top-level comments for a business, each with its
+// replies nested underneath, plus the total number of top-level comments.
+//
+// sort is "newest" (default) or "top" (threads with the most replies first).
+func (r *InteractionRepository) GetCommentThreads(bizID string, sort string, limit, offset int) ([]models.Comment, int64, error) {
+	var roots []models.Comment
+	var total int64
+
+	// Deleted or hidden top-level comments only stay visible while they still have live replies under them.
+	query := r.DB.Model(&models.Comment{}).
+		Where("business_id = ? AND parent_id IS NULL AND is_quarantined = ?", bizID, false).
+		Where("(deleted_at IS NULL AND is_hidden = ?) OR EXISTS (SELECT 1 FROM comments r WHERE r.root_id = comments.id AND r.deleted_at IS NULL AND r.is_hidden = ? AND r.is_quarantined = ?)", false, false, false)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Pinned threads always come first.
+	query = query.Order("is_pinned desc")
+	if sort == "top" {
+		query = query.Order("(SELECT count(*) FROM comments r WHERE r.root_id = comments.id AND r.deleted_at IS NULL AND r.is_hidden = false AND r.is_quarantined = false) desc")
+	}
+
 	// Preload("User") is a GORM feature that automatically fetches the associated User 
 	// for each comment. Under the hood, it usually runs two queries or a JOIN.
 	// Without this, the 'User' field in the Comment struct would be empty/zero-valued.
-	err := r.DB.Preload("User").Where("business_id = ?", bizID).Find(&comments).Error
-	return comments, err
+	err := query.Preload("User").Order("created_at desc").Limit(limit).Offset(offset).Find(&roots).Error
+	if err != nil || len(roots) == 0 {
+		return roots, total, err
+	}
+
+	rootIDs := make([]uuid.UUID, len(roots))
+	for i, root := range roots {
+		rootIDs[i] = root.ID
+	}
+
+	// One query for every reply in the page's threads, oldest first so conversations read top-down.
+	var replies []models.Comment
+	err = r.DB.Preload("User").
+		Where("root_id IN ? AND is_quarantined = ?", rootIDs, false).
+		Order("created_at asc").Find(&replies).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return buildCommentTree(roots, replies), total, nil
+}
+
+// buildCommentTree attaches replies to their parents. Deleted or hidden replies with nothing
+// beneath them are dropped since they carry no content and hold no thread together.
+func buildCommentTree(roots, replies []models.Comment) []models.Comment {
+	children := make(map[uuid.UUID][]models.Comment)
+	for _, reply := range replies {
+		children[*reply.ParentID] = append(children[*reply.ParentID], reply)
+	}
+
+	var attach func(c *models.Comment)
+	attach = func(c *models.Comment) {
+		for _, child := range children[c.ID] {
+			attach(&child)
+			if (child.DeletedAt != nil || child.IsHidden) && len(child.Replies) == 0 {
+				continue
+			}
+			c.Replies = append(c.Replies, child)
+		}
+		c.ReplyCount = len(c.Replies)
+	}
+
+	for i := range roots {
+		attach(&roots[i])
+	}
+	return roots
+}
+
+// GetComment fetches a single comment by ID.
+func (r *InteractionRepository) GetComment(id string) (*models.Comment, error) {
+	var comment models.Comment
+	if err := r.DB.Where("id = ?", id).First(&comment).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// EditComment replaces the content, archiving the previous version as a revision. When
+// held is set the spam checks flagged the new text: the comment goes back into quarantine
+// and held is saved as its review entry.
+func (r *InteractionRepository) EditComment(comment *models.Comment, content string, held *models.QuarantineEntry) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		revision := models.CommentRevision{CommentID: comment.ID, Content: comment.Content}
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{"content": content, "edited_at": now}
+		if held != nil {
+			updates["is_quarantined"] = true
+			comment.IsQuarantined = true
+		}
+		comment.Content = content
+		comment.EditedAt = &now
+		if err := tx.Model(comment).Updates(updates).Error; err != nil {
+			return err
+		}
+		if held == nil {
+			return nil
+		}
+		return tx.Create(held).Error
+	})
+}
+
+// SoftDeleteComment marks a comment deleted while keeping the row so its replies stay in place.
+func (r *InteractionRepository) SoftDeleteComment(comment *models.Comment) error {
+	now := time.Now()
+	comment.DeletedAt = &now
+	return r.DB.Model(comment).Update("deleted_at", now).Error
+}
+
+// SetCommentHidden hides or restores a comment on behalf of a business owner or admin.
+func (r *InteractionRepository) SetCommentHidden(comment *models.Comment, hidden bool, by uuid.UUID) error {
+	var hiddenBy *uuid.UUID
+	if hidden {
+		hiddenBy = &by
+	}
+	comment.IsHidden, comment.HiddenBy = hidden, hiddenBy
+	return r.DB.Model(comment).Updates(map[string]interface{}{"is_hidden": hidden, "hidden_by": hiddenBy}).Error
+}
+
+// SetCommentPinned pins or unpins a top-level comment.
+func (r *InteractionRepository) SetCommentPinned(comment *models.Comment, pinned bool) error {
+	comment.IsPinned = pinned
+	return r.DB.Model(comment).Update("is_pinned", pinned).Error
+}
+
+// GetCommentRevisions lists a comment's previous versions, newest first.
+func (r *InteractionRepository) GetCommentRevisions(commentID string) ([]models.CommentRevision, error) {
+	var revisions []models.CommentRevision
+	err := r.DB.Where("comment_id = ?", commentID).Order("created_at desc").Find(&revisions).Error
+	return revisions, err
 }
 
 func (r *InteractionRepository) AddChatMessage(msg *models.ChatMessage) error {
@@ -43,29 +246,17 @@ func (r *InteractionRepository) GetGlobalChatMessages(limit int) ([]models.ChatM
 
 func (r *InteractionRepository) GetInquiriesByBusiness(bizID string) ([]models.Inquiry, error) {
 	var inquiries []models.Inquiry
-	err := r.DB.Preload("User").Where("business_id = ?", bizID).Order("created_at desc").Find(&inquiries).Error
+	err := r.DB.Preload("User").Where("business_id = ? AND is_quarantined = ?", bizID, false).Order("created_at desc").Find(&inquiries).Error
 	return inquiries, err
 }
 
-// UpdateHealthScore calculates a 'popularity' score based on interactions and updates the main record.
-func (r *InteractionRepository) UpdateHealthScore(bizID string) (int, error) {
-	var likesCount int64
-	var commentsCount int64
-
-	// Count interactions
-	r.DB.Model(&models.Like{}).Where("business_id = ?", bizID).Count(&likesCount)
-	r.DB.Model(&models.Comment{}).Where("business_id = ?", bizID).Count(&commentsCount)
-
-	// Formula: (Likes * 2) + (Comments * 5)
-	newScore := (int(likesCount) * 2) + (int(commentsCount) * 5)
-
-	// Update the Business table
-	err := r.DB.Table("businesses").Where("id = ?", bizID).Update("health_score", newScore).Error
-	return newScore, err
-}
-
-func (r *InteractionRepository) AddNewsletterSubscriber(subscriber *models.NewsletterSubscriber) error {
-	return r.DB.Create(subscriber).Error
+// GetInquiry fetches a single inquiry.
+func (r *InteractionRepository) GetInquiry(id string) (*models.Inquiry, error) {
+	var inquiry models.Inquiry
+	if err := r.DB.Where("id = ?", id).First(&inquiry).Error; err != nil {
+		return nil, err
+	}
+	return &inquiry, nil
 }
 
 func (r *InteractionRepository) UpdateInquiryStatus(id string, status string) error {
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+)
+
+// LoginAttemptRepository stores the login audit trail and the failure counters that
+// throttle password guessing.
+type LoginAttemptRepository struct {
+	DB *gorm.DB
+}
+
+// Record adds an attempt to the audit trail.
+func (r *LoginAttemptRepository) Record(attempt *models.LoginAttempt) error {
+	if len(attempt.UserAgent) > 255 {
+		attempt.UserAgent = attempt.UserAgent[:255]
+	}
+	return r.DB.Create(attempt).Error
+}
+
+// LoginAttemptFilter narrows List. Empty fields match everything.
+type LoginAttemptFilter struct {
+	Email   string
+	IP      string
+	UserID  *uuid.UUID
+	Outcome string
+}
+
+// List returns attempts, newest first.
+func (r *LoginAttemptRepository) List(filter LoginAttemptFilter, limit, offset int) ([]models.LoginAttempt, int64, error) {
+	query := r.DB.Model(&models.LoginAttempt{})
+	if filter.Email != "" {
+		query = query.Where("LOWER(email) = LOWER(?)", filter.Email)
+	}
+	if filter.IP != "" {
+		query = query.Where("ip = ?", filter.IP)
+	}
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Outcome != "" {
+		query = query.Where("outcome = ?", filter.Outcome)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	list := []models.LoginAttempt{}
+	err := query.Order("created_at desc").Limit(limit).Offset(offset).Find(&list).Error
+	return list, total, err
+}
+
+// Throttle returns the failure counter for key; a key without failures has a zero counter.
+func (r *LoginAttemptRepository) Throttle(key string) (*models.LoginThrottle, error) {
+	var t models.LoginThrottle
+	err := r.DB.Where("throttle_key = ?", key).First(&t).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.LoginThrottle{ThrottleKey: key}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// LoginBackoff is how failed logins against one key slow down the next attempt. The first
+// Free failures are free; after that each one doubles the wait, up to Max. Failures older
+// than Window are forgotten, lock included.
+type LoginBackoff struct {
+	Free   int
+	Base   time.Duration
+	Max    time.Duration
+	Window time.Duration
+}
+
+// Wait is how long from now the next attempt against t has to wait; zero or less means it
+// can go ahead.
+func (b LoginBackoff) Wait(t *models.LoginThrottle, now time.Time) time.Duration {
+	if t.Failures <= b.Free {
+		return 0
+	}
+	delay := b.Base * time.Duration(math.Pow(2, float64(min(t.Failures-b.Free-1, 20))))
+	if delay > b.Max {
+		delay = b.Max
+	}
+	return t.LastFailureAt.Add(delay).Sub(now)
+}
+
+// Charge counts an attempt against key as a failure before it is made, unless key is
+// locked or still backing off. Checking and counting in one statement means parallel
+// requests can't all get through on the same count; an attempt that turns out not to be
+// a failure is given back with Refund. When the attempt has to wait, allowed is false and
+// t is the counter it was refused on.
+func (r *LoginAttemptRepository) Charge(key string, b LoginBackoff) (t *models.LoginThrottle, allowed bool, err error) {
+	var row models.LoginThrottle
+	res := r.DB.Raw(`INSERT INTO login_throttles (throttle_key, failures, last_failure_at) VALUES (@key, 1, NOW())
+		ON CONFLICT (throttle_key) DO UPDATE SET
+			failures = CASE WHEN login_throttles.last_failure_at < NOW() - make_interval(secs => @window) THEN 1
+				ELSE login_throttles.failures + 1 END,
+			locked_until = CASE WHEN login_throttles.last_failure_at < NOW() - make_interval(secs => @window) THEN NULL
+				ELSE login_throttles.locked_until END,
+			last_failure_at = NOW()
+		WHERE login_throttles.last_failure_at < NOW() - make_interval(secs => @window)
+			OR ((login_throttles.locked_until IS NULL OR login_throttles.locked_until <= NOW())
+				AND (login_throttles.failures <= @free
+					OR login_throttles.last_failure_at + make_interval(secs =>
+						LEAST(@base * power(2, LEAST(login_throttles.failures - @free - 1, 20)), @max)) <= NOW()))
+		RETURNING *`, map[string]interface{}{
+		"key":    key,
+		"window": b.Window.Seconds(),
+		"free":   b.Free,
+		"base":   b.Base.Seconds(),
+		"max":    b.Max.Seconds(),
+	}).Scan(&row)
+	if res.Error != nil {
+		return nil, false, res.Error
+	}
+	if res.RowsAffected > 0 {
+		return &row, true, nil
+	}
+	t, err = r.Throttle(key)
+	return t, false, err
+}
+
+// Refund gives back an attempt Charge counted that turned out not to be a failure.
+func (r *LoginAttemptRepository) Refund(key string) error {
+	return r.DB.Model(&models.LoginThrottle{}).
+		Where("throttle_key = ? AND failures > 0", key).
+		Update("failures", gorm.Expr("failures - 1")).Error
+}
+
+// Lock stops key from signing in until the given time once it has threshold failures. It
+// reports whether the key was newly locked, rather than already locked or under the
+// threshold, so the owner is only told once.
+func (r *LoginAttemptRepository) Lock(key string, threshold int, until time.Time) (bool, error) {
+	res := r.DB.Model(&models.LoginThrottle{}).
+		Where("throttle_key = ? AND failures >= ? AND (locked_until IS NULL OR locked_until < NOW())", key, threshold).
+		Update("locked_until", until)
+	return res.RowsAffected > 0, res.Error
+}
+
+// Notify reports whether the owner of key should be told about a lock, and notes that
+// they were. Someone who keeps locking an account only gets it one email per interval.
+func (r *LoginAttemptRepository) Notify(key string, interval time.Duration) (bool, error) {
+	res := r.DB.Model(&models.LoginThrottle{}).
+		Where("throttle_key = ? AND (notified_at IS NULL OR notified_at < NOW() - make_interval(secs => ?))", key, interval.Seconds()).
+		Update("notified_at", gorm.Expr("NOW()"))
+	return res.RowsAffected > 0, res.Error
+}
+
+// Prune deletes attempts older than retention, and counters that are unlocked and haven't
+// had a failure in idle. It returns how many of each it deleted.
+func (r *LoginAttemptRepository) Prune(retention, idle time.Duration) (attempts, throttles int64, err error) {
+	res := r.DB.Where("created_at < ?", time.Now().Add(-retention)).Delete(&models.LoginAttempt{})
+	if res.Error != nil {
+		return 0, 0, res.Error
+	}
+	attempts = res.RowsAffected
+	res = r.DB.Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until < NOW())", time.Now().Add(-idle)).
+		Delete(&models.LoginThrottle{})
+	return attempts, res.RowsAffected, res.Error
+}
+
+// Clear forgets the failures against key, e.g. after a successful login or a password reset.
+func (r *LoginAttemptRepository) Clear(key string) error {
+	return r.DB.Where("throttle_key = ?", key).Delete(&models.LoginThrottle{}).Error
+}
@@ -13,6 +13,14 @@ func (r *MeetingRepository) Create(m *models.Meeting) error {
 	return r.DB.Create(m).Error
 }
 
+func (r *MeetingRepository) GetByID(id string) (*models.Meeting, error) {
+	var meeting models.Meeting
+	if err := r.DB.Where("id = ?", id).First(&meeting).Error; err != nil {
+		return nil, err
+	}
+	return &meeting, nil
+}
+
 func (r *MeetingRepository) GetByBusinessID(bizID string) ([]models.Meeting, error) {
 	var meetings []models.Meeting
 	err := r.DB.Preload("User").Where("business_id = ?", bizID).Order("start_time asc").Find(&meetings).Error
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrUnknownEntityType is returned when a quarantine entry points at a table we don't manage.
+var ErrUnknownEntityType = errors.New("unknown quarantine entity type")
+
+// ErrQuarantinedContentMissing is returned when the content a quarantine entry holds back
+// no longer exists, so reviewing it would change nothing.
+var ErrQuarantinedContentMissing = errors.New("quarantined content not found")
+
+// quarantineTables maps quarantine entity types to the table holding the content.
+var quarantineTables = map[string]string{
+	models.QuarantineEntityComment:         "comments",
+	models.QuarantineEntityInquiry:         "inquiries",
+	models.QuarantineEntityPlatformInquiry: "platform_inquiries",
+}
+
+type ModerationRepository struct {
+	DB *gorm.DB
+}
+
+// Submit saves a new comment or inquiry. When entry is set the submission was held back,
+// and its quarantine entry is saved in the same transaction so admins always see it.
+func (r *ModerationRepository) Submit(content interface{}, entry *models.QuarantineEntry) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(content).Error; err != nil {
+			return err
+		}
+		if entry == nil {
+			return nil
+		}
+		return tx.Create(entry).Error
+	})
+}
+
+// ListQuarantine returns quarantine entries, newest first, optionally filtered by status and entity type.
+func (r *ModerationRepository) ListQuarantine(status, entityType string, limit, offset int) ([]models.QuarantineEntry, int64, error) {
+	var entries []models.QuarantineEntry
+	var total int64
+
+	query := r.DB.Model(&models.QuarantineEntry{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if entityType != "" {
+		query = query.Where("entity_type = ?", entityType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("created_at desc").Limit(limit).Offset(offset).Find(&entries).Error
+	return entries, total, err
+}
+
+// GetQuarantineEntry fetches a single entry by ID.
+func (r *ModerationRepository) GetQuarantineEntry(id string) (*models.QuarantineEntry, error) {
+	var entry models.QuarantineEntry
+	if err := r.DB.Where("id = ?", id).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Approve releases the content back into public listings.
+func (r *ModerationRepository) Approve(entry *models.QuarantineEntry, reviewerID uuid.UUID) error {
+	table, ok := quarantineTables[entry.EntityType]
+	if !ok {
+		return ErrUnknownEntityType
+	}
+
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Table(table).Where("id = ?", entry.EntityID).Update("is_quarantined", false)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrQuarantinedContentMissing
+		}
+		return r.review(tx, entry, models.QuarantineStatusApproved, reviewerID)
+	})
+}
+
+// Reject permanently deletes the quarantined content and closes the entry.
+func (r *ModerationRepository) Reject(entry *models.QuarantineEntry, reviewerID uuid.UUID) error {
+	table, ok := quarantineTables[entry.EntityType]
+	if !ok {
+		return ErrUnknownEntityType
+	}
+
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var res *gorm.DB
+		if entry.IsEdit {
+			// The comment may have replies by now, so delete it the way its author could
+			res = tx.Table(table).Where("id = ?", entry.EntityID).
+				Updates(map[string]interface{}{"deleted_at": time.Now(), "is_quarantined": false})
+		} else {
+			res = tx.Exec("DELETE FROM "+table+" WHERE id = ?", entry.EntityID)
+		}
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrQuarantinedContentMissing
+		}
+		return r.review(tx, entry, models.QuarantineStatusRejected, reviewerID)
+	})
+}
+
+func (r *ModerationRepository) review(tx *gorm.DB, entry *models.QuarantineEntry, status models.QuarantineStatus, reviewerID uuid.UUID) error {
+	now := time.Now()
+	entry.Status = status
+	entry.ReviewedBy = &reviewerID
+	entry.ReviewedAt = &now
+	return tx.Model(entry).Updates(map[string]interface{}{
+		"status":      status,
+		"reviewed_by": reviewerID,
+		"reviewed_at": now,
+	}).Error
+}
+
+// --- Comment reports ---
+
+// ReportedComment groups the open reports filed against one comment for the admin queue.
+type ReportedComment struct {
+	Comment        models.Comment `json:"comment"`
+	ReportCount    int64          `json:"report_count"`
+	Reasons        string         `json:"reasons"` // Comma-separated distinct reasons
+	LastReportedAt time.Time      `json:"last_reported_at"`
+}
+
+// AddReport files a user's report. A second report from the same user on the same comment fails
+// on the unique index.
+func (r *ModerationRepository) AddReport(report *models.CommentReport) error {
+	return r.DB.Create(report).Error
+}
+
+// ListReportedComments returns comments with open reports, most reported first.
+func (r *ModerationRepository) ListReportedComments(limit, offset int) ([]ReportedComment, int64, error) {
+	var total int64
+	err := r.DB.Model(&models.CommentReport{}).
+		Where("status = ?", models.ReportStatusOpen).
+		Distinct("comment_id").Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var rows []struct {
+		CommentID      uuid.UUID
+		ReportCount    int64
+		Reasons        string
+		LastReportedAt time.Time
+	}
+	err = r.DB.Model(&models.CommentReport{}).
+		Select("comment_id, count(*) as report_count, string_agg(DISTINCT reason, ',') as reasons, max(created_at) as last_reported_at").
+		Where("status = ?", models.ReportStatusOpen).
+		Group("comment_id").
+		Order("report_count desc, last_reported_at desc").
+		Limit(limit).Offset(offset).
+		Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return nil, total, err
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.CommentID
+	}
+	var comments []models.Comment
+	if err := r.DB.Preload("User").Where("id IN ?", ids).Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[uuid.UUID]models.Comment, len(comments))
+	for _, comment := range comments {
+		byID[comment.ID] = comment
+	}
+
+	items := make([]ReportedComment, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, ReportedComment{
+			Comment:        byID[row.CommentID],
+			ReportCount:    row.ReportCount,
+			Reasons:        row.Reasons,
+			LastReportedAt: row.LastReportedAt,
+		})
+	}
+	return items, total, nil
+}
+
+// GetReportsForComment lists every report filed against a comment.
+func (r *ModerationRepository) GetReportsForComment(commentID string) ([]models.CommentReport, error) {
+	var reports []models.CommentReport
+	err := r.DB.Where("comment_id = ?", commentID).Order("created_at desc").Find(&reports).Error
+	return reports, err
+}
+
+// CloseReports marks all open reports on the given comments as resolved or dismissed.
+func (r *ModerationRepository) CloseReports(tx *gorm.DB, commentIDs []uuid.UUID, status models.ReportStatus, reviewerID uuid.UUID) error {
+	return tx.Model(&models.CommentReport{}).
+		Where("comment_id IN ? AND status = ?", commentIDs, models.ReportStatusOpen).
+		Updates(map[string]interface{}{"status": status, "resolved_by": reviewerID, "resolved_at": time.Now()}).Error
+}
+
+// ApplyBulkAction performs an admin moderation action on several comments at once and closes
+// their open reports. It returns the IDs of the businesses whose comments changed so scores
+// can be recalculated.
+//
+// Actions: "hide", "unhide", "delete", "dismiss".
+func (r *ModerationRepository) ApplyBulkAction(commentIDs []uuid.UUID, action string, reviewerID uuid.UUID) ([]uuid.UUID, error) {
+	var bizIDs []uuid.UUID
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		comments := tx.Model(&models.Comment{}).Where("id IN ?", commentIDs)
+
+		status := models.ReportStatusResolved
+		switch action {
+		case "hide":
+			if err := comments.Updates(map[string]interface{}{"is_hidden": true, "hidden_by": reviewerID}).Error; err != nil {
+				return err
+			}
+		case "unhide":
+			if err := comments.Updates(map[string]interface{}{"is_hidden": false, "hidden_by": nil}).Error; err != nil {
+				return err
+			}
+			status = models.ReportStatusDismissed
+		case "delete":
+			if err := comments.Update("deleted_at", time.Now()).Error; err != nil {
+				return err
+			}
+		case "dismiss":
+			status = models.ReportStatusDismissed
+		default:
+			return ErrUnknownModerationAction
+		}
+
+		if err := r.CloseReports(tx, commentIDs, status, reviewerID); err != nil {
+			return err
+		}
+		return tx.Model(&models.Comment{}).Where("id IN ?", commentIDs).Distinct().Pluck("business_id", &bizIDs).Error
+	})
+	return bizIDs, err
+}
+
+// ErrUnknownModerationAction is returned for bulk actions we don't support.
+var ErrUnknownModerationAction = errors.New("unknown moderation action")
+
+// --- Bans ---
+
+// BanUser stops a user from posting until `until` (nil for a permanent ban).
+func (r *ModerationRepository) BanUser(userID string, reason string, until *time.Time) error {
+	res := r.DB.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"banned_at":    time.Now(),
+		"banned_until": until,
+		"ban_reason":   reason,
+	})
+	if res.Error == nil && res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return res.Error
+}
+
+// UnbanUser lifts a ban.
+func (r *ModerationRepository) UnbanUser(userID string) error {
+	return r.DB.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"banned_at":    nil,
+		"banned_until": nil,
+		"ban_reason":   "",
+	}).Error
+}
+
+// IsBanned reports whether the user currently has an active ban.
+func (r *ModerationRepository) IsBanned(userID interface{}) bool {
+	var user models.User
+	if err := r.DB.Select("id", "banned_at", "banned_until").Where("id = ?", userID).First(&user).Error; err != nil {
+		return false
+	}
+	return user.IsBanned()
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/database/dbtest"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+)
+
+// newTestModeration returns a moderation repository and a business with one user to post on it.
+func newTestModeration(t *testing.T) (*ModerationRepository, *models.Business, *models.User) {
+	t.Helper()
+	db := dbtest.Open(t, &models.User{}, &models.Business{}, &models.Comment{}, &models.Inquiry{}, &models.QuarantineEntry{})
+	user := &models.User{ID: uuid.New(), Email: "amina@example.com", Password: "x"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	biz := &models.Business{Name: "Duka", OwnerID: user.ID}
+	if err := db.Create(biz).Error; err != nil {
+		t.Fatalf("create business: %v", err)
+	}
+	return &ModerationRepository{DB: db}, biz, user
+}
+
+// hold submits content the way the controllers do when the spam check flags it.
+func hold(t *testing.T, r *ModerationRepository, content interface{}, entityType string, id, userID uuid.UUID) *models.QuarantineEntry {
+	t.Helper()
+	entry := &models.QuarantineEntry{EntityType: entityType, EntityID: id, UserID: &userID, Excerpt: "held"}
+	if err := r.Submit(content, entry); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	return entry
+}
+
+func TestApproveReleasesHeldComment(t *testing.T) {
+	r, biz, user := newTestModeration(t)
+	comment := &models.Comment{ID: uuid.New(), UserID: user.ID, BusinessID: biz.ID, Content: "casino", IsQuarantined: true}
+	entry := hold(t, r, comment, models.QuarantineEntityComment, comment.ID, user.ID)
+	if comment.ID != entry.EntityID {
+		t.Fatalf("comment saved as %s but the entry points at %s", comment.ID, entry.EntityID)
+	}
+
+	threads := &InteractionRepository{DB: r.DB}
+	if _, total, _ := threads.GetCommentThreads(biz.ID.String(), "newest", 10, 0); total != 0 {
+		t.Fatalf("a held comment is listed before review")
+	}
+	if err := r.Approve(entry, user.ID); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	list, total, err := threads.GetCommentThreads(biz.ID.String(), "newest", 10, 0)
+	if err != nil {
+		t.Fatalf("GetCommentThreads: %v", err)
+	}
+	if total != 1 || list[0].ID != comment.ID {
+		t.Fatalf("after approval got %d comments, want the approved one", total)
+	}
+	stored, _ := r.GetQuarantineEntry(entry.ID.String())
+	if stored.Status != models.QuarantineStatusApproved || stored.ReviewedBy == nil {
+		t.Fatalf("entry status = %q, reviewed_by = %v", stored.Status, stored.ReviewedBy)
+	}
+}
+
+func TestRejectDeletesHeldInquiry(t *testing.T) {
+	r, biz, user := newTestModeration(t)
+	inquiry := &models.Inquiry{ID: uuid.New(), UserID: user.ID, BusinessID: biz.ID, Subject: "Hi", Message: "free money", IsQuarantined: true}
+	entry := hold(t, r, inquiry, models.QuarantineEntityInquiry, inquiry.ID, user.ID)
+
+	if err := r.Reject(entry, user.ID); err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+	if err := r.DB.First(&models.Inquiry{}, "id = ?", inquiry.ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("rejected inquiry still exists: %v", err)
+	}
+}
+
+func TestReviewFailsWhenContentIsMissing(t *testing.T) {
+	r, _, user := newTestModeration(t)
+	entry := &models.QuarantineEntry{EntityType: models.QuarantineEntityComment, EntityID: uuid.New(), Excerpt: "gone"}
+	if err := r.DB.Create(entry).Error; err != nil {
+		t.Fatalf("create entry: %v", err)
+	}
+
+	if err := r.Approve(entry, user.ID); !errors.Is(err, ErrQuarantinedContentMissing) {
+		t.Fatalf("Approve err = %v, want ErrQuarantinedContentMissing", err)
+	}
+	if err := r.Reject(entry, user.ID); !errors.Is(err, ErrQuarantinedContentMissing) {
+		t.Fatalf("Reject err = %v, want ErrQuarantinedContentMissing", err)
+	}
+	stored, _ := r.GetQuarantineEntry(entry.ID.String())
+	if stored.Status != models.QuarantineStatusPending {
+		t.Fatalf("entry status = %q, want it left pending", stored.Status)
+	}
+}
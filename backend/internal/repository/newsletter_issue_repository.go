@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+)
+
+// NewsletterIssueRepository stores newsletter issues, their recipients and engagement.
+type NewsletterIssueRepository struct {
+	DB *gorm.DB
+}
+
+// IssueContent is the platform content a new issue is composed from.
+type IssueContent struct {
+	News       []models.News
+	Events     []models.Event
+	Businesses []models.Business
+	Blogs      []models.Blog
+}
+
+// Content gathers what's new since the given time: the latest public news and blog posts,
+// events starting within the next month and the healthiest featured public businesses.
+func (r *NewsletterIssueRepository) Content(since time.Time, limit int) (*IssueContent, error) {
+	now := time.Now()
+	var c IssueContent
+	if err := r.DB.Where("is_public = ? AND published_at >= ? AND published_at <= ?", true, since, now).
+		Order("published_at desc").Limit(limit).Find(&c.News).Error; err != nil {
+		return nil, err
+	}
+	if err := r.DB.Where("is_published = ? AND start_date >= ? AND start_date < ?", true, now, now.AddDate(0, 1, 0)).
+		Order("start_date asc").Limit(limit).Find(&c.Events).Error; err != nil {
+		return nil, err
+	}
+	if err := r.DB.Where("is_featured = ? AND is_public = ?", true, true).
+		Order("health_score desc").Limit(3).Find(&c.Businesses).Error; err != nil {
+		return nil, err
+	}
+	if err := r.DB.Where("published_at >= ? AND published_at <= ?", since, now).
+		Order("published_at desc").Limit(3).Find(&c.Blogs).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *NewsletterIssueRepository) Create(issue *models.NewsletterIssue) error {
+	return r.DB.Create(issue).Error
+}
+
+func (r *NewsletterIssueRepository) Get(id string) (*models.NewsletterIssue, error) {
+	var issue models.NewsletterIssue
+	if err := r.DB.Where("id = ?", id).First(&issue).Error; err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// List returns issues newest first, optionally filtered by status.
+func (r *NewsletterIssueRepository) List(status string, limit, offset int) ([]models.NewsletterIssue, int64, error) {
+	query := r.DB.Model(&models.NewsletterIssue{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []models.NewsletterIssue
+	err := query.Order("created_at desc").Limit(limit).Offset(offset).Find(&list).Error
+	return list, total, err
+}
+
+// SaveContent stores an admin's edits to the subject, intro and sections.
+func (r *NewsletterIssueRepository) SaveContent(issue *models.NewsletterIssue) error {
+	return r.DB.Model(issue).Select("subject", "intro", "sections").Updates(issue).Error
+}
+
+// Delete removes a draft. Returns false if the issue doesn't exist or isn't a draft.
+func (r *NewsletterIssueRepository) Delete(id string) (bool, error) {
+	res := r.DB.Where("id = ? AND status = ?", id, models.IssueStatusDraft).Delete(&models.NewsletterIssue{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// Schedule sets when a draft or scheduled issue goes out. Returns false if it has already started.
+func (r *NewsletterIssueRepository) Schedule(id string, at time.Time) (bool, error) {
+	res := r.DB.Model(&models.NewsletterIssue{}).
+		Where("id = ? AND status IN ?", id, []models.IssueStatus{models.IssueStatusDraft, models.IssueStatusScheduled}).
+		Updates(map[string]interface{}{"status": models.IssueStatusScheduled, "scheduled_at": at})
+	return res.RowsAffected > 0, res.Error
+}
+
+// Cancel returns a scheduled issue to draft, or stops one that is sending: recipients
+// whose batch hasn't gone out yet are skipped. Returns false if there was nothing to cancel.
+func (r *NewsletterIssueRepository) Cancel(id string) (bool, error) {
+	res := r.DB.Model(&models.NewsletterIssue{}).
+		Where("id = ? AND status = ?", id, models.IssueStatusScheduled).
+		Updates(map[string]interface{}{"status": models.IssueStatusDraft, "scheduled_at": nil})
+	if res.Error != nil || res.RowsAffected > 0 {
+		return res.RowsAffected > 0, res.Error
+	}
+
+	var cancelled bool
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.NewsletterIssue{}).
+			Where("id = ? AND status = ?", id, models.IssueStatusSending).
+			Update("status", models.IssueStatusCancelled)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		cancelled = true
+		return tx.Model(&models.NewsletterDelivery{}).
+			Where("issue_id = ? AND status = ?", id, models.DeliveryStatusPending).
+			Update("status", models.DeliveryStatusSkipped).Error
+	})
+	return cancelled, err
+}
+
+// StartSending moves a scheduled issue whose time has come to sending and creates a pending
+// delivery for every confirmed subscriber who follows at least one of its topics (subscribers
+// without recorded topics get everything). Only one caller wins; the others get started=false.
+func (r *NewsletterIssueRepository) StartSending(issue *models.NewsletterIssue, now time.Time) (started bool, recipients int64, err error) {
+	err = r.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.NewsletterIssue{}).
+			Where("id = ? AND status = ? AND scheduled_at <= ?", issue.ID, models.IssueStatusScheduled, now).
+			Updates(map[string]interface{}{"status": models.IssueStatusSending, "started_at": now})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		started = true
+
+		res = tx.Exec(`INSERT INTO newsletter_deliveries (id, issue_id, subscriber_id, email, status, created_at)
+			SELECT gen_random_uuid(), ?, s.id, s.email, ?, NOW()
+			FROM newsletter_subscribers s
+			WHERE s.confirmed_at IS NOT NULL AND s.deleted_at IS NULL
+			AND (s.topics IS NULL OR s.topics IN ('', 'null', '[]')
+				OR jsonb_exists_any(s.topics::jsonb, string_to_array(?, ',')))
+			ON CONFLICT (issue_id, subscriber_id) DO NOTHING`,
+			issue.ID, models.DeliveryStatusPending, strings.Join(issue.Topics(), ","))
+		recipients = res.RowsAffected
+		return res.Error
+	})
+	return started, recipients, err
+}
+
+// ClaimDeliveries marks up to limit pending deliveries as queued and returns them.
+// Concurrent batches never claim the same rows. Run it in the transaction that queues the emails.
+func (r *NewsletterIssueRepository) ClaimDeliveries(tx *gorm.DB, issueID uuid.UUID, limit int) ([]models.NewsletterDelivery, error) {
+	var claimed []models.NewsletterDelivery
+	err := tx.Raw(`UPDATE newsletter_deliveries SET status = ?, queued_at = NOW()
+		WHERE id IN (
+			SELECT id FROM newsletter_deliveries
+			WHERE issue_id = ? AND status = ?
+			ORDER BY created_at, id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, models.DeliveryStatusQueued, issueID, models.DeliveryStatusPending, limit).Scan(&claimed).Error
+	return claimed, err
+}
+
+// ActiveSubscribers returns the given subscribers that are still on the list, keyed by ID.
+func (r *NewsletterIssueRepository) ActiveSubscribers(tx *gorm.DB, ids []string) (map[string]models.NewsletterSubscriber, error) {
+	var subs []models.NewsletterSubscriber
+	if err := tx.Where("id IN ? AND confirmed_at IS NOT NULL", ids).Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]models.NewsletterSubscriber, len(subs))
+	for _, s := range subs {
+		byID[s.ID] = s
+	}
+	return byID, nil
+}
+
+// MarkSkipped records deliveries that were claimed but not sent.
+func (r *NewsletterIssueRepository) MarkSkipped(tx *gorm.DB, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return tx.Model(&models.NewsletterDelivery{}).Where("id IN ?", ids).
+		Updates(map[string]interface{}{"status": models.DeliveryStatusSkipped, "queued_at": nil}).Error
+}
+
+// FinishIfDone marks a sending issue sent once no deliveries are pending.
+func (r *NewsletterIssueRepository) FinishIfDone(issueID uuid.UUID) (bool, error) {
+	res := r.DB.Exec(`UPDATE newsletter_issues SET status = ?, sent_at = NOW()
+		WHERE id = ? AND status = ?
+		AND NOT EXISTS (SELECT 1 FROM newsletter_deliveries WHERE issue_id = ? AND status = ?)`,
+		models.IssueStatusSent, issueID, models.IssueStatusSending, issueID, models.DeliveryStatusPending)
+	return res.RowsAffected > 0, res.Error
+}
+
+// GetDelivery fetches a delivery for open and click tracking.
+func (r *NewsletterIssueRepository) GetDelivery(id string) (*models.NewsletterDelivery, error) {
+	var d models.NewsletterDelivery
+	if err := r.DB.Where("id = ?", id).First(&d).Error; err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// RecordOpen stores the first time a delivery was opened.
+func (r *NewsletterIssueRepository) RecordOpen(d *models.NewsletterDelivery) error {
+	return r.DB.Model(d).Where("opened_at IS NULL").Update("opened_at", time.Now()).Error
+}
+
+// RecordClick logs a click. A click also counts as an open, since many clients block the pixel.
+func (r *NewsletterIssueRepository) RecordClick(d *models.NewsletterDelivery, url string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&models.NewsletterClick{DeliveryID: d.ID, IssueID: d.IssueID, URL: url}).Error; err != nil {
+			return err
+		}
+		return tx.Model(d).Updates(map[string]interface{}{
+			"clicked_at": gorm.Expr("COALESCE(clicked_at, NOW())"),
+			"opened_at":  gorm.Expr("COALESCE(opened_at, NOW())"),
+		}).Error
+	})
+}
+
+// IssueStats summarises how an issue performed.
+type IssueStats struct {
+	Recipients int64       `json:"recipients"`
+	Queued     int64       `json:"queued"`
+	Skipped    int64       `json:"skipped"`
+	Pending    int64       `json:"pending"`
+	Opened     int64       `json:"opened"`
+	Clicked    int64       `json:"clicked"`
+	OpenRate   float64     `json:"open_rate"`
+	ClickRate  float64     `json:"click_rate"`
+	Links      []LinkStats `json:"links"`
+}
+
+// LinkStats counts clicks on one link of an issue.
+type LinkStats struct {
+	URL          string `json:"url"`
+	Clicks       int64  `json:"clicks"`
+	UniqueClicks int64  `json:"unique_clicks"`
+}
+
+// Stats counts an issue's recipients, opens and clicks, with the most clicked links first.
+func (r *NewsletterIssueRepository) Stats(issueID string) (*IssueStats, error) {
+	var s IssueStats
+	err := r.DB.Model(&models.NewsletterDelivery{}).Where("issue_id = ?", issueID).Select(`
+		COUNT(*) AS recipients,
+		COUNT(*) FILTER (WHERE status = 'queued') AS queued,
+		COUNT(*) FILTER (WHERE status = 'skipped') AS skipped,
+		COUNT(*) FILTER (WHERE status = 'pending') AS pending,
+		COUNT(opened_at) AS opened,
+		COUNT(clicked_at) AS clicked`).Scan(&s).Error
+	if err != nil {
+		return nil, err
+	}
+	if s.Queued > 0 {
+		s.OpenRate = float64(s.Opened) / float64(s.Queued)
+		s.ClickRate = float64(s.Clicked) / float64(s.Queued)
+	}
+
+	err = r.DB.Model(&models.NewsletterClick{}).Where("issue_id = ?", issueID).
+		Select("url, COUNT(*) AS clicks, COUNT(DISTINCT delivery_id) AS unique_clicks").
+		Group("url").Order("clicks desc").Limit(20).Scan(&s.Links).Error
+	return &s, err
+}
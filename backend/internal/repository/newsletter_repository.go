@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+)
+
+// NewsletterRepository manages the newsletter list.
+type NewsletterRepository struct {
+	DB *gorm.DB
+}
+
+// Subscribe adds an address as pending confirmation. An address that unsubscribed earlier
+// is restored and must confirm again; one that is already confirmed is left untouched.
+// confirmed reports whether the address was already on the list.
+func (r *NewsletterRepository) Subscribe(email, locale string, topics []string) (sub *models.NewsletterSubscriber, confirmed bool, err error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if len(topics) == 0 {
+		topics = models.NewsletterTopics
+	}
+	if locale == "" {
+		locale = "en"
+	}
+
+	var existing models.NewsletterSubscriber
+	err = r.DB.Unscoped().Where("LOWER(email) = ?", email).First(&existing).Error
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		sub = &models.NewsletterSubscriber{Email: email, Locale: locale, Topics: topics}
+		return sub, false, r.DB.Create(sub).Error
+	case err != nil:
+		return nil, false, err
+	case !existing.DeletedAt.Valid && existing.ConfirmedAt != nil:
+		return &existing, true, nil
+	}
+
+	// Pending, or coming back after unsubscribing: start the opt-in again.
+	err = r.DB.Unscoped().Model(&existing).Updates(map[string]interface{}{
+		"topics":       topicsJSON(topics),
+		"locale":       locale,
+		"confirmed_at": nil,
+		"deleted_at":   nil,
+	}).Error
+	existing.Topics, existing.Locale, existing.ConfirmedAt, existing.DeletedAt = topics, locale, nil, gorm.DeletedAt{}
+	return &existing, false, err
+}
+
+// Confirm completes the double opt-in. It is idempotent; firstTime is false when the
+// address had already confirmed.
+func (r *NewsletterRepository) Confirm(email string) (sub *models.NewsletterSubscriber, firstTime bool, err error) {
+	sub, err = r.Get(email)
+	if err != nil {
+		return nil, false, err
+	}
+	if sub.ConfirmedAt != nil {
+		return sub, false, nil
+	}
+	now := time.Now()
+	if err := r.DB.Model(sub).Update("confirmed_at", now).Error; err != nil {
+		return nil, false, err
+	}
+	sub.ConfirmedAt = &now
+	return sub, true, nil
+}
+
+// Get fetches an address that is on the list, confirmed or not.
+func (r *NewsletterRepository) Get(email string) (*models.NewsletterSubscriber, error) {
+	var sub models.NewsletterSubscriber
+	err := r.DB.Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(email))).First(&sub).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// UpdateTopics replaces the topics an address receives.
+func (r *NewsletterRepository) UpdateTopics(sub *models.NewsletterSubscriber, topics []string) error {
+	sub.Topics = topics
+	return r.DB.Model(sub).Update("topics", topicsJSON(topics)).Error
+}
+
+// Unsubscribe removes an address from the list. It is soft-deleted so a later
+// sign-up restores it. Returns false if the address wasn't subscribed.
+func (r *NewsletterRepository) Unsubscribe(email string) (bool, error) {
+	res := r.DB.Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(email))).Delete(&models.NewsletterSubscriber{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// topicsJSON encodes topics the way the column's JSON serializer stores them,
+// for updates that go through a column map rather than the model.
+func topicsJSON(topics []string) string {
+	b, _ := json.Marshal(topics)
+	return string(b)
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationPreferenceRepository stores what users want to be notified about, and how.
+type NotificationPreferenceRepository struct {
+	DB *gorm.DB
+}
+
+// GetSettings returns the user's quiet hours and digest schedule, or the defaults.
+func (r *NotificationPreferenceRepository) GetSettings(userID uuid.UUID) (models.NotificationSettings, error) {
+	settings := models.DefaultNotificationSettings(userID)
+	err := r.DB.Where("user_id = ?", userID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return settings, nil
+	}
+	return settings, err
+}
+
+// GetPreferences returns the full type x channel matrix for the user, with defaults filled in.
+func (r *NotificationPreferenceRepository) GetPreferences(userID uuid.UUID) ([]models.NotificationPreference, error) {
+	var saved []models.NotificationPreference
+	if err := r.DB.Where("user_id = ?", userID).Find(&saved).Error; err != nil {
+		return nil, err
+	}
+	overrides := map[[2]string]bool{}
+	for _, p := range saved {
+		overrides[[2]string{p.Type, p.Channel}] = p.Enabled
+	}
+
+	var prefs []models.NotificationPreference
+	for _, t := range models.NotificationTypes {
+		for _, ch := range models.NotificationChannels {
+			enabled, ok := overrides[[2]string{t, ch}]
+			if !ok {
+				enabled = models.DefaultChannelEnabled(t, ch)
+			}
+			prefs = append(prefs, models.NotificationPreference{UserID: userID, Type: t, Channel: ch, Enabled: enabled})
+		}
+	}
+	return prefs, nil
+}
+
+// ChannelEnabled reports whether the user wants notifications of notifType on channel.
+func (r *NotificationPreferenceRepository) ChannelEnabled(userID uuid.UUID, notifType, channel string) (bool, error) {
+	var pref models.NotificationPreference
+	err := r.DB.Where("user_id = ? AND type = ? AND channel = ?", userID, notifType, channel).First(&pref).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DefaultChannelEnabled(notifType, channel), nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return pref.Enabled, nil
+}
+
+// Save stores the user's settings and any preference overrides in one transaction.
+func (r *NotificationPreferenceRepository) Save(settings *models.NotificationSettings, prefs []models.NotificationPreference) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"quiet_hours_start", "quiet_hours_end", "timezone", "digest_frequency", "updated_at"}),
+		}).Create(settings).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range prefs {
+			prefs[i].UserID = settings.UserID
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}, {Name: "channel"}},
+				DoUpdates: clause.AssignmentColumns([]string{"enabled"}),
+			}).Create(&prefs[i]).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DueForDigest returns users whose digest period has elapsed and who have unread notifications.
+// Users without saved settings get the default (weekly) digest.
+func (r *NotificationPreferenceRepository) DueForDigest(now time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.DB.Table("users").
+		Select("users.id").
+		Joins("LEFT JOIN notification_settings s ON s.user_id = users.id").
+		Where(`(COALESCE(s.digest_frequency, ?) = ? AND COALESCE(s.last_digest_at, users.created_at) <= ?)
+			OR (COALESCE(s.digest_frequency, ?) = ? AND COALESCE(s.last_digest_at, users.created_at) <= ?)`,
+			models.DigestWeekly, models.DigestDaily, now.Add(-24*time.Hour),
+			models.DigestWeekly, models.DigestWeekly, now.Add(-7*24*time.Hour)).
+		Where("EXISTS (SELECT 1 FROM notifications n WHERE n.user_id = users.id AND n.is_read = false)").
+		Limit(limit).
+		Pluck("users.id", &ids).Error
+	return ids, err
+}
+
+// MarkDigestSent records when the user's last digest went out.
+func (r *NotificationPreferenceRepository) MarkDigestSent(userID uuid.UUID, at time.Time) error {
+	settings := models.DefaultNotificationSettings(userID)
+	settings.LastDigestAt = &at
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_digest_at"}),
+	}).Create(&settings).Error
+}
@@ -1,8 +1,17 @@
 package repository
 
 import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/saidimuKennedy/spotlight-africa/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type NotificationRepository struct {
@@ -13,16 +22,227 @@ func (r *NotificationRepository) Create(n *models.Notification) error {
 	return r.DB.Create(n).Error
 }
 
-func (r *NotificationRepository) GetByUserID(userID string) ([]models.Notification, error) {
+// Push delivers a notification, merging it into an unread notification with the same GroupKey
+// when there is one. groupMessage is a fmt format with a single %d for the merged count.
+// The same actor is only counted once per group. Returns the stored notification.
+// Pass the caller's transaction as tx to tie the notification to it, or nil.
+func (r *NotificationRepository) Push(tx *gorm.DB, n *models.Notification, actorID *uuid.UUID, groupMessage string) (*models.Notification, error) {
+	if tx == nil {
+		tx = r.DB
+	}
+	actor := ""
+	if actorID != nil {
+		actor = actorID.String()
+	}
+
+	if n.GroupKey == "" {
+		n.Count, n.ActorIDs = 1, actor
+		return n, tx.Create(n).Error
+	}
+
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		var existing models.Notification
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND group_key = ? AND is_read = ? AND archived_at IS NULL", n.UserID, n.GroupKey, false).
+			First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			n.Count, n.ActorIDs = 1, actor
+			return tx.Create(n).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		if actor != "" && containsActor(existing.ActorIDs, actor) {
+			*n = existing
+			return nil
+		}
+
+		existing.Count++
+		if actor != "" {
+			existing.ActorIDs = strings.Trim(existing.ActorIDs+","+actor, ",")
+		}
+		if groupMessage != "" {
+			existing.Message = fmt.Sprintf(groupMessage, existing.Count)
+		} else {
+			existing.Message = n.Message
+		}
+		existing.Link = n.Link
+		*n = existing
+		return tx.Model(&existing).Updates(map[string]interface{}{
+			"count":     existing.Count,
+			"actor_ids": existing.ActorIDs,
+			"message":   existing.Message,
+			"link":      existing.Link,
+		}).Error
+	})
+	return n, err
+}
+
+func containsActor(list, actor string) bool {
+	for _, id := range strings.Split(list, ",") {
+		if id == actor {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationFilter narrows an inbox listing.
+type NotificationFilter struct {
+	Types    []string
+	Read     *bool  // nil lists read and unread
+	Archived bool   // List the archive instead of the inbox
+	Cursor   string // next_cursor from the previous page
+	Limit    int
+}
+
+// ErrInvalidCursor is returned when a pagination cursor can't be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// notificationSortKey orders the inbox by last activity, so a group that grows moves back to the top.
+const notificationSortKey = "COALESCE(updated_at, created_at)"
+
+// List returns a page of the user's notifications, newest activity first, and the cursor
+// for the next page (empty on the last page).
+func (r *NotificationRepository) List(userID uuid.UUID, f NotificationFilter) ([]models.Notification, string, error) {
+	query := r.DB.Where("user_id = ?", userID)
+	if f.Archived {
+		query = query.Where("archived_at IS NOT NULL")
+	} else {
+		query = query.Where("archived_at IS NULL")
+	}
+	if len(f.Types) > 0 {
+		query = query.Where("type IN ?", f.Types)
+	}
+	if f.Read != nil {
+		query = query.Where("is_read = ?", *f.Read)
+	}
+	if f.Cursor != "" {
+		at, id, err := decodeCursor(f.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query = query.Where("("+notificationSortKey+", id) < (?, ?)", at, id)
+	}
+
+	var list []models.Notification
+	err := query.Order(notificationSortKey + " desc, id desc").Limit(f.Limit + 1).Find(&list).Error
+	if err != nil || len(list) <= f.Limit {
+		return list, "", err
+	}
+
+	list = list[:f.Limit]
+	last := list[len(list)-1]
+	sortAt := last.UpdatedAt
+	if sortAt.IsZero() {
+		sortAt = last.CreatedAt
+	}
+	return list, encodeCursor(sortAt, last.ID), nil
+}
+
+func encodeCursor(at time.Time, id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", at.UnixNano(), id)))
+}
+
+func decodeCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	nanos, idStr, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	return time.Unix(0, n), id, nil
+}
+
+// UnreadSince returns the user's unread notifications touched after since (all unread when nil), newest first.
+func (r *NotificationRepository) UnreadSince(userID uuid.UUID, since *time.Time, limit int) ([]models.Notification, error) {
+	query := r.DB.Where("user_id = ? AND is_read = ? AND archived_at IS NULL", userID, false)
+	if since != nil {
+		query = query.Where("COALESCE(updated_at, created_at) > ?", *since)
+	}
 	var notifications []models.Notification
-	err := r.DB.Where("user_id = ?", userID).Order("created_at desc").Limit(20).Find(&notifications).Error
+	err := query.Order("COALESCE(updated_at, created_at) desc").Limit(limit).Find(&notifications).Error
 	return notifications, err
 }
 
-func (r *NotificationRepository) MarkAsRead(id string) error {
-	return r.DB.Model(&models.Notification{}).Where("id = ?", id).Update("is_read", true).Error
+// Recipient loads the user a notification is addressed to.
+func (r *NotificationRepository) Recipient(userID uuid.UUID) (*models.User, error) {
+	var user models.User
+	if err := r.DB.Select("id, name, email, locale").Where("id = ?", userID).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// The methods below change a notification without touching updated_at (UpdateColumn):
+// it is the inbox's sort key, and reading or archiving an item mustn't move it.
+
+// MarkAsRead marks one of the user's notifications read. found is false when the
+// notification doesn't exist or belongs to someone else.
+func (r *NotificationRepository) MarkAsRead(id string, userID uuid.UUID) (found bool, err error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return false, nil
+	}
+	res := r.DB.Model(&models.Notification{}).Where("id = ? AND user_id = ?", id, userID).UpdateColumn("is_read", true)
+	return res.RowsAffected > 0, res.Error
 }
 
-func (r *NotificationRepository) MarkAllAsRead(userID string) error {
-	return r.DB.Model(&models.Notification{}).Where("user_id = ?", userID).Update("is_read", true).Error
+// MarkAllAsRead marks the user's notifications read, optionally only those of one type.
+func (r *NotificationRepository) MarkAllAsRead(userID uuid.UUID, notifType string) error {
+	query := r.DB.Model(&models.Notification{}).Where("user_id = ? AND is_read = ?", userID, false)
+	if notifType != "" {
+		query = query.Where("type = ?", notifType)
+	}
+	return query.UpdateColumn("is_read", true).Error
+}
+
+// SetArchived archives or restores one of the user's notifications.
+func (r *NotificationRepository) SetArchived(id string, userID uuid.UUID, archived bool) (found bool, err error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return false, nil
+	}
+	var archivedAt interface{}
+	if archived {
+		archivedAt = time.Now()
+	}
+	res := r.DB.Model(&models.Notification{}).Where("id = ? AND user_id = ?", id, userID).UpdateColumn("archived_at", archivedAt)
+	return res.RowsAffected > 0, res.Error
+}
+
+// Delete removes one of the user's notifications.
+func (r *NotificationRepository) Delete(id string, userID uuid.UUID) (found bool, err error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return false, nil
+	}
+	res := r.DB.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Notification{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// UnreadCounts returns the user's unread, unarchived notifications per type.
+func (r *NotificationRepository) UnreadCounts(userID uuid.UUID) (map[string]int64, error) {
+	var rows []struct {
+		Type  string
+		Count int64
+	}
+	err := r.DB.Model(&models.Notification{}).
+		Select("type, count(*) AS count").
+		Where("user_id = ? AND is_read = ? AND archived_at IS NULL", userID, false).
+		Group("type").Scan(&rows).Error
+
+	counts := map[string]int64{}
+	for _, row := range rows {
+		counts[row.Type] = row.Count
+	}
+	return counts, err
 }
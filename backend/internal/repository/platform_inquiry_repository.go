@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+)
+
+// PlatformInquiryFilter narrows the admin triage list.
+type PlatformInquiryFilter struct {
+	Status      string
+	AssignedTo  string // A user ID, or "none" for unassigned
+	Search      string // Matches name, email, subject or message
+	Quarantined bool   // Include spam-quarantined submissions
+	Limit       int
+	Offset      int
+}
+
+type PlatformInquiryRepository struct {
+	DB *gorm.DB
+}
+
+// List returns platform inquiries matching the filter, newest first, plus the total match count.
+func (r *PlatformInquiryRepository) List(f PlatformInquiryFilter) ([]models.PlatformInquiry, int64, error) {
+	var inquiries []models.PlatformInquiry
+	var total int64
+
+	query := r.DB.Model(&models.PlatformInquiry{}).Where("is_quarantined = ?", f.Quarantined)
+	if f.Status != "" {
+		query = query.Where("status = ?", f.Status)
+	}
+	switch f.AssignedTo {
+	case "":
+	case "none":
+		query = query.Where("assigned_to IS NULL")
+	default:
+		query = query.Where("assigned_to = ?", f.AssignedTo)
+	}
+	if f.Search != "" {
+		like := "%" + f.Search + "%"
+		query = query.Where("name ILIKE ? OR email ILIKE ? OR subject ILIKE ? OR message ILIKE ?", like, like, like, like)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("Assignee").Order("created_at desc").Limit(f.Limit).Offset(f.Offset).Find(&inquiries).Error
+	return inquiries, total, err
+}
+
+// GetByID fetches an inquiry along with its assignee and reply history.
+func (r *PlatformInquiryRepository) GetByID(id string) (*models.PlatformInquiry, error) {
+	var inquiry models.PlatformInquiry
+	err := r.DB.Preload("Assignee").
+		Preload("Replies", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc") }).
+		Preload("Replies.Author").
+		Preload("Replies.Email").
+		Where("id = ?", id).First(&inquiry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &inquiry, nil
+}
+
+// Assign hands the inquiry to an admin (or unassigns it when adminID is nil).
+// Pending inquiries move to in_progress once someone picks them up.
+func (r *PlatformInquiryRepository) Assign(inquiry *models.PlatformInquiry, adminID *uuid.UUID) error {
+	updates := map[string]interface{}{"assigned_to": adminID}
+	if adminID != nil && inquiry.Status == models.PlatformInquiryStatusPending {
+		updates["status"] = models.PlatformInquiryStatusInProgress
+	}
+	return r.DB.Model(inquiry).Updates(updates).Error
+}
+
+// UpdateStatus moves the inquiry through its lifecycle, stamping ClosedAt when it is closed.
+func (r *PlatformInquiryRepository) UpdateStatus(inquiry *models.PlatformInquiry, status string) error {
+	updates := map[string]interface{}{"status": status, "closed_at": nil}
+	if status == models.PlatformInquiryStatusClosed {
+		updates["closed_at"] = time.Now()
+	}
+	return r.DB.Model(inquiry).Updates(updates).Error
+}
+
+// AddReply stores a reply and marks the inquiry as responded (or closed). Pass the
+// caller's transaction as tx to tie the reply to it, or nil to save it on its own.
+func (r *PlatformInquiryRepository) AddReply(tx *gorm.DB, inquiry *models.PlatformInquiry, reply *models.PlatformInquiryReply, close bool) error {
+	if tx == nil {
+		tx = r.DB
+	}
+	return tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(reply).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{
+			"status":       models.PlatformInquiryStatusResponded,
+			"responded_at": now,
+		}
+		if close {
+			updates["status"] = models.PlatformInquiryStatusClosed
+			updates["closed_at"] = now
+		}
+		if inquiry.AssignedTo == nil {
+			updates["assigned_to"] = reply.AuthorID
+		}
+		return tx.Model(inquiry).Updates(updates).Error
+	})
+}
+
+// CountByStatus summarises the triage queue for the admin dashboard.
+func (r *PlatformInquiryRepository) CountByStatus() (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := r.DB.Model(&models.PlatformInquiry{}).
+		Select("status, count(*) as count").
+		Where("is_quarantined = ?", false).
+		Group("status").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int64{
+		models.PlatformInquiryStatusPending:    0,
+		models.PlatformInquiryStatusInProgress: 0,
+		models.PlatformInquiryStatusResponded:  0,
+		models.PlatformInquiryStatusClosed:     0,
+	}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// --- Canned responses ---
+
+func (r *PlatformInquiryRepository) ListCannedResponses() ([]models.CannedResponse, error) {
+	var responses []models.CannedResponse
+	err := r.DB.Order("title asc").Find(&responses).Error
+	return responses, err
+}
+
+func (r *PlatformInquiryRepository) GetCannedResponse(id string) (*models.CannedResponse, error) {
+	var response models.CannedResponse
+	if err := r.DB.Where("id = ?", id).First(&response).Error; err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (r *PlatformInquiryRepository) SaveCannedResponse(response *models.CannedResponse) error {
+	return r.DB.Save(response).Error
+}
+
+func (r *PlatformInquiryRepository) DeleteCannedResponse(id string) error {
+	return r.DB.Where("id = ?", id).Delete(&models.CannedResponse{}).Error
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRoleInUse is returned when deleting a role that users still hold.
+var ErrRoleInUse = errors.New("role is assigned to users")
+
+// RoleRepository stores roles and their permissions.
+type RoleRepository struct {
+	DB *gorm.DB
+}
+
+// RoleWithUsers is a role with the number of users holding it.
+type RoleWithUsers struct {
+	models.Role
+	Users int64 `json:"users"`
+}
+
+// List returns every role, built-in roles first.
+func (r *RoleRepository) List() ([]RoleWithUsers, error) {
+	var roles []models.Role
+	if err := r.DB.Order("system desc, name asc").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+
+	var counts []struct {
+		Role  string
+		Count int64
+	}
+	if err := r.DB.Model(&models.User{}).Select("role, COUNT(*) AS count").Group("role").Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	byRole := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		byRole[c.Role] = c.Count
+	}
+
+	list := make([]RoleWithUsers, len(roles))
+	for i, role := range roles {
+		list[i] = RoleWithUsers{Role: role, Users: byRole[role.Name]}
+	}
+	return list, nil
+}
+
+func (r *RoleRepository) Get(name string) (*models.Role, error) {
+	var role models.Role
+	if err := r.DB.Where("name = ?", name).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// Permissions returns a role's grants, or none for an unknown role.
+func (r *RoleRepository) Permissions(name string) ([]string, error) {
+	role, err := r.Get(name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return role.Permissions, nil
+}
+
+func (r *RoleRepository) Create(role *models.Role) error {
+	return r.DB.Create(role).Error
+}
+
+// Update stores a role's description and permissions.
+func (r *RoleRepository) Update(role *models.Role) error {
+	perms, err := json.Marshal(role.Permissions)
+	if err != nil {
+		return err
+	}
+	return r.DB.Model(role).Updates(map[string]interface{}{
+		"description": role.Description,
+		"permissions": string(perms),
+	}).Error
+}
+
+// Delete removes a custom role. Returns false if there's no such custom role.
+func (r *RoleRepository) Delete(name string) (bool, error) {
+	var deleted bool
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.User{}).Where("role = ?", name).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrRoleInUse
+		}
+		res := tx.Where("name = ? AND system = ?", name, false).Delete(&models.Role{})
+		deleted = res.RowsAffected > 0
+		return res.Error
+	})
+	return deleted, err
+}
+
+// EnsureDefaults creates the given roles if they don't exist yet. Existing roles,
+// including admins' edits to built-in ones, are left alone.
+func (r *RoleRepository) EnsureDefaults(roles []models.Role) error {
+	return r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&roles).Error
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrInvalidSession is returned for refresh tokens that are unknown, expired or revoked.
+	ErrInvalidSession = errors.New("invalid or expired session")
+	// ErrRefreshTokenReused is returned when an already rotated refresh token comes back.
+	// Someone else may hold a copy, so the session is revoked.
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+)
+
+// rotationGrace tolerates a rotated token arriving shortly after its replacement was issued,
+// e.g. two tabs refreshing at once. Within it the request fails without revoking the session.
+const rotationGrace = 30 * time.Second
+
+// SessionRepository stores signed-in devices and rotates their refresh tokens.
+type SessionRepository struct {
+	DB *gorm.DB
+}
+
+// Create starts a session and returns it with the raw refresh token to hand to the client.
+// twoFactor records that the user passed a second factor to sign in.
+func (r *SessionRepository) Create(userID uuid.UUID, userAgent, ip string, ttl time.Duration, twoFactor bool) (*models.Session, string, error) {
+	raw, err := newRawToken()
+	if err != nil {
+		return nil, "", err
+	}
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		UserID:           userID,
+		RefreshTokenHash: hashToken(raw),
+		UserAgent:        userAgent,
+		IP:               ip,
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(ttl),
+	}
+	if twoFactor {
+		session.TwoFactorAt = &now
+	}
+	err = r.DB.Transaction(func(tx *gorm.DB) error {
+		// Tidy up this user's dead sessions while we're here
+		if err := tx.Where("user_id = ? AND (expires_at < ? OR revoked_at < ?)", userID, now, now.AddDate(0, 0, -30)).
+			Delete(&models.Session{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(session).Error
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return session, raw, nil
+}
+
+// Rotate exchanges a refresh token for a new one and extends the session by ttl.
+// Presenting a token that was already rotated revokes the session (ErrRefreshTokenReused).
+func (r *SessionRepository) Rotate(raw string, ttl time.Duration) (*models.Session, string, error) {
+	next, err := newRawToken()
+	if err != nil {
+		return nil, "", err
+	}
+	hash := hashToken(raw)
+
+	var session models.Session
+	err = r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("refresh_token_hash = ?", hash).First(&session).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidSession
+		}
+		if err != nil {
+			return err
+		}
+		if !session.Active() {
+			return ErrInvalidSession
+		}
+
+		now := time.Now()
+		session.PreviousTokenHash = hash
+		session.RefreshTokenHash = hashToken(next)
+		session.LastUsedAt = now
+		session.ExpiresAt = now.Add(ttl)
+		return tx.Model(&session).Select("previous_token_hash", "refresh_token_hash", "last_used_at", "expires_at").
+			Updates(&session).Error
+	})
+	if errors.Is(err, ErrInvalidSession) && session.ID == uuid.Nil {
+		err = r.checkReuse(hash)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return &session, next, nil
+}
+
+// checkReuse handles a refresh token that isn't current. If it is the one a session
+// just rotated away from, the session is revoked unless the rotation was within the grace.
+func (r *SessionRepository) checkReuse(hash string) error {
+	var session models.Session
+	err := r.DB.Where("previous_token_hash = ? AND revoked_at IS NULL", hash).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidSession
+	}
+	if err != nil {
+		return err
+	}
+	if time.Since(session.LastUsedAt) < rotationGrace {
+		return ErrInvalidSession
+	}
+	if err := r.DB.Model(&session).Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// MarkTwoFactor records that the user passed a second factor during the session, e.g.
+// by setting up their authenticator app. Tokens refreshed from then on say so.
+func (r *SessionRepository) MarkTwoFactor(id uuid.UUID) error {
+	return r.DB.Model(&models.Session{}).Where("id = ? AND two_factor_at IS NULL", id).
+		Update("two_factor_at", time.Now()).Error
+}
+
+// IsActive reports whether the session an access token was issued for is still valid.
+func (r *SessionRepository) IsActive(id uuid.UUID) bool {
+	var count int64
+	r.DB.Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL AND expires_at > ?", id, time.Now()).
+		Count(&count)
+	return count > 0
+}
+
+// ListActive returns the user's live sessions, most recently used first.
+func (r *SessionRepository) ListActive(userID uuid.UUID) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at desc").Find(&sessions).Error
+	return sessions, err
+}
+
+// Revoke ends one of the user's sessions. Returns false if it wasn't found or already ended.
+func (r *SessionRepository) Revoke(userID uuid.UUID, id string) (bool, error) {
+	res := r.DB.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	return res.RowsAffected > 0, res.Error
+}
+
+// RevokeAll ends every session of the user and returns how many there were.
+func (r *SessionRepository) RevokeAll(userID uuid.UUID) (int64, error) {
+	res := r.DB.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/database/dbtest"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+)
+
+func newTestSessions(t *testing.T) *SessionRepository {
+	return &SessionRepository{DB: dbtest.Open(t, &models.Session{})}
+}
+
+func TestRotateIssuesNewToken(t *testing.T) {
+	r := newTestSessions(t)
+	session, raw, err := r.Create(uuid.New(), "test", "127.0.0.1", time.Hour, false)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	rotated, next, err := r.Rotate(raw, 2*time.Hour)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if rotated.ID != session.ID {
+		t.Fatalf("Rotate returned session %s, want %s", rotated.ID, session.ID)
+	}
+	if next == raw {
+		t.Fatal("Rotate returned the same refresh token")
+	}
+	if !rotated.ExpiresAt.After(session.ExpiresAt) {
+		t.Fatalf("expiry was not extended: %v -> %v", session.ExpiresAt, rotated.ExpiresAt)
+	}
+	if _, _, err := r.Rotate(next, time.Hour); err != nil {
+		t.Fatalf("rotating the new token: %v", err)
+	}
+}
+
+func TestRotateReuseWithinGraceKeepsSession(t *testing.T) {
+	r := newTestSessions(t)
+	session, raw, err := r.Create(uuid.New(), "test", "127.0.0.1", time.Hour, false)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	_, next, err := r.Rotate(raw, time.Hour)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	// A second tab refreshing with the old token right after the first one did
+	if _, _, err := r.Rotate(raw, time.Hour); err != ErrInvalidSession {
+		t.Fatalf("reuse within grace: err = %v, want ErrInvalidSession", err)
+	}
+	if !r.IsActive(session.ID) {
+		t.Fatal("reuse within the grace period revoked the session")
+	}
+	if _, _, err := r.Rotate(next, time.Hour); err != nil {
+		t.Fatalf("current token stopped working: %v", err)
+	}
+}
+
+func TestRotateReuseAfterGraceRevokesSession(t *testing.T) {
+	r := newTestSessions(t)
+	session, raw, err := r.Create(uuid.New(), "test", "127.0.0.1", time.Hour, false)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	_, next, err := r.Rotate(raw, time.Hour)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if err := r.DB.Model(&models.Session{}).Where("id = ?", session.ID).
+		Update("last_used_at", time.Now().Add(-rotationGrace-time.Second)).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := r.Rotate(raw, time.Hour); err != ErrRefreshTokenReused {
+		t.Fatalf("reuse after grace: err = %v, want ErrRefreshTokenReused", err)
+	}
+	if r.IsActive(session.ID) {
+		t.Fatal("session is still active after its old refresh token was reused")
+	}
+	if _, _, err := r.Rotate(next, time.Hour); err != ErrInvalidSession {
+		t.Fatalf("current token after revocation: err = %v, want ErrInvalidSession", err)
+	}
+}
+
+func TestRotateRejectsUnknownAndExpiredTokens(t *testing.T) {
+	r := newTestSessions(t)
+	if _, _, err := r.Rotate("not-a-token", time.Hour); err != ErrInvalidSession {
+		t.Fatalf("unknown token: err = %v, want ErrInvalidSession", err)
+	}
+
+	session, raw, err := r.Create(uuid.New(), "test", "127.0.0.1", time.Hour, false)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := r.DB.Model(&models.Session{}).Where("id = ?", session.ID).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.Rotate(raw, time.Hour); err != ErrInvalidSession {
+		t.Fatalf("expired session: err = %v, want ErrInvalidSession", err)
+	}
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/totp"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrTwoFactorNotSetUp is returned when the user has no authenticator app enrolled.
+	ErrTwoFactorNotSetUp = errors.New("two-factor authentication is not set up")
+	// ErrTwoFactorEnabled is returned when starting setup for a user who already finished it.
+	ErrTwoFactorEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrInvalidTwoFactorCode is returned for wrong, expired or replayed codes.
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+)
+
+// recoveryCodeCount is how many recovery codes a user gets at a time.
+const recoveryCodeCount = 10
+
+// TwoFactorRepository stores authenticator app enrollments and recovery codes.
+type TwoFactorRepository struct {
+	DB  *gorm.DB
+	Key []byte // Seals secrets at rest, see totp.KeyFromEnv
+}
+
+// Get returns the user's enrollment, finished or not.
+func (r *TwoFactorRepository) Get(userID uuid.UUID) (*models.TwoFactor, error) {
+	var tf models.TwoFactor
+	if err := r.DB.Where("user_id = ?", userID).First(&tf).Error; err != nil {
+		return nil, err
+	}
+	return &tf, nil
+}
+
+// Enabled reports whether the user signs in with a second factor.
+func (r *TwoFactorRepository) Enabled(userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.DB.Model(&models.TwoFactor{}).Where("user_id = ? AND enabled_at IS NOT NULL", userID).Count(&count).Error
+	return count > 0, err
+}
+
+// Begin starts setup with a new secret, replacing one from an unfinished setup.
+// It returns the secret for the user to add to their app.
+func (r *TwoFactorRepository) Begin(userID uuid.UUID) (string, error) {
+	if enabled, err := r.Enabled(userID); err != nil || enabled {
+		if err == nil {
+			err = ErrTwoFactorEnabled
+		}
+		return "", err
+	}
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return "", err
+	}
+	sealed, err := totp.Seal(r.Key, secret)
+	if err != nil {
+		return "", err
+	}
+	err = r.DB.Exec(`INSERT INTO user_two_factors (user_id, secret, last_step, created_at, updated_at)
+		VALUES (?, ?, 0, NOW(), NOW())
+		ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_step = 0, updated_at = NOW()
+		WHERE user_two_factors.enabled_at IS NULL`, userID, sealed).Error
+	return secret, err
+}
+
+// Enable finishes setup once the user enters a code from their app, and returns their
+// recovery codes. They are only ever shown this once.
+func (r *TwoFactorRepository) Enable(userID uuid.UUID, code string) ([]string, error) {
+	tf, err := r.Get(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTwoFactorNotSetUp
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tf.EnabledAt != nil {
+		return nil, ErrTwoFactorEnabled
+	}
+	if err := r.checkCode(tf, code); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err = r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.TwoFactor{}).Where("user_id = ?", userID).
+			Update("enabled_at", time.Now()).Error; err != nil {
+			return err
+		}
+		codes, err = replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	return codes, err
+}
+
+// Verify checks a code from the user's app or one of their recovery codes, which is
+// then used up. It reports whether a recovery code was used.
+func (r *TwoFactorRepository) Verify(userID uuid.UUID, code string) (recovery bool, err error) {
+	tf, err := r.Get(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && tf.EnabledAt == nil) {
+		return false, ErrTwoFactorNotSetUp
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := r.checkCode(tf, code); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		return false, err
+	}
+
+	res := r.DB.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashToken(normalizeRecoveryCode(code))).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return false, ErrInvalidTwoFactorCode
+	}
+	return true, nil
+}
+
+// checkCode accepts a code from the app at most once: each time step can only be used
+// once, so a code seen over the user's shoulder is worthless once they've used it.
+func (r *TwoFactorRepository) checkCode(tf *models.TwoFactor, code string) error {
+	secret, err := totp.Open(r.Key, tf.Secret)
+	if err != nil {
+		return err
+	}
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+	res := r.DB.Model(&models.TwoFactor{}).Where("user_id = ? AND last_step < ?", tf.UserID, step).
+		Update("last_step", step)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// Disable removes the user's enrollment and recovery codes.
+func (r *TwoFactorRepository) Disable(userID uuid.UUID) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.TwoFactor{}).Error
+	})
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes with a new set.
+func (r *TwoFactorRepository) RegenerateRecoveryCodes(userID uuid.UUID) ([]string, error) {
+	var codes []string
+	err := r.DB.Transaction(func(tx *gorm.DB) (err error) {
+		codes, err = replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	return codes, err
+}
+
+// RecoveryCodesLeft counts the user's unused recovery codes.
+func (r *TwoFactorRepository) RecoveryCodesLeft(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.DB.Model(&models.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uuid.UUID) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+	codes := make([]string, recoveryCodeCount)
+	rows := make([]models.RecoveryCode, recoveryCodeCount)
+	for i := range codes {
+		buf := make([]byte, 10)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(buf))[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+		rows[i] = models.RecoveryCode{UserID: userID, CodeHash: hashToken(raw)}
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode ignores case, spaces and the dash, however the user typed it.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
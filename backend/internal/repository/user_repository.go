@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+)
+
+// UserRepository handles account records.
+type UserRepository struct {
+	DB *gorm.DB
+}
+
+func (r *UserRepository) GetByID(id uuid.UUID) (*models.User, error) {
+	var user models.User
+	if err := r.DB.Where("id = ?", id).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetByEmail looks a user up by email, ignoring case.
+func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := r.DB.Where("LOWER(email) = LOWER(?)", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// IsEmailVerified reports whether the user has verified their email address.
+func (r *UserRepository) IsEmailVerified(userID interface{}) bool {
+	var user models.User
+	if err := r.DB.Select("id", "email_verified_at").Where("id = ?", userID).First(&user).Error; err != nil {
+		return false
+	}
+	return user.IsVerified()
+}
+
+// MarkEmailVerified records that the user proved they own their address. Idempotent.
+func (r *UserRepository) MarkEmailVerified(userID uuid.UUID) error {
+	return r.DB.Model(&models.User{}).Where("id = ? AND email_verified_at IS NULL", userID).
+		Update("email_verified_at", time.Now()).Error
+}
+
+// Create adds a user.
+func (r *UserRepository) Create(user *models.User) error {
+	return r.DB.Create(user).Error
+}
+
+// ClaimUnverified hands an account whose email was never verified to whoever has just
+// proved they own that address through an identity provider. The account is marked
+// verified and its password is cleared, since nobody proved they owned the address when
+// it was set; tokens issued before stop working.
+func (r *UserRepository) ClaimUnverified(userID uuid.UUID) error {
+	err := r.DB.Exec(`UPDATE users SET email_verified_at = NOW(), password = '', token_version = token_version + 1,
+		updated_at = NOW() WHERE id = ? AND email_verified_at IS NULL`, userID).Error
+	authStates.Delete(userID)
+	return err
+}
+
+// UpdatePassword stores a new password hash.
+func (r *UserRepository) UpdatePassword(userID uuid.UUID, hash string) error {
+	return r.DB.Model(&models.User{}).Where("id = ?", userID).Update("password", hash).Error
+}
+
+// AuthState is what authorization needs to know about a user on every request.
+type AuthState struct {
+	Role         string
+	TokenVersion int
+}
+
+// authStateTTL bounds how long a role change can take to reach other API instances.
+// This instance forgets a user's state as soon as it changes it.
+const authStateTTL = 30 * time.Second
+
+type cachedAuthState struct {
+	state   AuthState
+	expires time.Time
+}
+
+// authStates is shared by every UserRepository in the process, so a change made
+// through one is seen by the middleware using another.
+var authStates sync.Map // uuid.UUID -> cachedAuthState
+
+// AuthState returns the user's current role and token version, cached briefly.
+func (r *UserRepository) AuthState(userID uuid.UUID) (*AuthState, error) {
+	if v, ok := authStates.Load(userID); ok {
+		if cached := v.(cachedAuthState); time.Now().Before(cached.expires) {
+			return &cached.state, nil
+		}
+	}
+
+	var user models.User
+	if err := r.DB.Select("id", "role", "token_version").Where("id = ?", userID).First(&user).Error; err != nil {
+		return nil, err
+	}
+	state := AuthState{Role: user.Role, TokenVersion: user.TokenVersion}
+	authStates.Store(userID, cachedAuthState{state: state, expires: time.Now().Add(authStateTTL)})
+	return &state, nil
+}
+
+// ChangeRole gives the user a new role and bumps their token version, so tokens
+// carrying the old role stop working.
+func (r *UserRepository) ChangeRole(userID uuid.UUID, role string) (*models.User, error) {
+	var user models.User
+	res := r.DB.Raw(`UPDATE users SET role = ?, token_version = token_version + 1, updated_at = NOW()
+		WHERE id = ? RETURNING *`, role, userID).Scan(&user)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	authStates.Delete(userID)
+	return &user, nil
+}
+
+// ForgetAuthState drops the user's cached auth state, e.g. once a transaction that
+// changed their role has committed.
+func (r *UserRepository) ForgetAuthState(userID uuid.UUID) {
+	authStates.Delete(userID)
+}
+
+// PromoteToOwner makes a viewer an owner once they register or join a business. Staff keep
+// their role. Tokens stay valid: the middleware picks the new role up from the database.
+func (r *UserRepository) PromoteToOwner(ctx context.Context, userID uuid.UUID) error {
+	err := r.DB.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND role = ?", userID, "viewer").
+		Update("role", "owner").Error
+	authStates.Delete(userID)
+	return err
+}
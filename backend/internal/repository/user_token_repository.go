@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidUserToken is returned for tokens that are unknown, expired or already used.
+var ErrInvalidUserToken = errors.New("invalid or expired token")
+
+// UserTokenRepository issues and redeems single-use tokens for account flows.
+type UserTokenRepository struct {
+	DB *gorm.DB
+}
+
+// Issue creates a token for the user and returns the raw value to email them.
+// Earlier unused tokens for the same purpose stop working, so only the latest link is valid.
+func (r *UserTokenRepository) Issue(userID uuid.UUID, purpose string, ttl time.Duration) (string, error) {
+	raw, err := newRawToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+			Delete(&models.UserToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.UserToken{
+			UserID:    userID,
+			Purpose:   purpose,
+			TokenHash: hashToken(raw),
+			ExpiresAt: time.Now().Add(ttl),
+		}).Error
+	})
+	return raw, err
+}
+
+// Consume redeems a token, marking it used. It can only succeed once per token.
+func (r *UserTokenRepository) Consume(raw, purpose string) (*models.UserToken, error) {
+	var token models.UserToken
+	res := r.DB.Raw(`UPDATE user_tokens SET used_at = NOW()
+		WHERE token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > NOW()
+		RETURNING *`, hashToken(raw), purpose).Scan(&token)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrInvalidUserToken
+	}
+	return &token, nil
+}
+
+// Peek returns a token that can still be used, without using it up. For flows where the
+// user may get the next step wrong and retry; see RecordFailure.
+func (r *UserTokenRepository) Peek(raw, purpose string) (*models.UserToken, error) {
+	var token models.UserToken
+	err := r.DB.Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > NOW()", hashToken(raw), purpose).
+		First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidUserToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RecordFailure counts a wrong answer against a token. After max of them the token is
+// used up, so it can't be used to guess.
+func (r *UserTokenRepository) RecordFailure(id uuid.UUID, max int) error {
+	return r.DB.Exec(`UPDATE user_tokens SET attempts = attempts + 1,
+		used_at = CASE WHEN attempts + 1 >= ? THEN NOW() ELSE used_at END
+		WHERE id = ?`, max, id).Error
+}
+
+// newRawToken returns 32 random bytes, URL-safe encoded.
+func newRawToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/database/dbtest"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+)
+
+func newTestTokens(t *testing.T) *UserTokenRepository {
+	return &UserTokenRepository{DB: dbtest.Open(t, &models.UserToken{})}
+}
+
+func TestConsumeWorksOnce(t *testing.T) {
+	r := newTestTokens(t)
+	userID := uuid.New()
+
+	raw, err := r.Issue(userID, models.TokenPurposePasswordReset, time.Hour)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	token, err := r.Consume(raw, models.TokenPurposePasswordReset)
+	if err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+	if token.UserID != userID || token.UsedAt == nil {
+		t.Fatalf("consumed token = %+v", token)
+	}
+	if _, err := r.Consume(raw, models.TokenPurposePasswordReset); err != ErrInvalidUserToken {
+		t.Fatalf("second Consume err = %v, want ErrInvalidUserToken", err)
+	}
+}
+
+func TestConsumeChecksPurpose(t *testing.T) {
+	r := newTestTokens(t)
+
+	raw, err := r.Issue(uuid.New(), models.TokenPurposeEmailVerification, time.Hour)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if _, err := r.Consume(raw, models.TokenPurposePasswordReset); err != ErrInvalidUserToken {
+		t.Fatalf("err = %v, want ErrInvalidUserToken", err)
+	}
+	// Trying it for the wrong purpose must not use it up
+	if _, err := r.Consume(raw, models.TokenPurposeEmailVerification); err != nil {
+		t.Fatalf("Consume for the right purpose: %v", err)
+	}
+}
+
+func TestConsumeRejectsExpiredAndUnknownTokens(t *testing.T) {
+	r := newTestTokens(t)
+
+	raw, err := newRawToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.DB.Create(&models.UserToken{
+		UserID:    uuid.New(),
+		Purpose:   models.TokenPurposePasswordReset,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Consume(raw, models.TokenPurposePasswordReset); err != ErrInvalidUserToken {
+		t.Fatalf("expired token: err = %v, want ErrInvalidUserToken", err)
+	}
+	if _, err := r.Consume("not-a-token", models.TokenPurposePasswordReset); err != ErrInvalidUserToken {
+		t.Fatalf("unknown token: err = %v, want ErrInvalidUserToken", err)
+	}
+}
+
+func TestIssueReplacesEarlierTokens(t *testing.T) {
+	r := newTestTokens(t)
+	userID := uuid.New()
+
+	first, err := r.Issue(userID, models.TokenPurposePasswordReset, time.Hour)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	second, err := r.Issue(userID, models.TokenPurposePasswordReset, time.Hour)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	if _, err := r.Consume(first, models.TokenPurposePasswordReset); err != ErrInvalidUserToken {
+		t.Fatalf("earlier token: err = %v, want ErrInvalidUserToken", err)
+	}
+	if _, err := r.Consume(second, models.TokenPurposePasswordReset); err != nil {
+		t.Fatalf("latest token: %v", err)
+	}
+}
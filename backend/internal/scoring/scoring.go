@@ -0,0 +1,184 @@
+// Package scoring computes a business's health score: a 0-100 measure of how much
+// real engagement a profile is getting. It is pure calculation; the repository layer
+// gathers the raw signals from the database and stores the result.
+//
+// Each signal (likes, comments, views, inquiries, meetings) is counted with time decay,
+// so an interaction from today is worth more than one from six months ago. The weighted
+// sum is then squashed into 0-100 so a handful of very active profiles can't run off
+// the top of the scale.
+package scoring
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Weights is how many raw points one (fresh) interaction of each kind is worth.
+type Weights struct {
+	Like    float64 `json:"like"`
+	Comment float64 `json:"comment"`
+	View    float64 `json:"view"`
+	Inquiry float64 `json:"inquiry"`
+	Meeting float64 `json:"meeting"`
+}
+
+// Config tunes the engine.
+type Config struct {
+	Weights Weights
+
+	// HalfLife is how long it takes an interaction to lose half its value.
+	// Zero disables decay.
+	HalfLife time.Duration
+
+	// Saturation is the raw score that maps to ~63 on the 0-100 scale
+	// (score = 100 * (1 - e^(-raw/Saturation))). Larger values make 100 harder to reach.
+	Saturation float64
+}
+
+// DefaultConfig keeps comments worth more than likes (as the original formula did) and
+// adds lighter-weight views and heavier-weight inquiries and meetings.
+func DefaultConfig() Config {
+	return Config{
+		Weights: Weights{
+			Like:    2,
+			Comment: 5,
+			View:    0.1,
+			Inquiry: 8,
+			Meeting: 12,
+		},
+		HalfLife:   30 * 24 * time.Hour,
+		Saturation: 60,
+	}
+}
+
+// ConfigFromEnv starts from DefaultConfig and applies any overrides:
+// HEALTH_WEIGHT_LIKE, HEALTH_WEIGHT_COMMENT, HEALTH_WEIGHT_VIEW, HEALTH_WEIGHT_INQUIRY,
+// HEALTH_WEIGHT_MEETING, HEALTH_HALF_LIFE_DAYS and HEALTH_SATURATION.
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+	envFloat("HEALTH_WEIGHT_LIKE", &cfg.Weights.Like)
+	envFloat("HEALTH_WEIGHT_COMMENT", &cfg.Weights.Comment)
+	envFloat("HEALTH_WEIGHT_VIEW", &cfg.Weights.View)
+	envFloat("HEALTH_WEIGHT_INQUIRY", &cfg.Weights.Inquiry)
+	envFloat("HEALTH_WEIGHT_MEETING", &cfg.Weights.Meeting)
+
+	var days float64 = -1
+	envFloat("HEALTH_HALF_LIFE_DAYS", &days)
+	if days >= 0 {
+		cfg.HalfLife = time.Duration(days * float64(24*time.Hour))
+	}
+
+	envFloat("HEALTH_SATURATION", &cfg.Saturation)
+	if cfg.Saturation <= 0 {
+		cfg.Saturation = DefaultConfig().Saturation
+	}
+	return cfg
+}
+
+func envFloat(key string, dst *float64) {
+	if v, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		*dst = v
+	}
+}
+
+// Signal is one kind of interaction: the plain count and the time-decayed count
+// (sum of 0.5^(age/HalfLife) over every interaction).
+type Signal struct {
+	Count   int64   `json:"count"`
+	Decayed float64 `json:"decayed"`
+}
+
+// Signals is everything the engine needs to score one business.
+type Signals struct {
+	Likes     Signal
+	Comments  Signal
+	Views     Signal
+	Inquiries Signal
+	Meetings  Signal
+}
+
+// Component explains one signal's contribution to the final score.
+type Component struct {
+	Name    string  `json:"name"`
+	Count   int64   `json:"count"`
+	Decayed float64 `json:"decayed"`
+	Weight  float64 `json:"weight"`
+	Points  float64 `json:"points"` // Decayed * Weight
+	Share   float64 `json:"share"`  // Fraction of the raw score (0-1)
+}
+
+// Breakdown is a score together with how it was reached.
+type Breakdown struct {
+	Score        int         `json:"score"` // 0-100
+	Raw          float64     `json:"raw"`   // Weighted sum before normalization
+	Components   []Component `json:"components"`
+	HalfLifeDays float64     `json:"half_life_days"`
+	Saturation   float64     `json:"saturation"`
+	CalculatedAt time.Time   `json:"calculated_at"`
+}
+
+// Engine turns signals into scores using a Config.
+type Engine struct {
+	Config Config
+}
+
+// NewEngine creates an Engine.
+func NewEngine(cfg Config) *Engine {
+	return &Engine{Config: cfg}
+}
+
+// DecayTau returns the exponential time constant (in seconds) matching the half-life,
+// i.e. weight = e^(-age/tau). It returns 0 when decay is disabled.
+// Repositories use it to compute Signal.Decayed in SQL.
+func (e *Engine) DecayTau() float64 {
+	if e.Config.HalfLife <= 0 {
+		return 0
+	}
+	return e.Config.HalfLife.Seconds() / math.Ln2
+}
+
+// Score computes the 0-100 health score with a per-component breakdown.
+func (e *Engine) Score(s Signals) Breakdown {
+	w := e.Config.Weights
+	components := []Component{
+		{Name: "likes", Count: s.Likes.Count, Decayed: s.Likes.Decayed, Weight: w.Like},
+		{Name: "comments", Count: s.Comments.Count, Decayed: s.Comments.Decayed, Weight: w.Comment},
+		{Name: "views", Count: s.Views.Count, Decayed: s.Views.Decayed, Weight: w.View},
+		{Name: "inquiries", Count: s.Inquiries.Count, Decayed: s.Inquiries.Decayed, Weight: w.Inquiry},
+		{Name: "meetings", Count: s.Meetings.Count, Decayed: s.Meetings.Decayed, Weight: w.Meeting},
+	}
+
+	raw := 0.0
+	for i := range components {
+		components[i].Points = components[i].Decayed * components[i].Weight
+		raw += components[i].Points
+	}
+	if raw > 0 {
+		for i := range components {
+			components[i].Share = components[i].Points / raw
+		}
+	}
+
+	return Breakdown{
+		Score:        e.Normalize(raw),
+		Raw:          raw,
+		Components:   components,
+		HalfLifeDays: e.Config.HalfLife.Hours() / 24,
+		Saturation:   e.Config.Saturation,
+		CalculatedAt: time.Now(),
+	}
+}
+
+// Normalize maps a raw weighted sum onto 0-100.
+func (e *Engine) Normalize(raw float64) int {
+	if raw <= 0 {
+		return 0
+	}
+	score := int(math.Round(100 * (1 - math.Exp(-raw/e.Config.Saturation))))
+	if score > 100 {
+		score = 100
+	}
+	return score
+}
@@ -0,0 +1,70 @@
+package spam
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// ErrCaptchaFailed is returned when a captcha token is missing or rejected by the provider.
+var ErrCaptchaFailed = errors.New("captcha verification failed")
+
+// Verifier is the hook public forms call before accepting a submission.
+// Implementations can wrap reCAPTCHA, hCaptcha, Turnstile or a proof-of-work check.
+type Verifier interface {
+	Verify(token, remoteIP string) error
+}
+
+// SiteVerifier talks to any provider exposing the common "siteverify" API
+// (POST secret, response, remoteip -> {"success": bool}). reCAPTCHA, hCaptcha and
+// Cloudflare Turnstile all follow this shape.
+type SiteVerifier struct {
+	URL    string
+	Secret string
+	Client *http.Client
+}
+
+// NewVerifierFromEnv returns a SiteVerifier when CAPTCHA_VERIFY_URL and CAPTCHA_SECRET are set,
+// or nil when captcha checks are disabled.
+func NewVerifierFromEnv() Verifier {
+	verifyURL, secret := os.Getenv("CAPTCHA_VERIFY_URL"), os.Getenv("CAPTCHA_SECRET")
+	if verifyURL == "" || secret == "" {
+		return nil
+	}
+	return &SiteVerifier{
+		URL:    verifyURL,
+		Secret: secret,
+		Client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Verify checks the token with the provider.
+func (v *SiteVerifier) Verify(token, remoteIP string) error {
+	if token == "" {
+		return ErrCaptchaFailed
+	}
+
+	resp, err := v.Client.PostForm(v.URL, url.Values{
+		"secret":   {v.Secret},
+		"response": {token},
+		"remoteip": {remoteIP},
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+	if !body.Success {
+		return ErrCaptchaFailed
+	}
+	return nil
+}
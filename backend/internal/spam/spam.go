@@ -0,0 +1,127 @@
+// Package spam scores user-submitted text (comments, inquiries, contact forms)
+// so obvious abuse can be held back for review instead of reaching business owners.
+package spam
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultThreshold is the score at or above which content is quarantined.
+const DefaultThreshold = 0.7
+
+// defaultBlocklist holds terms that almost never appear in genuine messages on the platform.
+var defaultBlocklist = []string{
+	"viagra", "casino", "crypto giveaway", "forex signals", "loan offer",
+	"work from home", "click here", "free money", "porn", "escort",
+}
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+`)
+
+// Result explains how a piece of content was scored.
+type Result struct {
+	Score   float64  `json:"score"`   // 0 (clean) to 1 (certainly spam)
+	Reasons []string `json:"reasons"` // Human readable signals that contributed to the score
+}
+
+// Analyzer applies simple content heuristics. It is safe for concurrent use.
+type Analyzer struct {
+	Threshold float64
+	Blocklist []string
+}
+
+// NewAnalyzerFromEnv builds an Analyzer using SPAM_THRESHOLD and SPAM_BLOCKLIST (comma-separated)
+// on top of the built-in defaults.
+func NewAnalyzerFromEnv() *Analyzer {
+	// Copied so extra terms never end up in the shared defaults
+	a := &Analyzer{Threshold: DefaultThreshold, Blocklist: append([]string(nil), defaultBlocklist...)}
+
+	if v, err := strconv.ParseFloat(os.Getenv("SPAM_THRESHOLD"), 64); err == nil && v > 0 {
+		a.Threshold = v
+	}
+	for _, term := range strings.Split(os.Getenv("SPAM_BLOCKLIST"), ",") {
+		if term = strings.TrimSpace(strings.ToLower(term)); term != "" {
+			a.Blocklist = append(a.Blocklist, term)
+		}
+	}
+	return a
+}
+
+// Check scores the combined fields of a submission.
+func (a *Analyzer) Check(fields ...string) Result {
+	text := strings.TrimSpace(strings.Join(fields, "\n"))
+	var res Result
+	if text == "" {
+		return res
+	}
+
+	lower := strings.ToLower(text)
+	words := strings.Fields(lower)
+
+	// 1. Link density: a couple of links is normal, a wall of links is not.
+	links := len(linkPattern.FindAllString(text, -1))
+	if links > 0 && len(words) > 0 {
+		density := float64(links) / float64(len(words))
+		switch {
+		case links >= 4 || density > 0.3:
+			res.add(0.5, "high link density")
+		case links >= 2:
+			res.add(0.2, "multiple links")
+		}
+	}
+
+	// 2. Repeated text: bots tend to paste the same token or phrase over and over.
+	if len(words) >= 8 {
+		counts := make(map[string]int, len(words))
+		top := 0
+		for _, w := range words {
+			counts[w]++
+			if counts[w] > top {
+				top = counts[w]
+			}
+		}
+		if unique := float64(len(counts)) / float64(len(words)); unique < 0.3 {
+			res.add(0.4, "repetitive text")
+		} else if float64(top)/float64(len(words)) > 0.25 && top >= 5 {
+			res.add(0.2, "repeated word")
+		}
+	}
+
+	// 3. Blocklisted terms.
+	for _, term := range a.Blocklist {
+		if strings.Contains(lower, term) {
+			res.add(0.5, "blocklisted term: "+term)
+		}
+	}
+
+	// 4. Shouting.
+	letters, upper := 0, 0
+	for _, r := range text {
+		if r >= 'a' && r <= 'z' {
+			letters++
+		} else if r >= 'A' && r <= 'Z' {
+			letters++
+			upper++
+		}
+	}
+	if letters >= 20 && float64(upper)/float64(letters) > 0.7 {
+		res.add(0.2, "excessive capitals")
+	}
+
+	return res
+}
+
+// IsSpam reports whether a result crosses the analyzer's quarantine threshold.
+func (a *Analyzer) IsSpam(res Result) bool {
+	return res.Score >= a.Threshold
+}
+
+func (r *Result) add(weight float64, reason string) {
+	r.Score += weight
+	if r.Score > 1 {
+		r.Score = 1
+	}
+	r.Reasons = append(r.Reasons, reason)
+}
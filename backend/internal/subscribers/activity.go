@@ -0,0 +1,38 @@
+// Package subscribers holds the domain event subscribers that used to be inlined in controllers.
+package subscribers
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/events"
+	"github.com/saidimuKennedy/spotlight-africa/internal/jobs"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+)
+
+// Activity records views, conversions and inquiries on the business activity feed.
+type Activity struct {
+	Jobs *jobs.Queue
+}
+
+// Register subscribes to the events that produce activity entries.
+func (s *Activity) Register(bus *events.Bus) {
+	events.On(bus, func(ctx context.Context, e events.BusinessViewed) error {
+		return s.track(e.BusinessID, e.UserID, models.ActivityTypeView)
+	})
+	events.On(bus, func(ctx context.Context, e events.ConversionTracked) error {
+		return s.track(e.BusinessID, e.UserID, models.ActivityTypeConversion)
+	})
+	events.On(bus, func(ctx context.Context, e events.InquirySubmitted) error {
+		return s.track(e.BusinessID, &e.UserID, models.ActivityTypeInquiry)
+	})
+}
+
+func (s *Activity) track(bizID uuid.UUID, userID *uuid.UUID, activityType models.ActivityType) error {
+	return s.Jobs.TrackActivity(&models.Activity{
+		UserID:     userID,
+		EntityID:   bizID,
+		EntityType: "business",
+		Type:       activityType,
+	})
+}
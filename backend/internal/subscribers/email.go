@@ -0,0 +1,90 @@
+package subscribers
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/events"
+	"github.com/saidimuKennedy/spotlight-africa/internal/mail"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/newsletter"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Emails sends the transactional mail that confirms a user's own actions: welcome
+// emails and receipts for inquiries and meetings. Mail about other people's actions
+// goes through Notifications and the recipient's channel preferences instead.
+type Emails struct {
+	DB          *gorm.DB
+	Outbox      *mail.Outbox
+	Preferences *repository.NotificationPreferenceRepository
+}
+
+// Register subscribes to the events that trigger an email.
+func (s *Emails) Register(bus *events.Bus) {
+	events.On(bus, s.userRegistered)
+	events.On(bus, s.newsletterSubscribed)
+	events.On(bus, s.inquirySubmitted)
+	events.On(bus, s.meetingBooked)
+}
+
+func (s *Emails) userRegistered(ctx context.Context, e events.UserRegistered) error {
+	return s.Outbox.Queue(s.DB.WithContext(ctx), e.Email, "welcome", e.Locale, map[string]interface{}{
+		"Name": (&models.User{Name: e.Name, Email: e.Email}).DisplayName(),
+	})
+}
+
+func (s *Emails) newsletterSubscribed(ctx context.Context, e events.NewsletterSubscribed) error {
+	return s.Outbox.QueueWithHeaders(s.DB.WithContext(ctx), e.Email, "newsletter_welcome", e.Locale, map[string]interface{}{
+		"Email":     e.Email,
+		"ManageURL": newsletter.ManageURL(e.Email),
+	}, newsletter.Headers(e.Email))
+}
+
+// inquirySubmitted confirms to the sender that their inquiry reached the business.
+func (s *Emails) inquirySubmitted(ctx context.Context, e events.InquirySubmitted) error {
+	var inquiry models.Inquiry
+	if err := s.DB.WithContext(ctx).Preload("User").Preload("Business").
+		Where("id = ?", e.InquiryID).First(&inquiry).Error; err != nil {
+		return err
+	}
+
+	return s.Outbox.Queue(s.DB.WithContext(ctx), inquiry.User.Email, "inquiry_received", inquiry.User.Locale, map[string]interface{}{
+		"Name":         inquiry.User.DisplayName(),
+		"BusinessName": inquiry.Business.Name,
+		"Subject":      inquiry.Subject,
+		"Message":      inquiry.Message,
+	})
+}
+
+// meetingBooked sends the booker a confirmation with the time in their own timezone.
+func (s *Emails) meetingBooked(ctx context.Context, e events.MeetingBooked) error {
+	var meeting models.Meeting
+	if err := s.DB.WithContext(ctx).Preload("User").Preload("Business").
+		Where("id = ?", e.MeetingID).First(&meeting).Error; err != nil {
+		return err
+	}
+
+	return s.Outbox.Queue(s.DB.WithContext(ctx), meeting.User.Email, "meeting_booked", meeting.User.Locale, map[string]interface{}{
+		"Name":         meeting.User.DisplayName(),
+		"BusinessName": meeting.Business.Name,
+		"Title":        meeting.Title,
+		"When":         meeting.StartTime.In(s.location(meeting.UserID)).Format("Mon 2 Jan 2006, 15:04 MST"),
+		"MeetingLink":  meeting.MeetingLink,
+	})
+}
+
+// location is the user's timezone from their notification settings, or UTC.
+func (s *Emails) location(userID uuid.UUID) *time.Location {
+	settings, err := s.Preferences.GetSettings(userID)
+	if err != nil {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
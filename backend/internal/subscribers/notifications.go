@@ -0,0 +1,202 @@
+package subscribers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/authz"
+	"github.com/saidimuKennedy/spotlight-africa/internal/events"
+	"github.com/saidimuKennedy/spotlight-africa/internal/jobs"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"gorm.io/gorm"
+)
+
+// Notifications turns domain events into in-app notifications for the people they concern.
+// High-volume events (likes, comments) are grouped per business per day.
+type Notifications struct {
+	DB   *gorm.DB
+	Jobs *jobs.Queue
+}
+
+// Register subscribes to every user-facing event.
+func (s *Notifications) Register(bus *events.Bus) {
+	events.On(bus, s.inquirySubmitted)
+	events.On(bus, s.inquiryStatusChanged)
+	events.On(bus, s.commentAdded)
+	events.On(bus, s.likeAdded)
+	events.On(bus, s.meetingBooked)
+	events.On(bus, s.meetingStatusChanged)
+}
+
+func (s *Notifications) inquirySubmitted(ctx context.Context, e events.InquirySubmitted) error {
+	biz, err := s.business(e.BusinessID)
+	if err != nil {
+		return err
+	}
+	return s.notifyTeam(biz, authz.InquiryManage, &e.UserID, models.Notification{
+		Type:    models.NotificationTypeInquiry,
+		Title:   "New inquiry",
+		Message: fmt.Sprintf("%s sent an inquiry: %s", s.userName(e.UserID), e.Subject),
+		Link:    "/dashboard/inquiries",
+	}, "")
+}
+
+func (s *Notifications) inquiryStatusChanged(ctx context.Context, e events.InquiryStatusChanged) error {
+	biz, err := s.business(e.BusinessID)
+	if err != nil {
+		return err
+	}
+	return s.notify(e.UserID, &e.ChangedBy, models.Notification{
+		Type:    models.NotificationTypeInquiryStatus,
+		Title:   "Inquiry update",
+		Message: fmt.Sprintf("Your inquiry to %s is now %s", biz.Name, strings.ReplaceAll(e.Status, "_", " ")),
+		Link:    "/business/" + biz.ID.String(),
+	}, "")
+}
+
+func (s *Notifications) commentAdded(ctx context.Context, e events.CommentAdded) error {
+	biz, err := s.business(e.BusinessID)
+	if err != nil {
+		return err
+	}
+	link := "/business/" + biz.ID.String()
+	name := s.userName(e.UserID)
+
+	// Replies notify the author of the parent comment
+	if e.ParentID != nil {
+		var parent models.Comment
+		if err := s.DB.Select("user_id").Where("id = ?", *e.ParentID).First(&parent).Error; err == nil {
+			if err := s.notify(parent.UserID, &e.UserID, models.Notification{
+				Type:     models.NotificationTypeComment,
+				Title:    "New reply",
+				Message:  fmt.Sprintf("%s replied to your comment on %s", name, biz.Name),
+				Link:     link,
+				GroupKey: "reply:" + e.ParentID.String(),
+			}, "%d people replied to your comment on "+escape(biz.Name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.notifyTeam(biz, authz.CommentModerate, &e.UserID, models.Notification{
+		Type:     models.NotificationTypeComment,
+		Title:    "New comment",
+		Message:  fmt.Sprintf("%s commented on %s", name, biz.Name),
+		Link:     link,
+		GroupKey: daily("comment", biz.ID),
+	}, "%d people commented on "+escape(biz.Name)+" today")
+}
+
+func (s *Notifications) likeAdded(ctx context.Context, e events.LikeAdded) error {
+	biz, err := s.business(e.BusinessID)
+	if err != nil {
+		return err
+	}
+	return s.notify(biz.OwnerID, &e.UserID, models.Notification{
+		Type:     models.NotificationTypeLike,
+		Title:    "New reaction",
+		Message:  fmt.Sprintf("%s reacted to %s", s.userName(e.UserID), biz.Name),
+		Link:     "/dashboard",
+		GroupKey: daily("like", biz.ID),
+	}, "%d people liked your business today")
+}
+
+func (s *Notifications) meetingBooked(ctx context.Context, e events.MeetingBooked) error {
+	biz, err := s.business(e.BusinessID)
+	if err != nil {
+		return err
+	}
+	return s.notifyTeam(biz, authz.MeetingManage, &e.UserID, models.Notification{
+		Type:    models.NotificationTypeMeeting,
+		Title:   "Meeting booked",
+		Message: fmt.Sprintf("%s booked \"%s\" for %s", s.userName(e.UserID), e.Title, e.StartTime.UTC().Format("Mon 2 Jan, 15:04 MST")),
+		Link:    "/dashboard",
+	}, "")
+}
+
+// meetingStatusChanged tells the other side of the meeting what happened.
+func (s *Notifications) meetingStatusChanged(ctx context.Context, e events.MeetingStatusChanged) error {
+	biz, err := s.business(e.BusinessID)
+	if err != nil {
+		return err
+	}
+
+	n := models.Notification{
+		Type:    models.NotificationTypeMeeting,
+		Title:   "Meeting " + e.Status,
+		Message: fmt.Sprintf("Your meeting with %s was marked %s", biz.Name, e.Status),
+		Link:    "/business/" + biz.ID.String(),
+	}
+	if e.ChangedBy == e.UserID {
+		n.Link = "/dashboard"
+		return s.notifyTeam(biz, authz.MeetingManage, &e.ChangedBy, n, "")
+	}
+	return s.notify(e.UserID, &e.ChangedBy, n, "")
+}
+
+// notify queues a notification for recipient. People are never notified about their own actions.
+func (s *Notifications) notify(recipient uuid.UUID, actor *uuid.UUID, n models.Notification, groupMessage string) error {
+	if actor != nil && *actor == recipient {
+		return nil
+	}
+	n.UserID = recipient
+	return s.Jobs.Notify(jobs.Notification{Notification: n, ActorID: actor, GroupMessage: groupMessage})
+}
+
+// notifyTeam queues a notification for every team member whose role includes perm.
+func (s *Notifications) notifyTeam(biz *models.Business, perm authz.Permission, actor *uuid.UUID, n models.Notification, groupMessage string) error {
+	team, err := teamWith(s.DB, biz, perm)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, userID := range team {
+		errs = append(errs, s.notify(userID, actor, n, groupMessage))
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Notifications) business(id uuid.UUID) (*models.Business, error) {
+	var biz models.Business
+	err := s.DB.Select("id, name, owner_id").Where("id = ?", id).First(&biz).Error
+	return &biz, err
+}
+
+func (s *Notifications) userName(id uuid.UUID) string {
+	var user models.User
+	if err := s.DB.Select("name").Where("id = ?", id).First(&user).Error; err != nil || user.Name == "" {
+		return "Someone"
+	}
+	return user.Name
+}
+
+// teamWith returns the members of the business whose team role includes perm. The owner
+// is always among them.
+func teamWith(db *gorm.DB, biz *models.Business, perm authz.Permission) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := db.Model(&models.BusinessMember{}).
+		Where("business_id = ? AND role IN ?", biz.ID, authz.MemberRolesWith(perm)).
+		Pluck("user_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(ids, biz.OwnerID) {
+		ids = append(ids, biz.OwnerID)
+	}
+	return ids, nil
+}
+
+// daily builds a group key that rolls over at midnight UTC.
+func daily(kind string, bizID uuid.UUID) string {
+	return fmt.Sprintf("%s:%s:%s", kind, bizID, time.Now().UTC().Format("2006-01-02"))
+}
+
+// escape makes a value safe to embed in a fmt format string.
+func escape(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
+}
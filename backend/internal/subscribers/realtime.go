@@ -0,0 +1,56 @@
+package subscribers
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/authz"
+	"github.com/saidimuKennedy/spotlight-africa/internal/events"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/realtime"
+	"gorm.io/gorm"
+)
+
+// Realtime pushes chat messages and inquiry updates to connected clients.
+// (Notifications are pushed by the job that stores them.)
+type Realtime struct {
+	DB  *gorm.DB
+	Hub *realtime.Hub
+}
+
+// Register subscribes to the events clients want live.
+func (s *Realtime) Register(bus *events.Bus) {
+	events.On(bus, func(ctx context.Context, e events.ChatMessagePosted) error {
+		var msg models.ChatMessage
+		if err := s.DB.Preload("User").Where("id = ?", e.MessageID).First(&msg).Error; err != nil {
+			return err
+		}
+		s.Hub.Publish(ctx, realtime.TypeChat, nil, msg)
+		return nil
+	})
+
+	// Inquiry updates go to both sides: the team members who handle inquiries and the
+	// person who asked.
+	events.On(bus, func(ctx context.Context, e events.InquirySubmitted) error {
+		return s.toInquiryTeam(ctx, e.BusinessID, e)
+	})
+	events.On(bus, func(ctx context.Context, e events.InquiryStatusChanged) error {
+		s.Hub.Publish(ctx, realtime.TypeInquiry, &e.UserID, e)
+		return s.toInquiryTeam(ctx, e.BusinessID, e)
+	})
+}
+
+func (s *Realtime) toInquiryTeam(ctx context.Context, bizID uuid.UUID, payload interface{}) error {
+	var biz models.Business
+	if err := s.DB.Select("id", "owner_id").Where("id = ?", bizID).First(&biz).Error; err != nil {
+		return err
+	}
+	team, err := teamWith(s.DB, &biz, authz.InquiryManage)
+	if err != nil {
+		return err
+	}
+	for _, userID := range team {
+		s.Hub.Publish(ctx, realtime.TypeInquiry, &userID, payload)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package subscribers
+
+import (
+	"context"
+
+	"github.com/saidimuKennedy/spotlight-africa/internal/events"
+	"github.com/saidimuKennedy/spotlight-africa/internal/jobs"
+)
+
+// Scoring queues a health score recompute whenever a scoring signal changes.
+type Scoring struct {
+	Jobs *jobs.Queue
+}
+
+// Register subscribes to every event that affects the health score.
+func (s *Scoring) Register(bus *events.Bus) {
+	events.On(bus, func(ctx context.Context, e events.LikeAdded) error {
+		return s.Jobs.RecomputeHealth(e.BusinessID)
+	})
+	events.On(bus, func(ctx context.Context, e events.LikeRemoved) error {
+		return s.Jobs.RecomputeHealth(e.BusinessID)
+	})
+	events.On(bus, func(ctx context.Context, e events.CommentAdded) error {
+		return s.Jobs.RecomputeHealth(e.BusinessID)
+	})
+	events.On(bus, func(ctx context.Context, e events.CommentVisibilityChanged) error {
+		return s.Jobs.RecomputeHealth(e.BusinessID)
+	})
+	events.On(bus, func(ctx context.Context, e events.CommentsModerated) error {
+		return s.Jobs.RecomputeHealth(e.BusinessID)
+	})
+	events.On(bus, func(ctx context.Context, e events.InquirySubmitted) error {
+		return s.Jobs.RecomputeHealth(e.BusinessID)
+	})
+	events.On(bus, func(ctx context.Context, e events.MeetingBooked) error {
+		return s.Jobs.RecomputeHealth(e.BusinessID)
+	})
+	events.On(bus, func(ctx context.Context, e events.MeetingStatusChanged) error {
+		return s.Jobs.RecomputeHealth(e.BusinessID)
+	})
+}
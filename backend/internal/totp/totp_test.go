@@ -0,0 +1,145 @@
+package totp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 seed from the test vectors in RFC 4226 and RFC 6238.
+var rfcSecret = encoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCodeMatchesRFC4226(t *testing.T) {
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, w := range want {
+		if got := code([]byte("12345678901234567890"), int64(counter)); got != w {
+			t.Errorf("counter %d: code = %s, want %s", counter, got, w)
+		}
+	}
+}
+
+func TestCodeMatchesRFC6238(t *testing.T) {
+	// The RFC lists 8-digit codes; ours are their last 6 digits
+	for unix, want := range map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	} {
+		got, err := Code(rfcSecret, time.Unix(unix, 0))
+		if err != nil {
+			t.Fatalf("Code: %v", err)
+		}
+		if got != want[len(want)-Digits:] {
+			t.Errorf("t=%d: code = %s, want %s", unix, got, want[len(want)-Digits:])
+		}
+	}
+}
+
+func TestValidateAcceptsSkew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	for offset, ok := range map[int]bool{-2: false, -1: true, 0: true, 1: true, 2: false} {
+		at := now.Add(time.Duration(offset) * Period)
+		passcode, _ := Code(rfcSecret, at)
+		matched, got := Validate(rfcSecret, passcode, now)
+		if got != ok {
+			t.Errorf("offset %d: ok = %v, want %v", offset, got, ok)
+		}
+		if ok && matched != step(at) {
+			t.Errorf("offset %d: matched step %d, want %d", offset, matched, step(at))
+		}
+	}
+}
+
+func TestValidateRejectsMalformedInput(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	passcode, _ := Code(rfcSecret, now)
+
+	if _, ok := Validate(rfcSecret, " "+passcode[:3]+" "+passcode[3:]+" ", now); !ok {
+		t.Error("a code typed with spaces was rejected")
+	}
+	for name, tc := range map[string][2]string{
+		"short code":     {rfcSecret, passcode[:5]},
+		"long code":      {rfcSecret, passcode + "0"},
+		"invalid secret": {"not base32!", passcode},
+	} {
+		if _, ok := Validate(tc[0], tc[1], now); ok {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+func TestSealRoundTrip(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	secret, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := Seal(key, secret)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if strings.Contains(sealed, secret) {
+		t.Fatal("sealed value contains the secret")
+	}
+	again, _ := Seal(key, secret)
+	if again == sealed {
+		t.Fatal("sealing twice gave the same value; the nonce is not random")
+	}
+
+	got, err := Open(key, sealed)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if got != secret {
+		t.Fatalf("Open = %q, want %q", got, secret)
+	}
+}
+
+func TestOpenRejectsTamperingAndOtherKeys(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	sealed, _ := Seal(key, rfcSecret)
+
+	raw, _ := base64.RawStdEncoding.DecodeString(sealed)
+	raw[len(raw)-1] ^= 1
+	flipped := base64.RawStdEncoding.EncodeToString(raw)
+
+	for name, tc := range map[string]struct {
+		key    []byte
+		sealed string
+	}{
+		"other key":  {bytes.Repeat([]byte{8}, 32), sealed},
+		"tampered":   {key, flipped},
+		"truncated":  {key, sealed[:8]},
+		"not base64": {key, "!!!"},
+	} {
+		if _, err := Open(tc.key, tc.sealed); err != ErrInvalidSecret {
+			t.Errorf("%s: err = %v, want ErrInvalidSecret", name, err)
+		}
+	}
+}
+
+func TestKeyFromEnvIsNotTheTokenSecret(t *testing.T) {
+	t.Setenv("TOTP_ENCRYPTION_KEY", "")
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	derived := KeyFromEnv()
+	plain := sha256.Sum256([]byte("test-secret"))
+	if len(derived) != 32 || bytes.Equal(derived, plain[:]) || bytes.Contains(derived, []byte("test-secret")) {
+		t.Fatalf("derived key %x is the token secret or a plain hash of it", derived)
+	}
+	if !bytes.Equal(derived, KeyFromEnv()) {
+		t.Fatal("derived key is not stable")
+	}
+
+	t.Setenv("TOTP_ENCRYPTION_KEY", "sealing-key")
+	if bytes.Equal(KeyFromEnv(), derived) {
+		t.Fatal("TOTP_ENCRYPTION_KEY was ignored")
+	}
+}
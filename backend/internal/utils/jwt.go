@@ -11,22 +11,36 @@ import (
 
 // Claims defines the structured data we store inside the token
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Role   string    `json:"role"`
+	UserID    uuid.UUID `json:"user_id"`
+	Role      string    `json:"role"`
+	SessionID uuid.UUID `json:"sid"`           // The session that issued the token; revoking it invalidates the token
+	Version   int       `json:"ver"`           // The user's TokenVersion when the token was issued
+	TwoFactor bool      `json:"mfa,omitempty"` // The session passed a second factor
 	jwt.RegisteredClaims
 }
 
+// AccessTokenTTL is how long an access token lasts (ACCESS_TOKEN_TTL, default 15m).
+// Clients use their refresh token to get a new one.
+func AccessTokenTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("ACCESS_TOKEN_TTL")); err == nil && ttl > 0 {
+		return ttl
+	}
+	return 15 * time.Minute
+}
+
 // GenerateToken creates a signed JWT for a specific user
-func GenerateToken(userID uuid.UUID, role string) (string, error) {
+func GenerateToken(userID uuid.UUID, role string, sessionID uuid.UUID, version int, twoFactor bool) (string, error) {
 	secret := []byte(os.Getenv("JWT_SECRET"))
 
-	// We set the expiration (e.g., 24 hours). 
-	// JWTs are not meant to last forever; refresh often.
+	// Access tokens are short-lived; the session's refresh token keeps the user signed in.
 	claims := &Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
+		Version:   version,
+		TwoFactor: twoFactor,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenTTL())),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
@@ -1,6 +1,54 @@
 package utils
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"errors"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrPasswordBusy means every hashing slot stayed taken while we waited for one.
+var ErrPasswordBusy = errors.New("password hashing is busy")
+
+// hashSlots caps how many bcrypt operations run at once (BCRYPT_CONCURRENCY, default one
+// per CPU). At cost 14 each one takes about a second of CPU, so a burst of logins would
+// otherwise starve every other request. It is sized on first use, after main has loaded .env.
+var hashSlots = sync.OnceValue(func() chan struct{} {
+	return make(chan struct{}, hashConcurrency())
+})
+
+// NoAccountHash is a cost-14 hash of a random password nobody knows. Logins for an email
+// with no account are checked against it, so they take as long as a wrong password and
+// timing doesn't reveal which emails are registered.
+const NoAccountHash = "$2a$14$fB/d656hzNDEyuW0/ZmrEeQ97DkEGB0A2wEL/kVFq140rXN1rczIC"
+
+// hashWait is how long a request queues for a slot before giving up.
+const hashWait = 5 * time.Second
+
+func hashConcurrency() int {
+	if n, err := strconv.Atoi(os.Getenv("BCRYPT_CONCURRENCY")); err == nil && n > 0 {
+		return n
+	}
+	return runtime.NumCPU()
+}
+
+// withHashSlot runs fn once a hashing slot is free.
+func withHashSlot(fn func()) error {
+	timer := time.NewTimer(hashWait)
+	defer timer.Stop()
+	select {
+	case hashSlots() <- struct{}{}:
+		defer func() { <-hashSlots() }()
+		fn()
+		return nil
+	case <-timer.C:
+		return ErrPasswordBusy
+	}
+}
 
 //
 //Take a string (password), hash the passsword and return the hashed string (password)
@@ -8,14 +56,32 @@ func HashPassword (password string) (string, error){
 	// bcrypt.GenerateFromPassword hashes the password with a "Salt" to prevent rainbow table attacks.
 	// The number '14' is the "Work Factor" or "Cost". Higher numbers are more secure but slower.
 	// 14 is a strong default for modern servers.
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
+	var bytes []byte
+	var err error
+	if busy := withHashSlot(func() {
+		bytes, err = bcrypt.GenerateFromPassword([]byte(password), 14)
+	}); busy != nil {
+		return "", busy
+	}
 	return string(bytes), err
 }
 //
+// ComparePassword reports whether the plain text password matches the hash. The error is
+// ErrPasswordBusy when the server is too busy to check.
+func ComparePassword(password, hash string) (bool, error) {
+	var err error
+	if busy := withHashSlot(func() {
+		err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	}); busy != nil {
+		return false, busy
+	}
+	return err == nil, nil
+}
+//
 // CheckPasswordHash compares the hashed password with a plain text one
 func CheckPasswordHash(password, hash string) bool{
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+	ok, _ := ComparePassword(password, hash)
+	return ok
 }
 
 //
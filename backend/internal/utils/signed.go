@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidSignedValue = errors.New("invalid or tampered token")
+	ErrExpiredSignedValue = errors.New("token has expired")
+)
+
+// SignValue returns a URL-safe token that carries value and proves we issued it.
+// purpose binds the token to one use, so an unsubscribe token can't confirm a
+// subscription. A ttl of 0 makes the token valid forever (e.g. links in old emails).
+func SignValue(purpose, value string, ttl time.Duration) string {
+	var exp int64
+	if ttl > 0 {
+		exp = time.Now().Add(ttl).Unix()
+	}
+	payload := value + "\n" + strconv.FormatInt(exp, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(signature(purpose, payload))
+}
+
+// VerifySignedValue checks a token made by SignValue for the same purpose and returns its value.
+func VerifySignedValue(purpose, token string) (string, error) {
+	encPayload, encSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrInvalidSignedValue
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encPayload)
+	if err != nil {
+		return "", ErrInvalidSignedValue
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encSig)
+	if err != nil || !hmac.Equal(sig, signature(purpose, string(payload))) {
+		return "", ErrInvalidSignedValue
+	}
+
+	value, expStr, ok := strings.Cut(string(payload), "\n")
+	if !ok {
+		return "", ErrInvalidSignedValue
+	}
+	exp, err := strconv.ParseInt(expStr, 10, 64)
+	if err != nil {
+		return "", ErrInvalidSignedValue
+	}
+	if exp != 0 && time.Now().Unix() > exp {
+		return "", ErrExpiredSignedValue
+	}
+	return value, nil
+}
+
+func signature(purpose, payload string) []byte {
+	mac := hmac.New(sha256.New, []byte(os.Getenv("JWT_SECRET")))
+	mac.Write([]byte(purpose + "\n" + payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignedValueRoundTrip(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	for _, ttl := range []time.Duration{0, time.Hour} {
+		token := SignValue("newsletter.unsubscribe", "amina@example.com", ttl)
+		got, err := VerifySignedValue("newsletter.unsubscribe", token)
+		if err != nil {
+			t.Fatalf("ttl %v: %v", ttl, err)
+		}
+		if got != "amina@example.com" {
+			t.Fatalf("ttl %v: value = %q", ttl, got)
+		}
+	}
+}
+
+func TestSignedValueRejectsOtherPurpose(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	token := SignValue("newsletter.unsubscribe", "amina@example.com", 0)
+	if _, err := VerifySignedValue("newsletter.confirm", token); err != ErrInvalidSignedValue {
+		t.Fatalf("err = %v, want ErrInvalidSignedValue", err)
+	}
+}
+
+func TestSignedValueRejectsTampering(t *testing.T) {
+	t.Setenv("JWT_SECRET", "another-secret")
+	signedElsewhere := SignValue("newsletter.confirm", "amina@example.com", time.Hour)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	token := SignValue("newsletter.confirm", "amina@example.com", time.Hour)
+	encPayload, encSig, _ := strings.Cut(token, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte("mallory@example.com\n0"))
+
+	for name, bad := range map[string]string{
+		"swapped payload":   forged + "." + encSig,
+		"truncated sig":     encPayload + "." + encSig[:len(encSig)-2],
+		"no signature":      encPayload,
+		"missing separator": encPayload + encSig,
+		"not base64":        "!!!." + encSig,
+		"empty":             "",
+		"other secret":      signedElsewhere,
+	} {
+		if _, err := VerifySignedValue("newsletter.confirm", bad); err != ErrInvalidSignedValue {
+			t.Errorf("%s: err = %v, want ErrInvalidSignedValue", name, err)
+		}
+	}
+}
+
+func TestSignedValueExpires(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	// Build a token that expired a minute ago, signed like SignValue would.
+	payload := "amina@example.com\n" + strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	token := base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(signature("newsletter.confirm", payload))
+
+	if _, err := VerifySignedValue("newsletter.confirm", token); err != ErrExpiredSignedValue {
+		t.Fatalf("err = %v, want ErrExpiredSignedValue", err)
+	}
+}
@@ -0,0 +1,112 @@
+// Package webhooks forwards domain events to external HTTP endpoints.
+// Deliveries go through the job queue, so a slow or failing endpoint is retried
+// with backoff and never holds up the request that raised the event.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saidimuKennedy/spotlight-africa/internal/events"
+	"github.com/saidimuKennedy/spotlight-africa/internal/jobs"
+)
+
+// Dispatcher posts every domain event to the configured endpoints.
+// Each request carries an X-Spotlight-Signature header: "sha256=" + hex HMAC of the body using Secret.
+type Dispatcher struct {
+	Jobs   *jobs.Queue
+	URLs   []string
+	Secret string
+	Client *http.Client
+}
+
+// envelope is the JSON body sent to endpoints.
+type envelope struct {
+	ID         uuid.UUID    `json:"id"`
+	Event      string       `json:"event"`
+	OccurredAt time.Time    `json:"occurred_at"`
+	Data       events.Event `json:"data"`
+}
+
+// NewDispatcherFromEnv reads WEBHOOK_URLS (comma separated) and WEBHOOK_SECRET.
+// With no URLs configured the dispatcher does nothing.
+func NewDispatcherFromEnv(queue *jobs.Queue) *Dispatcher {
+	d := &Dispatcher{
+		Jobs:   queue,
+		Secret: os.Getenv("WEBHOOK_SECRET"),
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, u := range strings.Split(os.Getenv("WEBHOOK_URLS"), ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			d.URLs = append(d.URLs, u)
+		}
+	}
+	return d
+}
+
+// Register subscribes the dispatcher to every event on the bus.
+func (d *Dispatcher) Register(bus *events.Bus) {
+	if len(d.URLs) == 0 {
+		return
+	}
+	bus.SubscribeAll(d.enqueue)
+}
+
+func (d *Dispatcher) enqueue(ctx context.Context, e events.Event) error {
+	body, err := json.Marshal(envelope{ID: uuid.New(), Event: e.EventName(), OccurredAt: time.Now().UTC(), Data: e})
+	if err != nil {
+		return err
+	}
+	for _, u := range d.URLs {
+		if err := d.Jobs.Enqueue(jobs.TypeWebhook, jobs.Webhook{URL: u, Event: e.EventName(), Body: string(body)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deliver is the job handler for jobs.TypeWebhook.
+func (d *Dispatcher) Deliver(ctx context.Context, payload []byte) error {
+	var hook jobs.Webhook
+	if err := json.Unmarshal(payload, &hook); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewBufferString(hook.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Spotlight-Event", hook.Event)
+	if d.Secret != "" {
+		req.Header.Set("X-Spotlight-Signature", "sha256="+Sign(d.Secret, []byte(hook.Body)))
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s responded %d", hook.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of body. Receivers recompute it to verify a delivery.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/saidimuKennedy/spotlight-africa/internal/jobs"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+)
+
+// DigestWorker periodically queues email digests for users whose daily or weekly
+// digest is due and who have unread notifications.
+type DigestWorker struct {
+	Repo     *repository.NotificationPreferenceRepository
+	Jobs     *jobs.Queue
+	Interval time.Duration
+}
+
+// Start begins the background scheduling loop.
+func (w *DigestWorker) Start() {
+	if w.Interval <= 0 {
+		w.Interval = time.Hour
+	}
+
+	go func() {
+		log.Println("🚀 Starting Digest Worker...")
+
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			users, err := w.Repo.DueForDigest(time.Now(), 500)
+			if err != nil {
+				log.Println("⚠️ Digest Worker: could not find due digests:", err)
+				continue
+			}
+			for _, id := range users {
+				if err := w.Jobs.Enqueue(jobs.TypeDigest, jobs.Digest{UserID: id}, jobs.Dedupe("digest:"+id.String())); err != nil {
+					log.Println("⚠️ Digest Worker: could not queue digest:", err)
+				}
+			}
+		}
+	}()
+}
+
+// digestLimit caps how many notifications are listed in one email.
+const digestLimit = 20
+
+// sendDigest emails a summary of the user's unread notifications since their last digest.
+func (h *JobHandlers) sendDigest(ctx context.Context, payload []byte) error {
+	var p jobs.Digest
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return err
+	}
+
+	settings, err := h.PreferenceRepo.GetSettings(p.UserID)
+	if err != nil {
+		return err
+	}
+	if settings.DigestPeriod() == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	unread, err := h.NotificationRepo.UnreadSince(p.UserID, settings.LastDigestAt, digestLimit)
+	if err != nil {
+		return err
+	}
+	if len(unread) == 0 {
+		return h.PreferenceRepo.MarkDigestSent(p.UserID, now)
+	}
+
+	user, err := h.NotificationRepo.Recipient(p.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Outbox.Queue(nil, user.Email, "digest", user.Locale, map[string]interface{}{
+		"Name":      user.Name,
+		"Frequency": settings.DigestFrequency,
+		"Items":     unread,
+	}); err != nil {
+		return err
+	}
+	return h.PreferenceRepo.MarkDigestSent(p.UserID, now)
+}
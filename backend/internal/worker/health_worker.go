@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"log"
+	"time"
+
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+)
+
+// HealthScoreWorker periodically rescores every business. Scores decay with time,
+// so a profile that goes quiet has to drift down even if no new interaction triggers a recalculation.
+// It also forgets view records once they are too old to stop a repeat view from counting.
+type HealthScoreWorker struct {
+	Repo       *repository.HealthRepository
+	Businesses *repository.BusinessRepository
+	Interval   time.Duration
+	ViewPeriod time.Duration // How long one viewer's view of a business counts once
+}
+
+// Start begins the background recompute loop.
+func (w *HealthScoreWorker) Start() {
+	if w.Interval <= 0 {
+		w.Interval = time.Hour
+	}
+	if w.ViewPeriod <= 0 {
+		w.ViewPeriod = 24 * time.Hour
+	}
+
+	go func() {
+		log.Println("🚀 Starting Health Score Worker...")
+
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			start := time.Now()
+			updated, err := w.Repo.RecalculateAll(200)
+			if err != nil {
+				log.Println("⚠️ Health Score Worker: recompute failed:", err)
+				continue
+			}
+			log.Printf("✅ Health Score Worker: rescored %d businesses in %s", updated, time.Since(start).Round(time.Millisecond))
+
+			if w.Businesses != nil {
+				if n, err := w.Businesses.PruneViews(w.ViewPeriod); err != nil {
+					log.Println("⚠️ Health Score Worker: pruning views failed:", err)
+				} else if n > 0 {
+					log.Printf("🧹 Health Score Worker: pruned %d view records", n)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,129 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/saidimuKennedy/spotlight-africa/internal/jobs"
+	"github.com/saidimuKennedy/spotlight-africa/internal/mail"
+	"github.com/saidimuKennedy/spotlight-africa/internal/models"
+	"github.com/saidimuKennedy/spotlight-africa/internal/realtime"
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+	"gorm.io/gorm"
+)
+
+// JobHandlers executes the job types queued by the controllers.
+type JobHandlers struct {
+	ActivityRepo     *repository.ActivityRepository
+	HealthRepo       *repository.HealthRepository
+	NotificationRepo *repository.NotificationRepository
+	PreferenceRepo   *repository.NotificationPreferenceRepository
+	Outbox           *mail.Outbox
+	Realtime         *realtime.Hub // Optional; pushes new notifications to connected clients
+}
+
+// Register wires every job type to its handler on the runner.
+func (h *JobHandlers) Register(r *jobs.Runner) {
+	r.Handle(jobs.TypeHealthRecompute, h.recomputeHealth)
+	r.Handle(jobs.TypeActivityTrack, h.trackActivity)
+	r.Handle(jobs.TypeNotification, h.createNotification)
+	r.Handle(jobs.TypeEmail, h.Outbox.Deliver)
+	r.Handle(jobs.TypeDigest, h.sendDigest)
+}
+
+// recomputeHealth rescores a business and logs the new score on its activity feed.
+func (h *JobHandlers) recomputeHealth(ctx context.Context, payload []byte) error {
+	var p jobs.HealthRecompute
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return err
+	}
+
+	breakdown, err := h.HealthRepo.Recalculate(p.BusinessID.String())
+	if err != nil {
+		return err
+	}
+
+	return h.ActivityRepo.Track(&models.Activity{
+		EntityID:   p.BusinessID,
+		EntityType: "business",
+		Type:       models.ActivityTypeHealthEval,
+		Value:      float64(breakdown.Score),
+		Metadata:   fmt.Sprintf("Health Score recalibrated to %d%%", breakdown.Score),
+		CreatedAt:  time.Now(),
+	})
+}
+
+func (h *JobHandlers) trackActivity(ctx context.Context, payload []byte) error {
+	var activity models.Activity
+	if err := json.Unmarshal(payload, &activity); err != nil {
+		return err
+	}
+	return h.ActivityRepo.Track(&activity)
+}
+
+// createNotification delivers a notification on the channels the recipient has enabled.
+// During quiet hours it is stored but not pushed, and any email waits until the quiet hours end.
+func (h *JobHandlers) createNotification(ctx context.Context, payload []byte) error {
+	var p jobs.Notification
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return err
+	}
+	n := &p.Notification
+
+	settings, err := h.PreferenceRepo.GetSettings(n.UserID)
+	if err != nil {
+		return err
+	}
+	quietUntil, quiet := settings.QuietUntil(time.Now())
+
+	inApp, err := h.PreferenceRepo.ChannelEnabled(n.UserID, n.Type, models.ChannelInApp)
+	if err != nil {
+		return err
+	}
+	email, err := h.PreferenceRepo.ChannelEnabled(n.UserID, n.Type, models.ChannelEmail)
+	if err != nil {
+		return err
+	}
+
+	// The notification and its email are stored together, so a retry after a failure
+	// can't leave a duplicate in the inbox.
+	err = h.NotificationRepo.DB.Transaction(func(tx *gorm.DB) error {
+		// Grouped notifications only email on the first event of the group; the rest reach the digest.
+		firstOfGroup := true
+		if inApp {
+			stored, err := h.NotificationRepo.Push(tx, n, p.ActorID, p.GroupMessage)
+			if err != nil {
+				return err
+			}
+			n = stored
+			firstOfGroup = n.Count == 1
+		}
+		if !firstOfGroup || !email {
+			return nil
+		}
+
+		user, err := h.NotificationRepo.Recipient(n.UserID)
+		if err != nil {
+			return err
+		}
+		var opts []jobs.Option
+		if quiet {
+			opts = append(opts, jobs.Delay(time.Until(quietUntil)))
+		}
+		return h.Outbox.Queue(tx, user.Email, "notification", user.Locale, map[string]interface{}{
+			"Title":   n.Title,
+			"Message": n.Message,
+			"Link":    n.Link,
+		}, opts...)
+	})
+	if err != nil {
+		return err
+	}
+
+	if inApp && !quiet {
+		h.Realtime.Publish(ctx, realtime.TypeNotification, &n.UserID, n)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"log"
+	"time"
+
+	"github.com/saidimuKennedy/spotlight-africa/internal/repository"
+)
+
+// loginThrottleIdle is how long a failure counter is kept after its last failure. It is the
+// longest window failures count for (a day, for accounts), so pruning never forgets one early.
+const loginThrottleIdle = 24 * time.Hour
+
+// LoginRetentionWorker deletes old login attempts and stale failure counters every hour,
+// so the audit trail and the throttle table don't grow forever.
+type LoginRetentionWorker struct {
+	Repo      *repository.LoginAttemptRepository
+	Retention time.Duration // How long attempts are kept (default 90 days)
+}
+
+// Start begins the background pruning loop.
+func (w *LoginRetentionWorker) Start() {
+	if w.Retention <= 0 {
+		w.Retention = 90 * 24 * time.Hour
+	}
+
+	go func() {
+		log.Println("🚀 Starting Login Retention Worker...")
+
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+
+		for ; ; <-ticker.C {
+			attempts, throttles, err := w.Repo.Prune(w.Retention, loginThrottleIdle)
+			if err != nil {
+				log.Println("⚠️ Login Retention Worker: prune failed:", err)
+				continue
+			}
+			if attempts > 0 || throttles > 0 {
+				log.Printf("🧹 Login Retention Worker: pruned %d login attempts and %d throttles", attempts, throttles)
+			}
+		}
+	}()
+}
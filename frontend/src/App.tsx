@@ -44,7 +44,13 @@ import NewsPage from "./pages/NewsPage";
 import NewsArticlePage from "./pages/NewsArticlePage";
 import BusinessesPage from "./pages/BusinessesPage";
 import BlogPostPage from "./pages/BlogPostPage";
+import NewsletterPage from "./pages/NewsletterPage";
+import ResetPasswordPage from "./pages/ResetPasswordPage";
+import VerifyEmailPage from "./pages/VerifyEmailPage";
+import AcceptInvitationPage from "./pages/AcceptInvitationPage";
+import OAuthCallbackPage from "./pages/OAuthCallbackPage";
 import BlogManagementPage from "./pages/dashboard/BlogManagementPage";
+import TeamPage from "./pages/dashboard/TeamPage";
 
 /**
  * ProtectedRoute Component
@@ -176,6 +182,11 @@ function AppContent() {
           <Route path="/news" element={<NewsPage />} />
           <Route path="/news/:slug" element={<NewsArticlePage />} />
           <Route path="/businesses" element={<BusinessesPage />} />
+          <Route path="/newsletter" element={<NewsletterPage />} />
+          <Route path="/reset-password" element={<ResetPasswordPage />} />
+          <Route path="/verify-email" element={<VerifyEmailPage />} />
+          <Route path="/invitations/accept" element={<AcceptInvitationPage />} />
+          <Route path="/auth/callback/:provider" element={<OAuthCallbackPage />} />
 
           {/* Dashboard Routes wrapped in DashboardLayout */}
           <Route
@@ -189,6 +200,7 @@ function AppContent() {
                     <Route path="inquiries" element={<InquiriesPage />} />
                     <Route path="performance" element={<PerformancePage />} />
                     <Route path="business" element={<BusinessEditorPage />} />
+                    <Route path="team" element={<TeamPage />} />
                     <Route path="blogs" element={<BlogManagementPage />} />
                     <Route path="settings" element={<SettingsPage />} />
                   </Routes>
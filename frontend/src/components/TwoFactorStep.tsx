@@ -0,0 +1,129 @@
+import { useEffect, useState } from "react";
+import { KeyRound, Smartphone } from "lucide-react";
+import {
+  TwoFactorChallenge,
+  TwoFactorSetup,
+  confirmTwoFactorSetup,
+  startTwoFactorSetup,
+  verifyTwoFactor,
+} from "../lib/api";
+
+interface TwoFactorStepProps {
+  challenge: TwoFactorChallenge;
+  onSignedIn: () => void;
+}
+
+/**
+ * TwoFactorStep - the second step of logging in. Asks for the code from the user's
+ * authenticator app, or walks them through setting one up when their role requires it.
+ */
+const TwoFactorStep = ({ challenge, onSignedIn }: TwoFactorStepProps) => {
+  const [code, setCode] = useState("");
+  const [setup, setSetup] = useState<TwoFactorSetup | null>(null);
+  const [recoveryCodes, setRecoveryCodes] = useState<string[]>([]);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState("");
+  const settingUp = !!challenge.two_factor_setup_required;
+
+  useEffect(() => {
+    if (!settingUp) return;
+    startTwoFactorSetup(challenge.mfa_token)
+      .then(setSetup)
+      .catch((err) => setError(err instanceof Error ? err.message : "Could not start setup"));
+  }, [challenge.mfa_token, settingUp]);
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setLoading(true);
+    setError("");
+    try {
+      if (settingUp) {
+        const data = await confirmTwoFactorSetup(challenge.mfa_token, code);
+        setRecoveryCodes(data.recovery_codes);
+      } else {
+        await verifyTwoFactor(challenge.mfa_token, code);
+        onSignedIn();
+      }
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Could not verify code");
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  if (recoveryCodes.length > 0) {
+    return (
+      <div className="space-y-6">
+        <p className="text-white/60 text-sm font-serif italic">
+          Save these recovery codes somewhere safe. Each one signs you in once if you lose your
+          phone. They won't be shown again.
+        </p>
+        <div className="grid grid-cols-2 gap-2 font-mono text-sm text-accent-gold">
+          {recoveryCodes.map((c) => (
+            <span key={c} className="bg-slate-900/50 border border-white/5 px-3 py-2 text-center">
+              {c}
+            </span>
+          ))}
+        </div>
+        <button type="button" onClick={onSignedIn} className="w-full btn-primary py-4">
+          <span className="text-[10px] uppercase font-bold tracking-[0.3em]">I've saved them</span>
+        </button>
+      </div>
+    );
+  }
+
+  return (
+    <form onSubmit={handleSubmit} className="space-y-6">
+      <p className="text-white/60 text-sm font-serif italic">{challenge.message}</p>
+
+      {error && (
+        <div className="bg-red-500/10 border border-red-500/20 p-4 text-red-400 text-xs font-bold uppercase tracking-widest text-center">
+          {error}
+        </div>
+      )}
+
+      {settingUp && setup && (
+        <div className="space-y-3 p-4 bg-slate-900/50 border border-white/5">
+          <p className="text-xs text-white/50">
+            Add this key to your authenticator app, or open the link on your phone:
+          </p>
+          <p className="font-mono text-sm text-accent-gold break-all">{setup.secret}</p>
+          <a
+            href={setup.uri}
+            className="inline-flex items-center gap-2 text-[10px] font-bold uppercase tracking-widest text-white/40 hover:text-accent-gold transition-colors"
+          >
+            <Smartphone className="w-3 h-3" /> Open in authenticator app
+          </a>
+        </div>
+      )}
+
+      <div className="space-y-2">
+        <label className="text-[10px] font-bold text-white/40 uppercase tracking-[0.2em] ml-1">
+          {settingUp ? "Code from your app" : "Authentication or recovery code"}
+        </label>
+        <div className="relative">
+          <KeyRound className="absolute left-4 top-1/2 -translate-y-1/2 w-4 h-4 text-white/20" />
+          <input
+            value={code}
+            onChange={(e) => setCode(e.target.value)}
+            required
+            autoFocus
+            autoComplete="one-time-code"
+            className="w-full bg-slate-900/50 border border-white/5 px-12 py-4 text-white tracking-[0.3em] focus:outline-none focus:border-accent-gold/50 transition-all font-light"
+            placeholder="123456"
+          />
+        </div>
+      </div>
+
+      <button type="submit" disabled={loading} className="w-full btn-primary py-4">
+        {loading ? (
+          <div className="w-5 h-5 mx-auto border-2 border-white/20 border-t-white rounded-full animate-spin" />
+        ) : (
+          <span className="text-[10px] uppercase font-bold tracking-[0.3em]">Verify</span>
+        )}
+      </button>
+    </form>
+  );
+};
+
+export default TwoFactorStep;
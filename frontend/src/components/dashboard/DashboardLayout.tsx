@@ -22,9 +22,11 @@ import {
   Globe,
   ArrowLeft,
   BookOpen,
+  UserPlus,
+  Plus,
 } from "lucide-react";
 import { Link, useLocation, useNavigate } from "react-router-dom";
-import { fetchDashboardMe, DashboardData } from "../../lib/api";
+import { fetchDashboardMe, logout, selectBusiness, DashboardData } from "../../lib/api";
 
 interface SidebarItemProps {
   icon: React.ReactNode;
@@ -83,8 +85,8 @@ const DashboardLayout: React.FC<{ children: React.ReactNode }> = ({
       .catch((err) => console.error("Layout data fetch failed", err));
   }, []);
 
-  const handleLogout = () => {
-    localStorage.removeItem("token");
+  const handleLogout = async () => {
+    await logout();
     localStorage.removeItem("user");
     navigate("/");
   };
@@ -115,6 +117,11 @@ const DashboardLayout: React.FC<{ children: React.ReactNode }> = ({
       label: "My Business",
       path: "/dashboard/business",
     },
+    {
+      icon: <UserPlus size={20} />,
+      label: "Team",
+      path: "/dashboard/team",
+    },
   ];
 
   // Add Admin-only items
@@ -153,6 +160,34 @@ const DashboardLayout: React.FC<{ children: React.ReactNode }> = ({
           </button>
         </div>
 
+        {/* Business switcher, for users who run several businesses */}
+        {!collapsed && data?.business && (
+          <div className="px-6 space-y-2">
+            {data.businesses && data.businesses.length > 1 && (
+              <select
+                value={data.business.id}
+                onChange={(e) => {
+                  selectBusiness(e.target.value);
+                  window.location.reload();
+                }}
+                className="w-full bg-transparent border border-white/10 text-xs text-white px-3 py-2 uppercase tracking-widest"
+              >
+                {data.businesses.map((b) => (
+                  <option key={b.id} value={b.id} className="bg-bg-primary">
+                    {b.name}
+                  </option>
+                ))}
+              </select>
+            )}
+            <Link
+              to="/register-business"
+              className="flex items-center gap-2 text-[10px] font-bold text-white/40 hover:text-accent-gold uppercase tracking-widest transition-colors"
+            >
+              <Plus size={12} /> Add a business
+            </Link>
+          </div>
+        )}
+
         <nav className="flex-1 px-4 space-y-2 mt-4 overflow-y-auto custom-scrollbar">
           {menuItems.map((item) => (
             <SidebarItem
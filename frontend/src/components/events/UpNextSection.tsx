@@ -88,7 +88,7 @@ const UpNextSection = () => {
     try {
       await subscribeNewsletter(email);
       setToast({
-        message: "Successfully subscribed to the dispatch",
+        message: "Almost there: check your inbox to confirm",
         type: "success",
         isVisible: true,
       });
@@ -12,6 +12,7 @@ import {
 } from "lucide-react";
 import { useState, useEffect } from "react";
 import NotificationDropdown from "./navbar/NotificationDropdown";
+import { logout } from "../lib/api";
 
 const Navbar = () => {
   const [isLoggedIn, setIsLoggedIn] = useState(false);
@@ -26,9 +27,8 @@ const Navbar = () => {
     setRole(storedRole);
   }, []);
 
-  const handleLogout = () => {
-    localStorage.removeItem("token");
-    localStorage.removeItem("role");
+  const handleLogout = async () => {
+    await logout();
     setIsLoggedIn(false);
     setRole(null);
     navigate("/");
@@ -4,6 +4,7 @@ import {
   fetchNotifications,
   markNotificationRead,
   markAllNotificationsRead,
+  subscribeToStream,
   AppNotification,
 } from "../../lib/api";
 import { formatDistanceToNow } from "date-fns";
@@ -31,6 +32,18 @@ const NotificationDropdown = () => {
     }
   }, [isOpen]);
 
+  // New and regrouped notifications are pushed over the live stream
+  useEffect(() => {
+    const close = subscribeToStream({
+      notification: (notif: AppNotification) =>
+        setNotifications((prev: AppNotification[]) => [
+          notif,
+          ...prev.filter((n: AppNotification) => n.id !== notif.id),
+        ]),
+    });
+    return () => close?.();
+  }, []);
+
   const handleMarkRead = async (id: string) => {
     try {
       await markNotificationRead(id);
@@ -11,6 +11,7 @@ export interface Business {
   category: string;
   like_count?: number;
   comment_count?: number;
+  reaction_counts?: Partial<Record<"like" | "inspiring" | "want_to_partner", number>>;
   created_at: string;
   updated_at: string;
 }
@@ -19,13 +20,27 @@ export interface Comment {
   id: string;
   user_id: string;
   business_id: string;
+  parent_id?: string | null;
+  depth: number;
   content: string;
+  edited_at?: string | null;
+  deleted_at?: string | null;
+  reply_count: number;
+  replies?: Comment[];
   created_at: string;
   user?: {
     email: string;
   };
 }
 
+export interface CommentPage {
+  comments: Comment[];
+  total: number;
+  limit: number;
+  offset: number;
+  sort: "newest" | "top";
+}
+
 export interface ChatMessage {
   id: string;
   user_id: string;
@@ -39,8 +54,56 @@ export interface ChatMessage {
 
 const API_BASE_URL = "http://localhost:8080";
 
-// Helper for authenticated requests
-async function authFetch(url: string, options: RequestInit = {}) {
+// Session tokens. The access token is short-lived; the refresh token gets a new pair.
+export interface SessionTokens {
+  token: string;
+  refresh_token: string;
+  role: string;
+  expires_in: number;
+  email_verified: boolean;
+}
+
+export function storeSession(data: SessionTokens) {
+  localStorage.setItem("token", data.token);
+  localStorage.setItem("refresh_token", data.refresh_token);
+  localStorage.setItem("role", data.role);
+}
+
+function clearSession() {
+  localStorage.removeItem("token");
+  localStorage.removeItem("refresh_token");
+  localStorage.removeItem("role");
+  localStorage.removeItem("business_id");
+}
+
+// Refresh tokens rotate on use, so concurrent callers share one in-flight refresh.
+let refreshing: Promise<boolean> | null = null;
+
+async function refreshSession(): Promise<boolean> {
+  const refreshToken = localStorage.getItem("refresh_token");
+  if (!refreshToken) return false;
+  if (!refreshing) {
+    refreshing = fetch(`${API_BASE_URL}/auth/refresh`, {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ refresh_token: refreshToken }),
+    })
+      .then(async (response) => {
+        if (!response.ok) return false;
+        storeSession(await response.json());
+        return true;
+      })
+      .catch(() => false)
+      .finally(() => {
+        refreshing = null;
+      });
+  }
+  return refreshing;
+}
+
+// Helper for authenticated requests. An expired access token is refreshed once and the
+// request retried; if the session has ended the user is signed out.
+async function authFetch(url: string, options: RequestInit = {}, retried = false): Promise<Response> {
   const token = localStorage.getItem("token");
   if (!token) {
     throw new Error("UNAUTHORIZED");
@@ -56,7 +119,10 @@ async function authFetch(url: string, options: RequestInit = {}) {
   });
 
   if (response.status === 401) {
-    localStorage.removeItem("token");
+    if (!retried && (await refreshSession())) {
+      return authFetch(url, options, true);
+    }
+    clearSession();
     throw new Error("UNAUTHORIZED");
   }
 
@@ -70,6 +136,227 @@ async function authFetch(url: string, options: RequestInit = {}) {
   return response;
 }
 
+// Ends this device's session. Local tokens are cleared even if the request fails.
+export async function logout(): Promise<void> {
+  try {
+    await authFetch(`${API_BASE_URL}/auth/logout`, { method: "POST" });
+  } catch {
+    // Already signed out server-side
+  } finally {
+    clearSession();
+  }
+}
+
+export async function logoutEverywhere(): Promise<void> {
+  try {
+    await authFetch(`${API_BASE_URL}/auth/logout-all`, { method: "POST" });
+  } finally {
+    clearSession();
+  }
+}
+
+export interface UserSession {
+  id: string;
+  user_agent: string;
+  ip: string;
+  last_used_at: string;
+  expires_at: string;
+  created_at: string;
+  current: boolean;
+}
+
+export async function fetchSessions(): Promise<UserSession[]> {
+  const response = await authFetch(`${API_BASE_URL}/me/sessions`);
+  const data = await response.json();
+  return data.items;
+}
+
+export async function revokeSession(id: string): Promise<void> {
+  await authFetch(`${API_BASE_URL}/me/sessions/${id}`, { method: "DELETE" });
+}
+
+// Two-factor authentication. When it's on (or the user's role requires it), logging in
+// returns a challenge instead of tokens; the code from the authenticator app, or a
+// recovery code, exchanges the mfa_token for a session.
+export interface TwoFactorChallenge {
+  mfa_token: string;
+  two_factor_required?: boolean;
+  two_factor_setup_required?: boolean;
+  message: string;
+}
+
+export interface TwoFactorSetup {
+  secret: string;
+  uri: string; // otpauth:// provisioning URI
+}
+
+export interface TwoFactorStatus {
+  enabled: boolean;
+  required: boolean;
+  enabled_at?: string;
+  recovery_codes_left?: number;
+}
+
+export function isTwoFactorChallenge(data: object): data is TwoFactorChallenge {
+  return "mfa_token" in data;
+}
+
+export async function verifyTwoFactor(mfaToken: string, code: string): Promise<SessionTokens> {
+  const data = await publicRequest<SessionTokens>(
+    "/auth/2fa/verify",
+    { method: "POST", body: JSON.stringify({ mfa_token: mfaToken, code }) },
+    "Could not verify code",
+  );
+  storeSession(data);
+  return data;
+}
+
+// For users whose role requires two-factor but who haven't set it up yet.
+export async function startTwoFactorSetup(mfaToken: string): Promise<TwoFactorSetup> {
+  return publicRequest(
+    "/auth/2fa/setup",
+    { method: "POST", body: JSON.stringify({ mfa_token: mfaToken }) },
+    "Could not start setup",
+  );
+}
+
+export async function confirmTwoFactorSetup(
+  mfaToken: string,
+  code: string,
+): Promise<SessionTokens & { recovery_codes: string[] }> {
+  const data = await publicRequest<SessionTokens & { recovery_codes: string[] }>(
+    "/auth/2fa/setup/confirm",
+    { method: "POST", body: JSON.stringify({ mfa_token: mfaToken, code }) },
+    "Could not verify code",
+  );
+  storeSession(data);
+  return data;
+}
+
+export async function fetchTwoFactorStatus(): Promise<TwoFactorStatus> {
+  const response = await authFetch(`${API_BASE_URL}/me/2fa`);
+  return response.json();
+}
+
+export async function beginTwoFactorSetup(): Promise<TwoFactorSetup> {
+  const response = await authFetch(`${API_BASE_URL}/me/2fa/setup`, { method: "POST" });
+  return response.json();
+}
+
+// Returns the recovery codes; they're only shown this once.
+export async function enableTwoFactor(code: string): Promise<string[]> {
+  const response = await authFetch(`${API_BASE_URL}/me/2fa/enable`, {
+    method: "POST",
+    body: JSON.stringify({ code }),
+  });
+  const data = await response.json();
+  return data.recovery_codes;
+}
+
+export async function disableTwoFactor(code: string): Promise<void> {
+  await authFetch(`${API_BASE_URL}/me/2fa/disable`, {
+    method: "POST",
+    body: JSON.stringify({ code }),
+  });
+}
+
+export async function regenerateRecoveryCodes(code: string): Promise<string[]> {
+  const response = await authFetch(`${API_BASE_URL}/me/2fa/recovery-codes`, {
+    method: "POST",
+    body: JSON.stringify({ code }),
+  });
+  const data = await response.json();
+  return data.recovery_codes;
+}
+
+// Sign-in with Google, LinkedIn and GitHub. The provider redirects back to
+// /auth/callback/:provider; the state is kept for this tab so a callback we didn't start
+// (login CSRF) is rejected.
+export type AuthProvider = "google" | "linkedin" | "github";
+
+export interface LinkedIdentity {
+  id: string;
+  provider: AuthProvider;
+  email: string;
+  last_used_at: string;
+  created_at: string;
+}
+
+const OAUTH_STATE_KEY = "oauth_state";
+const OAUTH_LINKING_KEY = "oauth_linking"; // Set while linking an account from Settings
+
+export async function fetchAuthProviders(): Promise<AuthProvider[]> {
+  try {
+    const data = await publicRequest<{ items: AuthProvider[] }>("/auth/providers", { method: "GET" }, "");
+    return data.items;
+  } catch {
+    return [];
+  }
+}
+
+// Sends the browser to the provider.
+export async function startOAuth(provider: AuthProvider): Promise<void> {
+  const data = await publicRequest<{ url: string; state: string }>(
+    `/auth/oauth/${provider}`,
+    { method: "GET" },
+    "Could not start sign-in",
+  );
+  sessionStorage.setItem(OAUTH_STATE_KEY, data.state);
+  sessionStorage.removeItem(OAUTH_LINKING_KEY);
+  window.location.assign(data.url);
+}
+
+// Like startOAuth, but the provider account is added to the signed-in user.
+export async function linkIdentity(provider: AuthProvider): Promise<void> {
+  const response = await authFetch(`${API_BASE_URL}/me/identities/${provider}`, { method: "POST" });
+  const data = await response.json();
+  sessionStorage.setItem(OAUTH_STATE_KEY, data.state);
+  sessionStorage.setItem(OAUTH_LINKING_KEY, "1");
+  window.location.assign(data.url);
+}
+
+export type OAuthResult =
+  | { linked: true; provider: AuthProvider }
+  | (TwoFactorChallenge & { linked?: false })
+  | (SessionTokens & { linked?: false; new_account: boolean });
+
+// Finishes a sign-in or link with the code the provider sent back. Links are finished
+// as the signed-in user, so the server can check they're the one who started it.
+export async function completeOAuth(provider: AuthProvider, code: string, state: string): Promise<OAuthResult> {
+  const expected = sessionStorage.getItem(OAUTH_STATE_KEY);
+  const linking = sessionStorage.getItem(OAUTH_LINKING_KEY) === "1";
+  sessionStorage.removeItem(OAUTH_STATE_KEY);
+  sessionStorage.removeItem(OAUTH_LINKING_KEY);
+  if (!expected || expected !== state) {
+    throw new Error("This sign-in wasn't started here, please try again");
+  }
+  if (linking) {
+    const response = await authFetch(`${API_BASE_URL}/me/identities/${provider}/callback`, {
+      method: "POST",
+      body: JSON.stringify({ code, state }),
+    });
+    return response.json();
+  }
+  const data = await publicRequest<OAuthResult>(
+    `/auth/oauth/${provider}/callback`,
+    { method: "POST", body: JSON.stringify({ code, state }) },
+    "Could not sign in",
+  );
+  if (!data.linked && !isTwoFactorChallenge(data)) {
+    storeSession(data);
+  }
+  return data;
+}
+
+export async function fetchIdentities(): Promise<{ items: LinkedIdentity[]; providers: AuthProvider[] }> {
+  const response = await authFetch(`${API_BASE_URL}/me/identities`);
+  return response.json();
+}
+
+export async function unlinkIdentity(provider: AuthProvider): Promise<void> {
+  await authFetch(`${API_BASE_URL}/me/identities/${provider}`, { method: "DELETE" });
+}
+
 // fetch all businesses
 export async function fetchBusinesses(
   limit: number,
@@ -96,6 +383,29 @@ export async function fetchBusiness(id: string): Promise<Business> {
   return response.json();
 }
 
+export interface HealthHistoryPoint {
+  bucket: string;
+  open: number;
+  close: number;
+  min: number;
+  max: number;
+  average: number;
+}
+
+// fetch daily/weekly health score rollups for trend charts
+export async function fetchHealthHistory(
+  id: string,
+  interval: "daily" | "weekly" = "daily",
+  days = 90,
+): Promise<HealthHistoryPoint[]> {
+  const response = await fetch(
+    `${API_BASE_URL}/businesses/${id}/health/history?interval=${interval}&days=${days}`,
+  );
+  if (!response.ok) throw new Error("Failed to fetch health history");
+  const data = await response.json();
+  return data.points || [];
+}
+
 export async function trackView(id: string): Promise<void> {
   await fetch(`${API_BASE_URL}/businesses/${id}/view`, {
     method: "POST",
@@ -117,30 +427,73 @@ export async function submitPlatformInquiry(data: any): Promise<void> {
   if (!response.ok) throw new Error("Failed to send inquiry");
 }
 
-export async function fetchComments(businessId: string): Promise<Comment[]> {
+export async function fetchCommentPage(
+  businessId: string,
+  sort: "newest" | "top" = "newest",
+  limit = 20,
+  offset = 0,
+): Promise<CommentPage> {
   const response = await fetch(
-    `${API_BASE_URL}/businesses/${businessId}/comments`,
+    `${API_BASE_URL}/businesses/${businessId}/comments?sort=${sort}&limit=${limit}&offset=${offset}`,
   );
   if (!response.ok) throw new Error("Failed to fetch comments");
   return response.json();
 }
 
+// fetch the first page of top-level comments (replies are nested under each)
+export async function fetchComments(businessId: string): Promise<Comment[]> {
+  const page = await fetchCommentPage(businessId);
+  return page.comments;
+}
+
 export async function addComment(
   businessId: string,
   content: string,
+  parentId?: string,
 ): Promise<void> {
   await authFetch(`${API_BASE_URL}/businesses/${businessId}/comment`, {
     method: "POST",
+    body: JSON.stringify({ content, parent_id: parentId }),
+  });
+}
+
+export async function editComment(id: string, content: string): Promise<void> {
+  await authFetch(`${API_BASE_URL}/comments/${id}`, {
+    method: "PATCH",
     body: JSON.stringify({ content }),
   });
 }
 
+export async function deleteComment(id: string): Promise<void> {
+  await authFetch(`${API_BASE_URL}/comments/${id}`, {
+    method: "DELETE",
+  });
+}
+
 export async function likeBusiness(businessId: string): Promise<void> {
   await authFetch(`${API_BASE_URL}/businesses/${businessId}/like`, {
     method: "POST",
   });
 }
 
+export type ReactionType = "like" | "inspiring" | "want_to_partner";
+
+export async function setReaction(
+  businessId: string,
+  type: ReactionType,
+): Promise<void> {
+  await authFetch(`${API_BASE_URL}/businesses/${businessId}/reaction`, {
+    method: "PUT",
+    body: JSON.stringify({ type }),
+  });
+}
+
+export async function unlikeBusiness(businessId: string): Promise<void> {
+  await authFetch(`${API_BASE_URL}/businesses/${businessId}/reaction`, {
+    method: "DELETE",
+  });
+}
+
 export async function submitInquiry(
   businessId: string,
   subject: string,
@@ -179,19 +532,114 @@ export async function sendChatMessage(
   return response.json();
 }
 
-export async function subscribeNewsletter(email: string): Promise<void> {
-  const response = await fetch(`${API_BASE_URL}/newsletter/subscribe`, {
-    method: "POST",
-    headers: {
-      "Content-Type": "application/json",
-    },
-    body: JSON.stringify({ email }),
-  });
+export type NewsletterTopic = "events" | "news" | "founder_stories";
 
+export interface NewsletterSubscriber {
+  id: string;
+  email: string;
+  topics: NewsletterTopic[];
+  locale: string;
+  confirmed_at: string | null;
+  created_at: string;
+  updated_at: string;
+}
+
+// Unauthenticated JSON request; token-based links from emails go through here.
+async function publicRequest<T>(path: string, init: RequestInit, fallback: string): Promise<T> {
+  const response = await fetch(`${API_BASE_URL}${path}`, {
+    ...init,
+    headers: { "Content-Type": "application/json" },
+  });
   if (!response.ok) {
     const error = await response.json().catch(() => ({}));
-    throw new Error(error.error || "Failed to subscribe to newsletter");
+    throw new Error(error.error || fallback);
   }
+  return response.json();
+}
+
+// Starts a double opt-in: the address only joins the list once the emailed link is opened.
+export async function subscribeNewsletter(
+  email: string,
+  topics?: NewsletterTopic[],
+): Promise<void> {
+  await publicRequest(
+    "/newsletter/subscribe",
+    { method: "POST", body: JSON.stringify({ email, topics }) },
+    "Failed to subscribe to newsletter",
+  );
+}
+
+// Confirms a subscription from the emailed link. The returned token manages the subscription.
+export async function confirmNewsletter(
+  token: string,
+): Promise<{ subscriber: NewsletterSubscriber; token: string }> {
+  return publicRequest(
+    "/newsletter/confirm",
+    { method: "POST", body: JSON.stringify({ token }) },
+    "Could not confirm subscription",
+  );
+}
+
+export async function fetchNewsletterPreferences(
+  token: string,
+): Promise<{ subscriber: NewsletterSubscriber; topics: NewsletterTopic[] }> {
+  return publicRequest(
+    `/newsletter/preferences?token=${encodeURIComponent(token)}`,
+    { method: "GET" },
+    "Could not load preferences",
+  );
+}
+
+export async function updateNewsletterPreferences(
+  token: string,
+  topics: NewsletterTopic[],
+): Promise<NewsletterSubscriber> {
+  const data = await publicRequest<{ subscriber: NewsletterSubscriber }>(
+    "/newsletter/preferences",
+    { method: "PUT", body: JSON.stringify({ token, topics }) },
+    "Could not save preferences",
+  );
+  return data.subscriber;
+}
+
+export async function unsubscribeNewsletter(token: string): Promise<void> {
+  await publicRequest(
+    "/newsletter/unsubscribe",
+    { method: "POST", body: JSON.stringify({ token }) },
+    "Could not unsubscribe",
+  );
+}
+
+// Password reset and email verification. The emailed links land on /reset-password
+// and /verify-email with a single-use token.
+export async function forgotPassword(email: string): Promise<string> {
+  const data = await publicRequest<{ message: string }>(
+    "/auth/forgot-password",
+    { method: "POST", body: JSON.stringify({ email }) },
+    "Could not send reset link",
+  );
+  return data.message;
+}
+
+export async function resetPassword(token: string, password: string): Promise<string> {
+  const data = await publicRequest<{ message: string }>(
+    "/auth/reset-password",
+    { method: "POST", body: JSON.stringify({ token, password }) },
+    "Could not reset password",
+  );
+  return data.message;
+}
+
+export async function verifyEmail(token: string): Promise<void> {
+  await publicRequest(
+    "/auth/verify-email",
+    { method: "POST", body: JSON.stringify({ token }) },
+    "Could not verify email",
+  );
+}
+
+export async function resendVerification(): Promise<void> {
+  await authFetch(`${API_BASE_URL}/auth/resend-verification`, { method: "POST" });
 }
 
 // create business
@@ -267,6 +715,27 @@ export interface Meeting {
   };
 }
 
+export async function bookMeeting(
+  businessId: string,
+  meeting: Pick<Meeting, "title" | "description" | "start_time" | "end_time" | "meeting_link">,
+): Promise<Meeting> {
+  const response = await authFetch(`${API_BASE_URL}/businesses/${businessId}/meetings`, {
+    method: "POST",
+    body: JSON.stringify(meeting),
+  });
+  return response.json();
+}
+
+export async function updateMeetingStatus(
+  id: string,
+  status: "completed" | "cancelled",
+): Promise<void> {
+  await authFetch(`${API_BASE_URL}/meetings/${id}/status`, {
+    method: "PATCH",
+    body: JSON.stringify({ status }),
+  });
+}
+
 // fetch dashboard data
 export interface DashboardData {
   user: {
@@ -276,13 +745,18 @@ export interface DashboardData {
     role: string;
   };
   role: string; // admin, owner, viewer, privileged
+  member_role?: MemberRole; // The user's role in the business's team
   has_business?: boolean;
+  businesses?: PortfolioBusiness[]; // Every business the user can switch to
   business?: Business;
   stats: {
     // Owner stats
     health_score?: number;
+    health_score_delta_wow?: number; // Change vs. 7 days ago
+    health_trend?: "up" | "down" | "flat";
     profile_views?: number;
     active_inquiries?: number;
+    meetings?: number; // Also sent to viewers, who don't get the meetings themselves
     conversions?: number;
     likes?: number;
 
@@ -306,11 +780,54 @@ export interface DashboardData {
 }
 
 // fetch dashboard data
+// Users who run several businesses see the one picked in the switcher (selectBusiness).
 export async function fetchDashboardMe(): Promise<DashboardData> {
+  const businessId = localStorage.getItem("business_id");
+  if (businessId) {
+    try {
+      const response = await authFetch(`${API_BASE_URL}/dashboard/businesses/${businessId}`);
+      return response.json();
+    } catch (err) {
+      if (err instanceof Error && err.message === "UNAUTHORIZED") throw err;
+      // No longer on that business's team; fall back to the default one
+      localStorage.removeItem("business_id");
+    }
+  }
   const response = await authFetch(`${API_BASE_URL}/dashboard/me`);
   return response.json();
 }
 
+// Picks which business the dashboard shows.
+export function selectBusiness(id: string): void {
+  localStorage.setItem("business_id", id);
+}
+
+export interface PortfolioBusiness {
+  id: string;
+  name: string;
+  avatar_url: string;
+  role: MemberRole;
+  health_score: number;
+  views: number;
+  inquiries: number;
+}
+
+export interface Portfolio {
+  businesses: number;
+  total_views: number;
+  total_likes: number;
+  total_inquiries: number;
+  total_conversions: number;
+  upcoming_meetings: number;
+  average_health: number;
+}
+
+// Every business the user runs, and totals across them.
+export async function fetchPortfolio(): Promise<{ items: PortfolioBusiness[]; portfolio: Portfolio }> {
+  const response = await authFetch(`${API_BASE_URL}/dashboard/businesses`);
+  return response.json();
+}
+
 export interface AppEvent {
   id: string;
   title: string;
@@ -371,14 +888,96 @@ export interface AppNotification {
   type: string;
   is_read: boolean;
   link?: string;
+  count: number; // > 1 when repeat events were merged ("5 people liked...")
+  archived_at?: string;
   created_at: string;
+  updated_at: string;
 }
 
-export async function fetchNotifications(): Promise<AppNotification[]> {
-  const response = await authFetch(`${API_BASE_URL}/notifications`);
+export type StreamEventType = "notification" | "chat" | "inquiry";
+
+// subscribeToStream opens the live update stream. Returns a function that closes it,
+// or null when the user isn't signed in (callers should fall back to polling).
+export function subscribeToStream(
+  handlers: Partial<Record<StreamEventType, (data: any) => void>>,
+): (() => void) | null {
+  const token = localStorage.getItem("token");
+  if (!token) return null;
+
+  let source: EventSource;
+  let closed = false;
+  const open = (token: string) => {
+    source = new EventSource(
+      `${API_BASE_URL}/stream?token=${encodeURIComponent(token)}`,
+    );
+    (Object.keys(handlers) as StreamEventType[]).forEach((type) => {
+      source.addEventListener(type, (event) => {
+        handlers[type]?.(JSON.parse((event as MessageEvent).data));
+      });
+    });
+    // A rejected (e.g. expired) token closes the stream for good; refresh and reconnect.
+    source.onerror = async () => {
+      if (source.readyState !== EventSource.CLOSED || closed) return;
+      if (await refreshSession()) open(localStorage.getItem("token") || "");
+    };
+  };
+  open(token);
+  return () => {
+    closed = true;
+    source.close();
+  };
+}
+
+export interface NotificationPage {
+  items: AppNotification[];
+  next_cursor: string; // empty on the last page
+  unread_count: number;
+}
+
+export async function fetchNotificationPage(
+  params: {
+    type?: string;
+    read?: boolean;
+    archived?: boolean;
+    cursor?: string;
+    limit?: number;
+  } = {},
+): Promise<NotificationPage> {
+  const query = new URLSearchParams();
+  Object.entries(params).forEach(([key, value]) => {
+    if (value !== undefined && value !== "") query.set(key, String(value));
+  });
+  const response = await authFetch(`${API_BASE_URL}/notifications?${query}`);
   return response.json();
 }
 
+export async function fetchNotifications(): Promise<AppNotification[]> {
+  const page = await fetchNotificationPage();
+  return page.items;
+}
+
+export async function fetchUnreadNotificationCount(): Promise<number> {
+  const response = await authFetch(`${API_BASE_URL}/notifications/unread-count`);
+  const data = await response.json();
+  return data.total;
+}
+
+export async function archiveNotification(
+  id: string,
+  archived = true,
+): Promise<void> {
+  await authFetch(`${API_BASE_URL}/notifications/${id}/archive`, {
+    method: "PATCH",
+    body: JSON.stringify({ archived }),
+  });
+}
+
+export async function deleteNotification(id: string): Promise<void> {
+  await authFetch(`${API_BASE_URL}/notifications/${id}`, {
+    method: "DELETE",
+  });
+}
+
 export async function markNotificationRead(id: string): Promise<void> {
   await authFetch(`${API_BASE_URL}/notifications/${id}/read`, {
     method: "PATCH",
@@ -391,6 +990,42 @@ export async function markAllNotificationsRead(): Promise<void> {
   });
 }
 
+export interface NotificationPreference {
+  type: string;
+  channel: "in_app" | "email" | "sms" | "whatsapp";
+  enabled: boolean;
+}
+
+export interface NotificationPreferences {
+  settings: {
+    quiet_hours_start: string; // "HH:MM", empty when off
+    quiet_hours_end: string;
+    timezone: string;
+    digest_frequency: "off" | "daily" | "weekly";
+    last_digest_at?: string;
+  };
+  preferences: NotificationPreference[];
+  types: string[];
+  channels: string[];
+}
+
+export async function fetchNotificationPreferences(): Promise<NotificationPreferences> {
+  const response = await authFetch(`${API_BASE_URL}/me/notification-preferences`);
+  return response.json();
+}
+
+export async function updateNotificationPreferences(
+  update: Partial<NotificationPreferences["settings"]> & {
+    preferences?: NotificationPreference[];
+  },
+): Promise<NotificationPreferences> {
+  const response = await authFetch(`${API_BASE_URL}/me/notification-preferences`, {
+    method: "PUT",
+    body: JSON.stringify(update),
+  });
+  return response.json();
+}
+
 export interface BlogPost {
   id: string;
   title: string;
@@ -462,3 +1097,277 @@ export async function deleteBlog(slug: string): Promise<void> {
     method: "DELETE",
   });
 }
+
+// --- Newsletter issues (admin) ---
+
+export type IssueStatus = "draft" | "scheduled" | "sending" | "sent" | "cancelled";
+
+export interface IssueItem {
+  title: string;
+  summary?: string;
+  url: string;
+  image_url?: string;
+  meta?: string;
+}
+
+export interface IssueSection {
+  key: string;
+  topic: NewsletterTopic;
+  title: string;
+  items: IssueItem[];
+}
+
+export interface NewsletterIssue {
+  id: string;
+  subject: string;
+  intro: string;
+  sections: IssueSection[];
+  status: IssueStatus;
+  scheduled_at: string | null;
+  started_at?: string;
+  sent_at?: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface IssueStats {
+  recipients: number;
+  queued: number;
+  skipped: number;
+  pending: number;
+  opened: number;
+  clicked: number;
+  open_rate: number;
+  click_rate: number;
+  links: { url: string; clicks: number; unique_clicks: number }[];
+}
+
+// Drafts a new issue from the platform content published in the last sinceDays days.
+export async function composeNewsletterIssue(sinceDays = 7): Promise<NewsletterIssue> {
+  const response = await authFetch(`${API_BASE_URL}/admin/newsletter/issues`, {
+    method: "POST",
+    body: JSON.stringify({ since_days: sinceDays }),
+  });
+  return response.json();
+}
+
+export async function fetchNewsletterIssues(
+  status?: IssueStatus,
+): Promise<{ items: NewsletterIssue[]; total: number }> {
+  const query = status ? `?status=${status}` : "";
+  const response = await authFetch(`${API_BASE_URL}/admin/newsletter/issues${query}`);
+  return response.json();
+}
+
+export async function fetchNewsletterIssue(id: string): Promise<NewsletterIssue> {
+  const response = await authFetch(`${API_BASE_URL}/admin/newsletter/issues/${id}`);
+  return response.json();
+}
+
+export async function updateNewsletterIssue(
+  id: string,
+  data: Pick<NewsletterIssue, "subject" | "intro" | "sections">,
+): Promise<NewsletterIssue> {
+  const response = await authFetch(`${API_BASE_URL}/admin/newsletter/issues/${id}`, {
+    method: "PUT",
+    body: JSON.stringify(data),
+  });
+  return response.json();
+}
+
+export async function deleteNewsletterIssue(id: string): Promise<void> {
+  await authFetch(`${API_BASE_URL}/admin/newsletter/issues/${id}`, { method: "DELETE" });
+}
+
+// Returns the rendered HTML of the issue, for showing in an iframe.
+export async function previewNewsletterIssue(id: string): Promise<string> {
+  const response = await authFetch(`${API_BASE_URL}/admin/newsletter/issues/${id}/preview`);
+  return response.text();
+}
+
+export async function sendTestNewsletterIssue(id: string, email: string): Promise<void> {
+  await authFetch(`${API_BASE_URL}/admin/newsletter/issues/${id}/test`, {
+    method: "POST",
+    body: JSON.stringify({ email }),
+  });
+}
+
+// Schedules the issue; omit sendAt to send right away.
+export async function scheduleNewsletterIssue(id: string, sendAt?: string): Promise<void> {
+  await authFetch(`${API_BASE_URL}/admin/newsletter/issues/${id}/schedule`, {
+    method: "POST",
+    body: JSON.stringify({ send_at: sendAt }),
+  });
+}
+
+export async function cancelNewsletterIssue(id: string): Promise<void> {
+  await authFetch(`${API_BASE_URL}/admin/newsletter/issues/${id}/cancel`, { method: "POST" });
+}
+
+export async function fetchNewsletterIssueStats(id: string): Promise<IssueStats> {
+  const response = await authFetch(`${API_BASE_URL}/admin/newsletter/issues/${id}/stats`);
+  return response.json();
+}
+
+// --- Roles & permissions (admin) ---
+// A permission like "business:update" applies to everything; "business:update:own"
+// only to the user's own resources.
+
+export interface PermissionInfo {
+  name: string;
+  description: string;
+  ownable: boolean;
+}
+
+export interface Role {
+  name: string;
+  description: string;
+  permissions: string[];
+  system: boolean;
+  users: number;
+  created_at: string;
+  updated_at: string;
+}
+
+export async function fetchRoles(): Promise<{ items: Role[]; permissions: PermissionInfo[] }> {
+  const response = await authFetch(`${API_BASE_URL}/admin/roles`);
+  return response.json();
+}
+
+export async function createRole(
+  role: Pick<Role, "name" | "description" | "permissions">,
+): Promise<Role> {
+  const response = await authFetch(`${API_BASE_URL}/admin/roles`, {
+    method: "POST",
+    body: JSON.stringify(role),
+  });
+  return response.json();
+}
+
+export async function updateRole(
+  name: string,
+  role: Pick<Role, "description" | "permissions">,
+): Promise<Role> {
+  const response = await authFetch(`${API_BASE_URL}/admin/roles/${name}`, {
+    method: "PUT",
+    body: JSON.stringify(role),
+  });
+  return response.json();
+}
+
+export async function deleteRole(name: string): Promise<void> {
+  await authFetch(`${API_BASE_URL}/admin/roles/${name}`, { method: "DELETE" });
+}
+
+export async function changeUserRole(userId: string, role: string): Promise<void> {
+  await authFetch(`${API_BASE_URL}/admin/users/${userId}/role`, {
+    method: "PATCH",
+    body: JSON.stringify({ role }),
+  });
+}
+
+// --- Business teams ---
+// Owners and managers invite people by email and set their role in the team.
+
+export type MemberRole = "owner" | "manager" | "editor" | "viewer";
+
+export interface BusinessMember {
+  id: string;
+  business_id: string;
+  user_id: string;
+  role: MemberRole;
+  created_at: string;
+  user: { id: string; name: string; email: string };
+}
+
+export interface BusinessInvitation {
+  id: string;
+  business_id: string;
+  email: string;
+  role: MemberRole;
+  expires_at: string;
+  created_at: string;
+}
+
+export interface InvitationPreview {
+  business_id: string;
+  business_name: string;
+  email: string;
+  role: MemberRole;
+  expires_at: string;
+}
+
+// Returns the team and the caller's own role in it.
+export async function fetchBusinessMembers(
+  businessId: string,
+): Promise<{ items: BusinessMember[]; role: MemberRole | "" }> {
+  const response = await authFetch(`${API_BASE_URL}/businesses/${businessId}/members`);
+  return response.json();
+}
+
+export async function updateBusinessMember(
+  businessId: string,
+  userId: string,
+  role: Exclude<MemberRole, "owner">,
+): Promise<BusinessMember> {
+  const response = await authFetch(`${API_BASE_URL}/businesses/${businessId}/members/${userId}`, {
+    method: "PATCH",
+    body: JSON.stringify({ role }),
+  });
+  return response.json();
+}
+
+// Removes a member, or leaves the team when userId is the caller's own.
+export async function removeBusinessMember(businessId: string, userId: string): Promise<void> {
+  await authFetch(`${API_BASE_URL}/businesses/${businessId}/members/${userId}`, { method: "DELETE" });
+}
+
+export async function transferBusinessOwnership(businessId: string, userId: string): Promise<void> {
+  await authFetch(`${API_BASE_URL}/businesses/${businessId}/transfer`, {
+    method: "POST",
+    body: JSON.stringify({ user_id: userId }),
+  });
+}
+
+export async function fetchBusinessInvitations(businessId: string): Promise<BusinessInvitation[]> {
+  const response = await authFetch(`${API_BASE_URL}/businesses/${businessId}/invitations`);
+  const data = await response.json();
+  return data.items;
+}
+
+export async function inviteBusinessMember(
+  businessId: string,
+  email: string,
+  role: Exclude<MemberRole, "owner">,
+): Promise<BusinessInvitation> {
+  const response = await authFetch(`${API_BASE_URL}/businesses/${businessId}/invitations`, {
+    method: "POST",
+    body: JSON.stringify({ email, role }),
+  });
+  return response.json();
+}
+
+export async function revokeBusinessInvitation(businessId: string, invitationId: string): Promise<void> {
+  await authFetch(`${API_BASE_URL}/businesses/${businessId}/invitations/${invitationId}`, {
+    method: "DELETE",
+  });
+}
+
+export async function fetchInvitation(token: string): Promise<InvitationPreview> {
+  return publicRequest(
+    `/invitations/${encodeURIComponent(token)}`,
+    { method: "GET" },
+    "This invitation is invalid or has expired",
+  );
+}
+
+// Joins the team. The account's role may change so it can open the dashboard.
+export async function acceptInvitation(token: string): Promise<BusinessMember> {
+  const response = await authFetch(`${API_BASE_URL}/invitations/accept`, {
+    method: "POST",
+    body: JSON.stringify({ token }),
+  });
+  const data = await response.json();
+  localStorage.setItem("role", data.role);
+  return data.member;
+}
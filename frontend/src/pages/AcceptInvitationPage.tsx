@@ -0,0 +1,115 @@
+import { useEffect, useRef, useState } from "react";
+import { useSearchParams, Link, useNavigate } from "react-router-dom";
+import { motion } from "framer-motion";
+import { Users, Loader2 } from "lucide-react";
+import { fetchInvitation, acceptInvitation, InvitationPreview } from "../lib/api";
+
+type View = "loading" | "ready" | "joined" | "error";
+
+/**
+ * AcceptInvitationPage - landing page for the link in a team invitation email (?token=).
+ * Shows which business the invitation is for; signed-in users with the invited email can join.
+ */
+const AcceptInvitationPage = () => {
+  const [params] = useSearchParams();
+  const navigate = useNavigate();
+  const token = params.get("token") || "";
+  const [view, setView] = useState<View>("loading");
+  const [invitation, setInvitation] = useState<InvitationPreview | null>(null);
+  const [error, setError] = useState("");
+  const [joining, setJoining] = useState(false);
+  const isLoggedIn = !!localStorage.getItem("token");
+  const loaded = useRef(false);
+
+  useEffect(() => {
+    if (loaded.current) return;
+    loaded.current = true;
+    fetchInvitation(token)
+      .then((data) => {
+        setInvitation(data);
+        setView("ready");
+      })
+      .catch((err) => {
+        setError(err instanceof Error ? err.message : "Could not load invitation");
+        setView("error");
+      });
+  }, [token]);
+
+  const handleAccept = async () => {
+    setJoining(true);
+    try {
+      await acceptInvitation(token);
+      setView("joined");
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Could not accept invitation");
+      setView("error");
+    } finally {
+      setJoining(false);
+    }
+  };
+
+  return (
+    <div className="bg-bg-primary min-h-screen pt-24">
+      <section className="py-20 px-6 max-w-2xl mx-auto">
+        <motion.div initial={{ opacity: 0, y: 20 }} animate={{ opacity: 1, y: 0 }} className="mb-12">
+          <div className="flex items-center gap-3 text-accent-gold mb-6">
+            <Users size={20} />
+            <span className="text-[10px] font-bold uppercase tracking-[0.3em]">Team</span>
+          </div>
+          <h1 className="text-4xl md:text-5xl font-heading font-bold text-white tracking-tighter">
+            TEAM <span className="text-accent-gold italic-serif lowercase">invitation</span>
+          </h1>
+        </motion.div>
+
+        {view === "loading" && (
+          <div className="flex items-center gap-3 text-white/50">
+            <Loader2 size={16} className="animate-spin" /> Loading…
+          </div>
+        )}
+
+        {view === "ready" && invitation && (
+          <div className="p-8 border-l border-accent-gold bg-accent-gold/5">
+            <p className="text-white/70 mb-2">
+              You've been invited to join <span className="text-white font-bold">{invitation.business_name}</span> as{" "}
+              {invitation.role}.
+            </p>
+            <p className="text-white/40 text-sm mb-6">The invitation was sent to {invitation.email}.</p>
+            {isLoggedIn ? (
+              <button
+                onClick={handleAccept}
+                disabled={joining}
+                className="text-accent-gold text-sm font-bold uppercase tracking-widest disabled:opacity-50"
+              >
+                {joining ? "Joining…" : "Join the team"}
+              </button>
+            ) : (
+              <Link to="/auth" className="text-accent-gold text-sm font-bold uppercase tracking-widest">
+                Sign in with {invitation.email}, then open the link again
+              </Link>
+            )}
+          </div>
+        )}
+
+        {view === "joined" && (
+          <div className="p-8 border-l border-accent-gold bg-accent-gold/5">
+            <p className="text-white/70 mb-4">You're on the team.</p>
+            <button
+              onClick={() => navigate("/dashboard")}
+              className="text-accent-gold text-sm font-bold uppercase tracking-widest"
+            >
+              Open the dashboard
+            </button>
+          </div>
+        )}
+
+        {view === "error" && (
+          <div className="p-8 border-l border-red-500 bg-red-500/5">
+            <p className="text-white/70">{error}</p>
+          </div>
+        )}
+      </section>
+    </div>
+  );
+};
+
+export default AcceptInvitationPage;
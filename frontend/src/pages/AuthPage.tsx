@@ -1,7 +1,22 @@
-import { useState } from "react";
+import { useEffect, useState } from "react";
 import { motion, AnimatePresence } from "framer-motion";
-import { useNavigate } from "react-router-dom";
+import { useNavigate, Link } from "react-router-dom";
 import { Mail, Lock, ArrowRight, Eye, EyeOff } from "lucide-react";
+import {
+  AuthProvider,
+  TwoFactorChallenge,
+  fetchAuthProviders,
+  isTwoFactorChallenge,
+  startOAuth,
+  storeSession,
+} from "../lib/api";
+import TwoFactorStep from "../components/TwoFactorStep";
+
+const providerLabels: Record<AuthProvider, string> = {
+  google: "Google",
+  linkedin: "LinkedIn",
+  github: "GitHub",
+};
 
 const AuthPage = () => {
   const [isLogin, setIsLogin] = useState(true);
@@ -11,8 +26,29 @@ const AuthPage = () => {
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState("");
   const [message, setMessage] = useState("");
+  const [providers, setProviders] = useState<AuthProvider[]>([]);
+  const [challenge, setChallenge] = useState<TwoFactorChallenge | null>(null);
   const navigate = useNavigate();
 
+  useEffect(() => {
+    fetchAuthProviders().then(setProviders);
+  }, []);
+
+  const finishSignIn = () => {
+    navigate("/");
+    window.location.reload(); // Quick refresh to update Navbar state
+  };
+
+  const handleProvider = async (provider: AuthProvider) => {
+    setError("");
+    setMessage("");
+    try {
+      await startOAuth(provider);
+    } catch (err: any) {
+      setError(err.message);
+    }
+  };
+
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault();
     setLoading(true);
@@ -35,11 +71,11 @@ const AuthPage = () => {
         throw new Error(data.error || "Something went wrong");
       }
 
-      if (isLogin) {
-        localStorage.setItem("token", data.token);
-        localStorage.setItem("role", data.role);
-        navigate("/");
-        window.location.reload(); // Quick refresh to update Navbar state
+      if (isLogin && isTwoFactorChallenge(data)) {
+        setChallenge(data);
+      } else if (isLogin) {
+        storeSession(data);
+        finishSignIn();
       } else {
         setMessage(data.message);
         setIsLogin(true); // Switch to login after registration
@@ -77,96 +113,130 @@ const AuthPage = () => {
           {/* Accent Bar */}
           <div className="absolute top-0 left-0 w-full h-1 bg-linear-to-r from-transparent via-accent-gold/50 to-transparent opacity-0 group-hover:opacity-100 transition-opacity duration-700" />
 
-          <form onSubmit={handleSubmit} className="space-y-6">
-            <AnimatePresence mode="wait">
-              {error && (
-                <motion.div
-                  initial={{ opacity: 0, height: 0 }}
-                  animate={{ opacity: 1, height: "auto" }}
-                  exit={{ opacity: 0, height: 0 }}
-                  className="bg-red-500/10 border border-red-500/20 p-4 text-red-400 text-xs font-bold uppercase tracking-widest text-center"
-                >
-                  {error}
-                </motion.div>
-              )}
-              {message && (
-                <motion.div
-                  initial={{ opacity: 0, height: 0 }}
-                  animate={{ opacity: 1, height: "auto" }}
-                  exit={{ opacity: 0, height: 0 }}
-                  className="bg-accent-gold/10 border border-accent-gold/20 p-4 text-accent-gold text-xs font-bold uppercase tracking-widest text-center"
-                >
-                  {message}
-                </motion.div>
-              )}
-            </AnimatePresence>
-
-            <div className="space-y-4">
-              <div className="space-y-2">
-                <label className="text-[10px] font-bold text-white/40 uppercase tracking-[0.2em] ml-1">
-                  Email Address
-                </label>
-                <div className="relative">
-                  <Mail className="absolute left-4 top-1/2 -translate-y-1/2 w-4 h-4 text-white/20" />
-                  <input
-                    type="email"
-                    value={email}
-                    onChange={(e) => setEmail(e.target.value)}
-                    required
-                    className="w-full bg-slate-900/50 border border-white/5 px-12 py-4 text-white focus:outline-none focus:border-accent-gold/50 transition-all font-light"
-                    placeholder="name@company.com"
-                  />
+          {challenge ? (
+            <TwoFactorStep challenge={challenge} onSignedIn={finishSignIn} />
+          ) : (
+            <form onSubmit={handleSubmit} className="space-y-6">
+              <AnimatePresence mode="wait">
+                {error && (
+                  <motion.div
+                    initial={{ opacity: 0, height: 0 }}
+                    animate={{ opacity: 1, height: "auto" }}
+                    exit={{ opacity: 0, height: 0 }}
+                    className="bg-red-500/10 border border-red-500/20 p-4 text-red-400 text-xs font-bold uppercase tracking-widest text-center"
+                  >
+                    {error}
+                  </motion.div>
+                )}
+                {message && (
+                  <motion.div
+                    initial={{ opacity: 0, height: 0 }}
+                    animate={{ opacity: 1, height: "auto" }}
+                    exit={{ opacity: 0, height: 0 }}
+                    className="bg-accent-gold/10 border border-accent-gold/20 p-4 text-accent-gold text-xs font-bold uppercase tracking-widest text-center"
+                  >
+                    {message}
+                  </motion.div>
+                )}
+              </AnimatePresence>
+
+              <div className="space-y-4">
+                <div className="space-y-2">
+                  <label className="text-[10px] font-bold text-white/40 uppercase tracking-[0.2em] ml-1">
+                    Email Address
+                  </label>
+                  <div className="relative">
+                    <Mail className="absolute left-4 top-1/2 -translate-y-1/2 w-4 h-4 text-white/20" />
+                    <input
+                      type="email"
+                      value={email}
+                      onChange={(e) => setEmail(e.target.value)}
+                      required
+                      className="w-full bg-slate-900/50 border border-white/5 px-12 py-4 text-white focus:outline-none focus:border-accent-gold/50 transition-all font-light"
+                      placeholder="name@company.com"
+                    />
+                  </div>
                 </div>
-              </div>
 
-              <div className="space-y-2">
-                <label className="text-[10px] font-bold text-white/40 uppercase tracking-[0.2em] ml-1">
-                  Password
-                </label>
-                <div className="relative">
-                  <Lock className="absolute left-4 top-1/2 -translate-y-1/2 w-4 h-4 text-white/20" />
-                  <input
-                    type={showPassword ? "text" : "password"}
-                    value={password}
-                    onChange={(e) => setPassword(e.target.value)}
-                    required
-                    className="w-full bg-slate-900/50 border border-white/5 px-12 py-4 text-white focus:outline-none focus:border-accent-gold/50 transition-all font-light"
-                    placeholder="••••••••"
-                  />
-                  <button
-                    type="button"
-                    onClick={() => setShowPassword(!showPassword)}
-                    className="absolute right-4 top-1/2 -translate-y-1/2 text-white/20 hover:text-white/60 transition-colors"
-                  >
-                    {showPassword ? (
-                      <EyeOff className="w-4 h-4" />
-                    ) : (
-                      <Eye className="w-4 h-4" />
-                    )}
-                  </button>
+                <div className="space-y-2">
+                  <label className="text-[10px] font-bold text-white/40 uppercase tracking-[0.2em] ml-1">
+                    Password
+                  </label>
+                  <div className="relative">
+                    <Lock className="absolute left-4 top-1/2 -translate-y-1/2 w-4 h-4 text-white/20" />
+                    <input
+                      type={showPassword ? "text" : "password"}
+                      value={password}
+                      onChange={(e) => setPassword(e.target.value)}
+                      required
+                      className="w-full bg-slate-900/50 border border-white/5 px-12 py-4 text-white focus:outline-none focus:border-accent-gold/50 transition-all font-light"
+                      placeholder="••••••••"
+                    />
+                    <button
+                      type="button"
+                      onClick={() => setShowPassword(!showPassword)}
+                      className="absolute right-4 top-1/2 -translate-y-1/2 text-white/20 hover:text-white/60 transition-colors"
+                    >
+                      {showPassword ? (
+                        <EyeOff className="w-4 h-4" />
+                      ) : (
+                        <Eye className="w-4 h-4" />
+                      )}
+                    </button>
+                  </div>
                 </div>
+                {isLogin && (
+                  <div className="text-right">
+                    <Link
+                      to="/reset-password"
+                      className="text-[10px] font-bold uppercase tracking-widest text-white/30 hover:text-accent-gold transition-colors"
+                    >
+                      Forgot password?
+                    </Link>
+                  </div>
+                )}
               </div>
-            </div>
 
-            <button
-              type="submit"
-              disabled={loading}
-              className="w-full btn-primary py-4 group relative overflow-hidden"
-            >
-              <div className="relative z-10 flex items-center justify-center gap-3">
-                {loading ? (
-                  <div className="w-5 h-5 border-2 border-white/20 border-t-white rounded-full animate-spin" />
-                ) : (
-                  <>
-                    <span className="text-[10px] uppercase font-bold tracking-[0.3em]">
-                      {isLogin ? "Authenticate" : "Create Account"}
-                    </span>
-                    <ArrowRight className="w-4 h-4 group-hover:translate-x-1 transition-transform" />
-                  </>
-                )}
+              <button
+                type="submit"
+                disabled={loading}
+                className="w-full btn-primary py-4 group relative overflow-hidden"
+              >
+                <div className="relative z-10 flex items-center justify-center gap-3">
+                  {loading ? (
+                    <div className="w-5 h-5 border-2 border-white/20 border-t-white rounded-full animate-spin" />
+                  ) : (
+                    <>
+                      <span className="text-[10px] uppercase font-bold tracking-[0.3em]">
+                        {isLogin ? "Authenticate" : "Create Account"}
+                      </span>
+                      <ArrowRight className="w-4 h-4 group-hover:translate-x-1 transition-transform" />
+                    </>
+                  )}
+                </div>
+              </button>
+            </form>
+          )}
+
+          {!challenge && providers.length > 0 && (
+            <div className="mt-8 space-y-3">
+              <div className="flex items-center gap-4 text-[10px] font-bold uppercase tracking-[0.2em] text-white/20">
+                <div className="flex-1 h-px bg-white/5" />
+                or
+                <div className="flex-1 h-px bg-white/5" />
               </div>
-            </button>
-          </form>
+              {providers.map((provider) => (
+                <button
+                  key={provider}
+                  type="button"
+                  onClick={() => handleProvider(provider)}
+                  className="w-full border border-white/10 py-3 text-[10px] uppercase font-bold tracking-[0.3em] text-white/60 hover:text-white hover:border-accent-gold/50 transition-all"
+                >
+                  Continue with {providerLabels[provider]}
+                </button>
+              ))}
+            </div>
+          )}
 
           <div className="mt-8 pt-8 border-t border-white/5 text-center">
             <button
@@ -10,6 +10,7 @@ const ContactPage = () => {
     email: "",
     subject: "",
     message: "",
+    website: "",
   });
   const [isSubmitting, setIsSubmitting] = useState(false);
   const [toast, setToast] = useState<{
@@ -32,7 +33,7 @@ const ContactPage = () => {
         message: "Message dispatched. We will synchronize soon.",
         type: "success",
       });
-      setFormData({ name: "", email: "", subject: "", message: "" });
+      setFormData({ name: "", email: "", subject: "", message: "", website: "" });
     } catch (err) {
       setToast({
         isVisible: true,
@@ -116,6 +117,19 @@ const ContactPage = () => {
           {/* Right: Form */}
           <div className="bg-white/2 border border-white/5 p-8 md:p-12">
             <form onSubmit={handleSubmit} className="space-y-8">
+              {/* Honeypot: hidden from people, bots fill it in and get dropped */}
+              <input
+                type="text"
+                name="website"
+                tabIndex={-1}
+                autoComplete="off"
+                aria-hidden="true"
+                value={formData.website}
+                onChange={(e) =>
+                  setFormData({ ...formData, website: e.target.value })
+                }
+                className="hidden"
+              />
               <div className="grid md:grid-cols-2 gap-8">
                 <div className="space-y-2">
                   <label className="text-[10px] uppercase font-bold text-white/40 tracking-widest">
@@ -10,7 +10,12 @@ import {
   ArrowUpRight,
   Search,
 } from "lucide-react";
-import { fetchNetworkFeed, sendChatMessage, ChatMessage } from "../lib/api";
+import {
+  fetchNetworkFeed,
+  sendChatMessage,
+  subscribeToStream,
+  ChatMessage,
+} from "../lib/api";
 import AuthModal from "../components/AuthModal";
 
 const NetworkPage = () => {
@@ -34,7 +39,14 @@ const NetworkPage = () => {
 
   useEffect(() => {
     loadFeed();
-    // Refresh every 10 seconds for a "live" feel
+    // Signed-in users get new messages pushed; everyone else polls every 10 seconds
+    const close = subscribeToStream({
+      chat: (msg: ChatMessage) =>
+        setMessages((prev) =>
+          prev.some((m) => m.id === msg.id) ? prev : [msg, ...prev],
+        ),
+    });
+    if (close) return close;
     const interval = setInterval(loadFeed, 10000);
     return () => clearInterval(interval);
   }, []);
@@ -0,0 +1,192 @@
+import { useEffect, useState } from "react";
+import { useSearchParams, Link } from "react-router-dom";
+import { motion } from "framer-motion";
+import { Mail, Loader2, Check } from "lucide-react";
+import {
+  confirmNewsletter,
+  fetchNewsletterPreferences,
+  updateNewsletterPreferences,
+  unsubscribeNewsletter,
+  NewsletterSubscriber,
+  NewsletterTopic,
+} from "../lib/api";
+
+const TOPIC_LABELS: Record<NewsletterTopic, string> = {
+  events: "Events & summits",
+  news: "Ecosystem news",
+  founder_stories: "Founder stories",
+};
+
+type View = "loading" | "preferences" | "unsubscribed" | "error";
+
+/**
+ * NewsletterPage - landing page for the links in newsletter emails.
+ * ?action=confirm&token= confirms a subscription; ?token= manages topics;
+ * ?action=unsubscribe&token= asks before unsubscribing.
+ */
+const NewsletterPage = () => {
+  const [params] = useSearchParams();
+  const action = params.get("action");
+  const [token, setToken] = useState(params.get("token") || "");
+  const [view, setView] = useState<View>("loading");
+  const [subscriber, setSubscriber] = useState<NewsletterSubscriber | null>(null);
+  const [topics, setTopics] = useState<NewsletterTopic[]>([]);
+  const [notice, setNotice] = useState("");
+  const [error, setError] = useState("");
+  const [isSaving, setIsSaving] = useState(false);
+
+  useEffect(() => {
+    const load = async () => {
+      try {
+        let manageToken = params.get("token") || "";
+        if (action === "confirm") {
+          const confirmed = await confirmNewsletter(manageToken);
+          manageToken = confirmed.token;
+          setToken(manageToken);
+          setNotice("You're subscribed. Welcome to the dispatch!");
+        } else if (action === "unsubscribe") {
+          setNotice("Unsubscribe from all Spotlight Africa newsletters?");
+        }
+        const prefs = await fetchNewsletterPreferences(manageToken);
+        setSubscriber(prefs.subscriber);
+        setTopics(prefs.subscriber.topics?.length ? prefs.subscriber.topics : prefs.topics);
+        setView("preferences");
+      } catch (err) {
+        setError(err instanceof Error ? err.message : "Something went wrong");
+        setView("error");
+      }
+    };
+    load();
+  }, [action, params]);
+
+  const toggleTopic = (topic: NewsletterTopic) => {
+    setTopics((current) =>
+      current.includes(topic) ? current.filter((t) => t !== topic) : [...current, topic],
+    );
+  };
+
+  const handleSave = async () => {
+    setIsSaving(true);
+    setError("");
+    try {
+      const updated = await updateNewsletterPreferences(token, topics);
+      setSubscriber(updated);
+      setNotice("Preferences saved");
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Could not save preferences");
+    } finally {
+      setIsSaving(false);
+    }
+  };
+
+  const handleUnsubscribe = async () => {
+    setIsSaving(true);
+    setError("");
+    try {
+      await unsubscribeNewsletter(token);
+      setView("unsubscribed");
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Could not unsubscribe");
+    } finally {
+      setIsSaving(false);
+    }
+  };
+
+  return (
+    <div className="bg-bg-primary min-h-screen pt-24">
+      <section className="py-20 px-6 max-w-2xl mx-auto">
+        <motion.div initial={{ opacity: 0, y: 20 }} animate={{ opacity: 1, y: 0 }} className="mb-12">
+          <div className="flex items-center gap-3 text-accent-gold mb-6">
+            <Mail size={20} />
+            <span className="text-[10px] font-bold uppercase tracking-[0.3em]">The Dispatch</span>
+          </div>
+          <h1 className="text-4xl md:text-5xl font-heading font-bold text-white tracking-tighter">
+            NEWSLETTER{" "}
+            <span className="text-accent-gold italic-serif lowercase">preferences</span>
+          </h1>
+        </motion.div>
+
+        {view === "loading" && (
+          <div className="flex items-center gap-3 text-white/50">
+            <Loader2 size={16} className="animate-spin" /> Loading…
+          </div>
+        )}
+
+        {view === "error" && (
+          <div className="p-8 border-l border-red-500 bg-red-500/5">
+            <p className="text-white/70 mb-4">{error}</p>
+            <Link to="/" className="text-accent-gold text-sm font-bold uppercase tracking-widest">
+              Back to Spotlight Africa
+            </Link>
+          </div>
+        )}
+
+        {view === "unsubscribed" && (
+          <div className="p-8 border-l border-accent-gold bg-accent-gold/5">
+            <p className="text-white/70">
+              {subscriber?.email} has been removed from the list. You can sign up again at any time.
+            </p>
+          </div>
+        )}
+
+        {view === "preferences" && subscriber && (
+          <div className="space-y-8">
+            {notice && (
+              <div className="p-4 border-l border-accent-gold bg-accent-gold/5 text-white/70 text-sm">
+                {notice}
+              </div>
+            )}
+            <p className="text-white/50 text-sm">
+              Sending to <span className="text-white font-bold">{subscriber.email}</span>
+            </p>
+
+            <div className="space-y-3">
+              {(Object.keys(TOPIC_LABELS) as NewsletterTopic[]).map((topic) => (
+                <button
+                  key={topic}
+                  type="button"
+                  onClick={() => toggleTopic(topic)}
+                  className="w-full flex items-center justify-between p-5 bg-white/[0.02] border border-white/5 hover:border-accent-gold/40 transition-colors"
+                >
+                  <span className="text-white font-bold uppercase tracking-wider text-sm">
+                    {TOPIC_LABELS[topic]}
+                  </span>
+                  <span
+                    className={`w-6 h-6 flex items-center justify-center border ${
+                      topics.includes(topic)
+                        ? "bg-accent-gold border-accent-gold text-black"
+                        : "border-white/20"
+                    }`}
+                  >
+                    {topics.includes(topic) && <Check size={14} />}
+                  </span>
+                </button>
+              ))}
+            </div>
+
+            {error && <p className="text-red-400 text-sm">{error}</p>}
+
+            <div className="flex flex-wrap gap-4">
+              <button
+                onClick={handleSave}
+                disabled={isSaving || topics.length === 0}
+                className="px-8 py-4 bg-accent-gold text-black font-bold text-xs uppercase tracking-widest disabled:opacity-40"
+              >
+                Save preferences
+              </button>
+              <button
+                onClick={handleUnsubscribe}
+                disabled={isSaving}
+                className="px-8 py-4 border border-white/10 text-white/60 hover:text-white font-bold text-xs uppercase tracking-widest"
+              >
+                Unsubscribe from everything
+              </button>
+            </div>
+          </div>
+        )}
+      </section>
+    </div>
+  );
+};
+
+export default NewsletterPage;
@@ -0,0 +1,90 @@
+import { useEffect, useRef, useState } from "react";
+import { useNavigate, useParams, useSearchParams, Link } from "react-router-dom";
+import { motion } from "framer-motion";
+import { KeyRound, Loader2 } from "lucide-react";
+import { AuthProvider, TwoFactorChallenge, completeOAuth, isTwoFactorChallenge } from "../lib/api";
+import TwoFactorStep from "../components/TwoFactorStep";
+
+/**
+ * OAuthCallbackPage - where Google, LinkedIn and GitHub send the user back (?code=&state=).
+ * Signs them in, or finishes linking the account when it was started from Settings.
+ */
+const OAuthCallbackPage = () => {
+  const { provider } = useParams<{ provider: AuthProvider }>();
+  const [params] = useSearchParams();
+  const navigate = useNavigate();
+  const [error, setError] = useState("");
+  const [challenge, setChallenge] = useState<TwoFactorChallenge | null>(null);
+  const attempted = useRef(false);
+
+  const finishSignIn = () => {
+    navigate("/", { replace: true });
+    window.location.reload(); // Quick refresh to update Navbar state
+  };
+
+  useEffect(() => {
+    // The code and state are single-use, so don't spend them twice when effects re-run.
+    if (attempted.current) return;
+    attempted.current = true;
+    const finish = async () => {
+      if (params.get("error")) {
+        setError("Sign-in was cancelled");
+        return;
+      }
+      try {
+        const result = await completeOAuth(
+          provider as AuthProvider,
+          params.get("code") || "",
+          params.get("state") || "",
+        );
+        if (result.linked) {
+          navigate("/dashboard/settings", { replace: true });
+          return;
+        }
+        if (isTwoFactorChallenge(result)) {
+          setChallenge(result);
+          return;
+        }
+        finishSignIn();
+      } catch (err) {
+        setError(err instanceof Error ? err.message : "Could not sign in");
+      }
+    };
+    finish();
+  }, [params, provider, navigate]);
+
+  return (
+    <div className="bg-bg-primary min-h-screen pt-24">
+      <section className="py-20 px-6 max-w-2xl mx-auto">
+        <motion.div initial={{ opacity: 0, y: 20 }} animate={{ opacity: 1, y: 0 }} className="mb-12">
+          <div className="flex items-center gap-3 text-accent-gold mb-6">
+            <KeyRound size={20} />
+            <span className="text-[10px] font-bold uppercase tracking-[0.3em]">Account</span>
+          </div>
+          <h1 className="text-4xl md:text-5xl font-heading font-bold text-white tracking-tighter">
+            SIGNING <span className="text-accent-gold italic-serif lowercase">in</span>
+          </h1>
+        </motion.div>
+
+        {challenge && !error ? (
+          <div className="max-w-md">
+            <TwoFactorStep challenge={challenge} onSignedIn={finishSignIn} />
+          </div>
+        ) : !error ? (
+          <div className="flex items-center gap-3 text-white/50">
+            <Loader2 size={16} className="animate-spin" /> Finishing sign-in…
+          </div>
+        ) : (
+          <div className="p-8 border-l border-red-500 bg-red-500/5">
+            <p className="text-white/70 mb-4">{error}</p>
+            <Link to="/auth" className="text-accent-gold text-sm font-bold uppercase tracking-widest">
+              Back to sign in
+            </Link>
+          </div>
+        )}
+      </section>
+    </div>
+  );
+};
+
+export default OAuthCallbackPage;
@@ -10,7 +10,7 @@ import {
   Smartphone,
 } from "lucide-react";
 import { useNavigate } from "react-router-dom";
-import { createBusiness } from "../lib/api";
+import { createBusiness, selectBusiness } from "../lib/api";
 
 const RegisterBusinessPage = () => {
   const [step, setStep] = useState(1);
@@ -44,6 +44,8 @@ const RegisterBusinessPage = () => {
       // Update local storage with new token and role
       localStorage.setItem("token", response.token);
       localStorage.setItem("role", response.role);
+      // Open the new business in the dashboard
+      selectBusiness(response.business.id);
 
       // Go to success/payment step
       setStep(3);
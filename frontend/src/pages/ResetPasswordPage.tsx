@@ -0,0 +1,130 @@
+import { useState } from "react";
+import { useSearchParams, Link } from "react-router-dom";
+import { motion } from "framer-motion";
+import { Lock, Mail, Loader2 } from "lucide-react";
+import { forgotPassword, resetPassword } from "../lib/api";
+
+/**
+ * ResetPasswordPage - without a token, asks for the account email and sends a reset link;
+ * with ?token= from that email, sets a new password.
+ */
+const ResetPasswordPage = () => {
+  const [params] = useSearchParams();
+  const token = params.get("token") || "";
+  const [email, setEmail] = useState("");
+  const [password, setPassword] = useState("");
+  const [confirm, setConfirm] = useState("");
+  const [notice, setNotice] = useState("");
+  const [error, setError] = useState("");
+  const [done, setDone] = useState(false);
+  const [isSaving, setIsSaving] = useState(false);
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError("");
+    setNotice("");
+    if (token && password !== confirm) {
+      setError("Passwords don't match");
+      return;
+    }
+    setIsSaving(true);
+    try {
+      setNotice(token ? await resetPassword(token, password) : await forgotPassword(email));
+      setDone(true);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Something went wrong");
+    } finally {
+      setIsSaving(false);
+    }
+  };
+
+  const inputClass =
+    "w-full bg-slate-900/50 border border-white/5 px-12 py-4 text-white focus:outline-none focus:border-accent-gold/50 transition-all font-light";
+
+  return (
+    <div className="bg-bg-primary min-h-screen pt-24">
+      <section className="py-20 px-6 max-w-md mx-auto">
+        <motion.div initial={{ opacity: 0, y: 20 }} animate={{ opacity: 1, y: 0 }} className="mb-12">
+          <h1 className="text-4xl font-heading font-bold text-white tracking-tighter uppercase mb-2">
+            {token ? "Choose a new password" : "Reset your password"}
+          </h1>
+          <p className="text-white/50 font-serif italic leading-relaxed">
+            {token
+              ? "The link works once and expires after an hour."
+              : "Enter your account email and we'll send you a link."}
+          </p>
+        </motion.div>
+
+        {notice && (
+          <div className="p-4 mb-6 border-l border-accent-gold bg-accent-gold/5 text-white/70 text-sm">
+            {notice}
+          </div>
+        )}
+        {error && (
+          <div className="p-4 mb-6 border-l border-red-500 bg-red-500/5 text-red-400 text-sm">{error}</div>
+        )}
+
+        {done ? (
+          <Link to="/auth" className="text-accent-gold text-sm font-bold uppercase tracking-widest">
+            Back to sign in
+          </Link>
+        ) : (
+          <form onSubmit={handleSubmit} className="space-y-4">
+            {token ? (
+              <>
+                <div className="relative">
+                  <Lock className="absolute left-4 top-1/2 -translate-y-1/2 w-4 h-4 text-white/20" />
+                  <input
+                    type="password"
+                    value={password}
+                    onChange={(e) => setPassword(e.target.value)}
+                    required
+                    minLength={6}
+                    className={inputClass}
+                    placeholder="New password"
+                  />
+                </div>
+                <div className="relative">
+                  <Lock className="absolute left-4 top-1/2 -translate-y-1/2 w-4 h-4 text-white/20" />
+                  <input
+                    type="password"
+                    value={confirm}
+                    onChange={(e) => setConfirm(e.target.value)}
+                    required
+                    className={inputClass}
+                    placeholder="Confirm password"
+                  />
+                </div>
+              </>
+            ) : (
+              <div className="relative">
+                <Mail className="absolute left-4 top-1/2 -translate-y-1/2 w-4 h-4 text-white/20" />
+                <input
+                  type="email"
+                  value={email}
+                  onChange={(e) => setEmail(e.target.value)}
+                  required
+                  className={inputClass}
+                  placeholder="name@company.com"
+                />
+              </div>
+            )}
+
+            <button
+              type="submit"
+              disabled={isSaving}
+              className="w-full btn-primary py-4 flex items-center justify-center gap-3 disabled:opacity-40"
+            >
+              {isSaving && <Loader2 size={14} className="animate-spin" />}
+              <span className="text-[10px] uppercase font-bold tracking-[0.3em]">
+                {token ? "Set password" : "Send reset link"}
+              </span>
+            </button>
+          </form>
+        )}
+      </section>
+    </div>
+  );
+};
+
+export default ResetPasswordPage;
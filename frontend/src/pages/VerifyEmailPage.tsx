@@ -0,0 +1,100 @@
+import { useEffect, useRef, useState } from "react";
+import { useSearchParams, Link } from "react-router-dom";
+import { motion } from "framer-motion";
+import { MailCheck, Loader2 } from "lucide-react";
+import { verifyEmail, resendVerification } from "../lib/api";
+
+type View = "loading" | "verified" | "error";
+
+/**
+ * VerifyEmailPage - landing page for the link in the verification email (?token=).
+ * Logged-in users whose link has expired can ask for a new one.
+ */
+const VerifyEmailPage = () => {
+  const [params] = useSearchParams();
+  const [view, setView] = useState<View>("loading");
+  const [error, setError] = useState("");
+  const [notice, setNotice] = useState("");
+  const isLoggedIn = !!localStorage.getItem("token");
+  const attempted = useRef(false);
+
+  useEffect(() => {
+    // The token is single-use, so don't spend it twice when effects re-run.
+    if (attempted.current) return;
+    attempted.current = true;
+    const verify = async () => {
+      try {
+        await verifyEmail(params.get("token") || "");
+        setView("verified");
+      } catch (err) {
+        setError(err instanceof Error ? err.message : "Could not verify email");
+        setView("error");
+      }
+    };
+    verify();
+  }, [params]);
+
+  const handleResend = async () => {
+    try {
+      await resendVerification();
+      setNotice("We've sent you a new link.");
+    } catch (err) {
+      setNotice(err instanceof Error ? err.message : "Could not send a new link");
+    }
+  };
+
+  return (
+    <div className="bg-bg-primary min-h-screen pt-24">
+      <section className="py-20 px-6 max-w-2xl mx-auto">
+        <motion.div initial={{ opacity: 0, y: 20 }} animate={{ opacity: 1, y: 0 }} className="mb-12">
+          <div className="flex items-center gap-3 text-accent-gold mb-6">
+            <MailCheck size={20} />
+            <span className="text-[10px] font-bold uppercase tracking-[0.3em]">Account</span>
+          </div>
+          <h1 className="text-4xl md:text-5xl font-heading font-bold text-white tracking-tighter">
+            EMAIL <span className="text-accent-gold italic-serif lowercase">verification</span>
+          </h1>
+        </motion.div>
+
+        {view === "loading" && (
+          <div className="flex items-center gap-3 text-white/50">
+            <Loader2 size={16} className="animate-spin" /> Verifying…
+          </div>
+        )}
+
+        {view === "verified" && (
+          <div className="p-8 border-l border-accent-gold bg-accent-gold/5">
+            <p className="text-white/70 mb-4">Your email is verified. Thanks!</p>
+            <Link
+              to={isLoggedIn ? "/" : "/auth"}
+              className="text-accent-gold text-sm font-bold uppercase tracking-widest"
+            >
+              {isLoggedIn ? "Continue" : "Sign in"}
+            </Link>
+          </div>
+        )}
+
+        {view === "error" && (
+          <div className="p-8 border-l border-red-500 bg-red-500/5">
+            <p className="text-white/70 mb-4">{error}</p>
+            {notice && <p className="text-white/50 text-sm mb-4">{notice}</p>}
+            {isLoggedIn ? (
+              <button
+                onClick={handleResend}
+                className="text-accent-gold text-sm font-bold uppercase tracking-widest"
+              >
+                Send a new link
+              </button>
+            ) : (
+              <Link to="/auth" className="text-accent-gold text-sm font-bold uppercase tracking-widest">
+                Sign in to get a new link
+              </Link>
+            )}
+          </div>
+        )}
+      </section>
+    </div>
+  );
+};
+
+export default VerifyEmailPage;
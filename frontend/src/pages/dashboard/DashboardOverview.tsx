@@ -21,7 +21,7 @@ import {
   User,
 } from "lucide-react";
 import { Link } from "react-router-dom";
-import { fetchDashboardMe, DashboardData } from "../../lib/api";
+import { fetchDashboardMe, fetchPortfolio, DashboardData, Portfolio } from "../../lib/api";
 import { format } from "date-fns";
 import ScheduleMeetingModal from "../../components/dashboard/ScheduleMeetingModal";
 
@@ -130,6 +130,7 @@ const PipelineItem = ({
 
 const DashboardOverview = () => {
   const [data, setData] = useState<DashboardData | null>(null);
+  const [portfolio, setPortfolio] = useState<Portfolio | null>(null);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
   const [isMeetingModalOpen, setIsMeetingModalOpen] = useState(false);
@@ -160,6 +161,12 @@ const DashboardOverview = () => {
       try {
         const dashboardData = await fetchDashboardMe();
         setData(dashboardData);
+        // Totals across businesses, for users who run more than one
+        if ((dashboardData.businesses?.length || 0) > 1) {
+          fetchPortfolio()
+            .then((res) => setPortfolio(res.portfolio))
+            .catch((err) => console.error("Portfolio fetch failed", err));
+        }
       } catch (err: any) {
         setError(err.message);
       } finally {
@@ -400,7 +407,7 @@ const DashboardOverview = () => {
             "We suffer more often in imagination than in reality." — Seneca
           </p>
           <div className="mt-2 text-[10px] font-bold text-white/20 uppercase tracking-[0.2em]">
-            Owner @ {data.business?.name}
+            {data.member_role || "Owner"} @ {data.business?.name}
           </div>
         </div>
         <div className="flex items-center gap-3">
@@ -426,8 +433,8 @@ const DashboardOverview = () => {
         <StatCard
           label="Health Score"
           value={`${data.stats.health_score}`}
-          change="+4.2%"
-          trend="up"
+          change={`${(data.stats.health_score_delta_wow || 0) >= 0 ? "+" : ""}${data.stats.health_score_delta_wow || 0} wk`}
+          trend={data.stats.health_trend === "down" ? "down" : "up"}
           icon={<Heart size={20} />}
         />
         <StatCard
@@ -439,7 +446,7 @@ const DashboardOverview = () => {
         />
         <StatCard
           label="Active Inquiries"
-          value={data.pipeline?.length || 0}
+          value={data.stats.active_inquiries ?? (data.pipeline?.length || 0)}
           change={`+${data.pipeline?.filter((p) => new Date(p.created_at).getTime() > Date.now() - 86400000).length || 0}`}
           trend="up"
           icon={<MessageSquare size={20} />}
@@ -453,6 +460,28 @@ const DashboardOverview = () => {
         />
       </div>
 
+      {/* Portfolio totals across all the user's businesses */}
+      {portfolio && (
+        <div className="glass p-6 flex flex-wrap gap-x-10 gap-y-4">
+          <div className="text-[10px] font-bold text-white/40 uppercase tracking-[0.2em] w-full">
+            Portfolio · {portfolio.businesses} businesses
+          </div>
+          {[
+            { label: "Avg. Health", value: Math.round(portfolio.average_health) },
+            { label: "Views", value: portfolio.total_views.toLocaleString() },
+            { label: "Likes", value: portfolio.total_likes.toLocaleString() },
+            { label: "Inquiries", value: portfolio.total_inquiries.toLocaleString() },
+            { label: "Conversions", value: portfolio.total_conversions.toLocaleString() },
+            { label: "Upcoming Meetings", value: portfolio.upcoming_meetings },
+          ].map((item) => (
+            <div key={item.label}>
+              <p className="text-2xl font-heading font-bold text-white">{item.value}</p>
+              <p className="text-[10px] text-white/40 uppercase tracking-widest">{item.label}</p>
+            </div>
+          ))}
+        </div>
+      )}
+
       <div className="grid grid-cols-1 lg:grid-cols-3 gap-8">
         {/* Pipeline Column */}
         <div className="lg:col-span-2 space-y-6">
@@ -533,7 +562,9 @@ const DashboardOverview = () => {
                 ))
             ) : (
               <div className="p-6 text-center text-white/20 italic text-xs border border-white/5 bg-white/2">
-                No upcoming meetings scheduled.
+                {data.stats.meetings
+                  ? `${data.stats.meetings} meeting${data.stats.meetings === 1 ? "" : "s"} scheduled.`
+                  : "No upcoming meetings scheduled."}
               </div>
             )}
           </div>
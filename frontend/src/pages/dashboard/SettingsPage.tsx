@@ -1,3 +1,5 @@
+import { useEffect, useState } from "react";
+import { useNavigate } from "react-router-dom";
 import { motion } from "framer-motion";
 import {
   Shield,
@@ -6,9 +8,132 @@ import {
   CreditCard,
   ChevronRight,
   LogOut,
+  Monitor,
+  Link2,
+  KeyRound,
 } from "lucide-react";
+import {
+  fetchSessions,
+  revokeSession,
+  logoutEverywhere,
+  UserSession,
+  AuthProvider,
+  LinkedIdentity,
+  fetchIdentities,
+  linkIdentity,
+  unlinkIdentity,
+  TwoFactorSetup,
+  TwoFactorStatus,
+  fetchTwoFactorStatus,
+  beginTwoFactorSetup,
+  enableTwoFactor,
+  disableTwoFactor,
+  regenerateRecoveryCodes,
+} from "../../lib/api";
+
+const providerLabels: Record<AuthProvider, string> = {
+  google: "Google",
+  linkedin: "LinkedIn",
+  github: "GitHub",
+};
 
 const SettingsPage = () => {
+  const navigate = useNavigate();
+  const [sessions, setSessions] = useState<UserSession[]>([]);
+  const [identities, setIdentities] = useState<LinkedIdentity[]>([]);
+  const [providers, setProviders] = useState<AuthProvider[]>([]);
+  const [identityError, setIdentityError] = useState("");
+  const [twoFactor, setTwoFactor] = useState<TwoFactorStatus | null>(null);
+  const [twoFactorSetup, setTwoFactorSetup] = useState<TwoFactorSetup | null>(null);
+  const [twoFactorCode, setTwoFactorCode] = useState("");
+  const [recoveryCodes, setRecoveryCodes] = useState<string[]>([]);
+  const [twoFactorError, setTwoFactorError] = useState("");
+
+  useEffect(() => {
+    fetchSessions()
+      .then(setSessions)
+      .catch((err) => console.error("Failed to load sessions", err));
+    fetchIdentities()
+      .then((data) => {
+        setIdentities(data.items);
+        setProviders(data.providers);
+      })
+      .catch((err) => console.error("Failed to load linked accounts", err));
+    fetchTwoFactorStatus()
+      .then(setTwoFactor)
+      .catch((err) => console.error("Failed to load two-factor status", err));
+  }, []);
+
+  // Runs a two-factor change that needs the code typed into the field.
+  const withTwoFactorCode = async (action: (code: string) => Promise<void>) => {
+    setTwoFactorError("");
+    try {
+      await action(twoFactorCode);
+      setTwoFactorCode("");
+      setTwoFactor(await fetchTwoFactorStatus());
+    } catch (err) {
+      setTwoFactorError(err instanceof Error ? err.message : "Could not verify code");
+    }
+  };
+
+  const handleBeginTwoFactor = async () => {
+    setTwoFactorError("");
+    setRecoveryCodes([]);
+    try {
+      setTwoFactorSetup(await beginTwoFactorSetup());
+    } catch (err) {
+      setTwoFactorError(err instanceof Error ? err.message : "Could not start setup");
+    }
+  };
+
+  const handleEnableTwoFactor = () =>
+    withTwoFactorCode(async (code) => {
+      setRecoveryCodes(await enableTwoFactor(code));
+      setTwoFactorSetup(null);
+    });
+
+  const handleRegenerateCodes = () =>
+    withTwoFactorCode(async (code) => setRecoveryCodes(await regenerateRecoveryCodes(code)));
+
+  const handleDisableTwoFactor = () =>
+    withTwoFactorCode(async (code) => {
+      await disableTwoFactor(code);
+      setRecoveryCodes([]);
+    });
+
+  const handleLink = async (provider: AuthProvider) => {
+    setIdentityError("");
+    try {
+      await linkIdentity(provider);
+    } catch (err) {
+      setIdentityError(err instanceof Error ? err.message : "Could not link account");
+    }
+  };
+
+  const handleUnlink = async (provider: AuthProvider) => {
+    setIdentityError("");
+    try {
+      await unlinkIdentity(provider);
+      setIdentities((current) => current.filter((i) => i.provider !== provider));
+    } catch (err) {
+      setIdentityError(err instanceof Error ? err.message : "Could not unlink account");
+    }
+  };
+
+  const handleRevoke = async (id: string) => {
+    try {
+      await revokeSession(id);
+      setSessions((current) => current.filter((s) => s.id !== id));
+    } catch (err) {
+      console.error("Failed to revoke session", err);
+    }
+  };
+
+  const handleLogoutEverywhere = async () => {
+    await logoutEverywhere().catch(() => undefined);
+    navigate("/auth");
+  };
+
   const sections = [
     {
       icon: User,
@@ -76,6 +201,176 @@ const SettingsPage = () => {
         ))}
       </div>
 
+      {twoFactor && (
+        <div className="space-y-4">
+          <h2 className="text-[10px] font-bold text-white/40 uppercase tracking-[0.2em]">
+            Two-Factor Authentication
+          </h2>
+          <div className="p-6 bg-white/[0.02] border border-white/5 space-y-4">
+            <div className="flex items-center gap-6">
+              <KeyRound size={18} className="text-white/30 shrink-0" />
+              <div className="flex-1 min-w-0">
+                <p className="text-xs text-white">Authenticator app</p>
+                <p className="text-[10px] text-white/40 uppercase tracking-widest mt-1">
+                  {twoFactor.enabled
+                    ? `On · ${twoFactor.recovery_codes_left ?? 0} recovery codes left`
+                    : twoFactor.required
+                      ? "Required for your role"
+                      : "Off"}
+                </p>
+              </div>
+              {!twoFactor.enabled && !twoFactorSetup && (
+                <button
+                  onClick={handleBeginTwoFactor}
+                  className="text-[10px] font-bold text-white/40 hover:text-accent-gold uppercase tracking-widest transition-colors"
+                >
+                  Set up
+                </button>
+              )}
+            </div>
+
+            {twoFactorSetup && (
+              <div className="space-y-2">
+                <p className="text-xs text-white/50">
+                  Add this key to your authenticator app, or open the link on your phone, then enter
+                  the code it shows.
+                </p>
+                <p className="font-mono text-sm text-accent-gold break-all">{twoFactorSetup.secret}</p>
+                <a
+                  href={twoFactorSetup.uri}
+                  className="text-[10px] font-bold uppercase tracking-widest text-white/40 hover:text-accent-gold transition-colors"
+                >
+                  Open in authenticator app
+                </a>
+              </div>
+            )}
+
+            {(twoFactor.enabled || twoFactorSetup) && (
+              <div className="flex flex-wrap items-center gap-4">
+                <input
+                  value={twoFactorCode}
+                  onChange={(e) => setTwoFactorCode(e.target.value)}
+                  autoComplete="one-time-code"
+                  placeholder={twoFactorSetup ? "Code from your app" : "Code or recovery code"}
+                  className="bg-slate-900/50 border border-white/5 px-4 py-2 text-sm text-white focus:outline-none focus:border-accent-gold/50"
+                />
+                {twoFactorSetup ? (
+                  <button
+                    onClick={handleEnableTwoFactor}
+                    className="text-[10px] font-bold text-accent-gold uppercase tracking-widest"
+                  >
+                    Turn on
+                  </button>
+                ) : (
+                  <>
+                    <button
+                      onClick={handleRegenerateCodes}
+                      className="text-[10px] font-bold text-white/40 hover:text-accent-gold uppercase tracking-widest transition-colors"
+                    >
+                      New recovery codes
+                    </button>
+                    {!twoFactor.required && (
+                      <button
+                        onClick={handleDisableTwoFactor}
+                        className="text-[10px] font-bold text-white/40 hover:text-primary uppercase tracking-widest transition-colors"
+                      >
+                        Turn off
+                      </button>
+                    )}
+                  </>
+                )}
+              </div>
+            )}
+
+            {twoFactorError && <p className="text-xs text-red-400">{twoFactorError}</p>}
+
+            {recoveryCodes.length > 0 && (
+              <div className="space-y-2">
+                <p className="text-xs text-white/50">
+                  Save these recovery codes somewhere safe. They won't be shown again.
+                </p>
+                <div className="grid grid-cols-2 md:grid-cols-5 gap-2 font-mono text-xs text-accent-gold">
+                  {recoveryCodes.map((c) => (
+                    <span key={c} className="bg-slate-900/50 border border-white/5 px-2 py-1 text-center">
+                      {c}
+                    </span>
+                  ))}
+                </div>
+              </div>
+            )}
+          </div>
+        </div>
+      )}
+
+      {providers.length > 0 && (
+        <div className="space-y-4">
+          <h2 className="text-[10px] font-bold text-white/40 uppercase tracking-[0.2em]">
+            Linked Accounts
+          </h2>
+          {identityError && <p className="text-xs text-red-400">{identityError}</p>}
+          {providers.map((provider) => {
+            const identity = identities.find((i) => i.provider === provider);
+            return (
+              <div
+                key={provider}
+                className="flex items-center gap-6 p-6 bg-white/[0.02] border border-white/5"
+              >
+                <Link2 size={18} className="text-white/30 shrink-0" />
+                <div className="flex-1 min-w-0">
+                  <p className="text-xs text-white">{providerLabels[provider]}</p>
+                  <p className="text-[10px] text-white/40 uppercase tracking-widest mt-1 truncate">
+                    {identity ? identity.email || "Connected" : "Not connected"}
+                  </p>
+                </div>
+                <button
+                  onClick={() => (identity ? handleUnlink(provider) : handleLink(provider))}
+                  className="text-[10px] font-bold text-white/40 hover:text-accent-gold uppercase tracking-widest transition-colors"
+                >
+                  {identity ? "Unlink" : "Link"}
+                </button>
+              </div>
+            );
+          })}
+        </div>
+      )}
+
+      {sessions.length > 0 && (
+        <div className="space-y-4">
+          <h2 className="text-[10px] font-bold text-white/40 uppercase tracking-[0.2em]">
+            Active Sessions
+          </h2>
+          {sessions.map((session) => (
+            <div
+              key={session.id}
+              className="flex items-center gap-6 p-6 bg-white/[0.02] border border-white/5"
+            >
+              <Monitor size={18} className="text-white/30 shrink-0" />
+              <div className="flex-1 min-w-0">
+                <p className="text-xs text-white truncate">
+                  {session.user_agent || "Unknown device"}
+                </p>
+                <p className="text-[10px] text-white/40 uppercase tracking-widest mt-1">
+                  {session.ip} · Last active{" "}
+                  {new Date(session.last_used_at).toLocaleString()}
+                </p>
+              </div>
+              {session.current ? (
+                <span className="text-[10px] font-bold text-accent-gold uppercase tracking-widest">
+                  This device
+                </span>
+              ) : (
+                <button
+                  onClick={() => handleRevoke(session.id)}
+                  className="text-[10px] font-bold text-white/40 hover:text-primary uppercase tracking-widest transition-colors"
+                >
+                  Revoke
+                </button>
+              )}
+            </div>
+          ))}
+        </div>
+      )}
+
       <div className="pt-10 border-t border-white/5 flex justify-between items-center">
         <div className="flex items-center gap-4 text-white/30 text-[10px] font-bold uppercase tracking-[0.2em]">
           <span>v1.0.4-Lagos</span>
@@ -83,7 +378,10 @@ const SettingsPage = () => {
           <span className="text-accent-gold/50">System Stable</span>
         </div>
 
-        <button className="flex items-center gap-2 text-primary hover:brightness-125 transition-all text-[10px] font-bold uppercase tracking-widest">
+        <button
+          onClick={handleLogoutEverywhere}
+          className="flex items-center gap-2 text-primary hover:brightness-125 transition-all text-[10px] font-bold uppercase tracking-widest"
+        >
           <LogOut size={14} />
           Sign Out of All Devices
         </button>
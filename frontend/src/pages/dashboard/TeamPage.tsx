@@ -0,0 +1,206 @@
+import { useEffect, useState } from "react";
+import { Loader2, UserPlus, Crown, X } from "lucide-react";
+import {
+  fetchDashboardMe,
+  fetchBusinessMembers,
+  fetchBusinessInvitations,
+  inviteBusinessMember,
+  updateBusinessMember,
+  removeBusinessMember,
+  revokeBusinessInvitation,
+  transferBusinessOwnership,
+  BusinessMember,
+  BusinessInvitation,
+  MemberRole,
+} from "../../lib/api";
+
+type AssignableRole = Exclude<MemberRole, "owner">;
+const assignableRoles: AssignableRole[] = ["manager", "editor", "viewer"];
+
+/**
+ * TeamPage - who helps run the business. Owners and managers invite people and set
+ * their roles; only the owner can hand the business over.
+ */
+const TeamPage = () => {
+  const [businessId, setBusinessId] = useState("");
+  const [myRole, setMyRole] = useState<MemberRole | "">("");
+  const [members, setMembers] = useState<BusinessMember[]>([]);
+  const [invitations, setInvitations] = useState<BusinessInvitation[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [email, setEmail] = useState("");
+  const [role, setRole] = useState<AssignableRole>("editor");
+  const [error, setError] = useState("");
+
+  const canManage = myRole === "owner" || myRole === "manager";
+
+  const load = async (id: string) => {
+    const team = await fetchBusinessMembers(id);
+    setMembers(team.items);
+    setMyRole(team.role);
+    if (team.role === "owner" || team.role === "manager") {
+      setInvitations(await fetchBusinessInvitations(id));
+    }
+  };
+
+  useEffect(() => {
+    fetchDashboardMe()
+      .then(async (data) => {
+        if (!data.business) return;
+        setBusinessId(data.business.id);
+        await load(data.business.id);
+      })
+      .catch((err) => console.error("Team load failed", err))
+      .finally(() => setLoading(false));
+  }, []);
+
+  // Runs a change, shows its error if any, and reloads the team.
+  const run = async (action: () => Promise<unknown>) => {
+    setError("");
+    try {
+      await action();
+      await load(businessId);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Something went wrong");
+    }
+  };
+
+  const handleInvite = (e: React.FormEvent) => {
+    e.preventDefault();
+    run(async () => {
+      await inviteBusinessMember(businessId, email, role);
+      setEmail("");
+    });
+  };
+
+  const handleTransfer = (member: BusinessMember) => {
+    if (!window.confirm(`Make ${member.user.name || member.user.email} the owner? You'll stay on as a manager.`)) return;
+    run(() => transferBusinessOwnership(businessId, member.user_id));
+  };
+
+  if (loading) {
+    return (
+      <div className="min-h-[400px] flex items-center justify-center">
+        <Loader2 className="w-8 h-8 animate-spin text-accent-gold" />
+      </div>
+    );
+  }
+
+  if (!businessId) return null;
+
+  return (
+    <div className="space-y-10 animate-in fade-in slide-in-from-bottom-4 duration-700">
+      <div>
+        <h1 className="text-3xl font-heading font-bold text-white mb-2 uppercase tracking-tight">Team</h1>
+        <p className="text-white/40 text-sm">The people who run this business with you.</p>
+      </div>
+
+      {error && <p className="text-sm text-primary">{error}</p>}
+
+      <div className="space-y-4">
+        {members.map((member) => (
+          <div key={member.id} className="flex items-center gap-6 p-6 bg-white/[0.02] border border-white/5">
+            <div className="flex-1 min-w-0">
+              <p className="text-sm text-white truncate">{member.user.name || member.user.email}</p>
+              <p className="text-[10px] text-white/40 uppercase tracking-widest mt-1">{member.user.email}</p>
+            </div>
+
+            {member.role === "owner" || !canManage ? (
+              <span className="flex items-center gap-2 text-[10px] font-bold text-accent-gold uppercase tracking-widest">
+                {member.role === "owner" && <Crown size={12} />}
+                {member.role}
+              </span>
+            ) : (
+              <select
+                value={member.role}
+                onChange={(e) =>
+                  run(() => updateBusinessMember(businessId, member.user_id, e.target.value as AssignableRole))
+                }
+                className="bg-transparent border border-white/10 text-xs text-white px-3 py-2 uppercase tracking-widest"
+              >
+                {assignableRoles.map((r) => (
+                  <option key={r} value={r} className="bg-bg-primary">
+                    {r}
+                  </option>
+                ))}
+              </select>
+            )}
+
+            {myRole === "owner" && member.role !== "owner" && (
+              <button
+                onClick={() => handleTransfer(member)}
+                className="text-[10px] font-bold text-white/40 hover:text-accent-gold uppercase tracking-widest transition-colors"
+              >
+                Make owner
+              </button>
+            )}
+            {canManage && member.role !== "owner" && (
+              <button
+                onClick={() => run(() => removeBusinessMember(businessId, member.user_id))}
+                className="text-[10px] font-bold text-white/40 hover:text-primary uppercase tracking-widest transition-colors"
+              >
+                Remove
+              </button>
+            )}
+          </div>
+        ))}
+      </div>
+
+      {canManage && (
+        <>
+          <form onSubmit={handleInvite} className="flex flex-col md:flex-row gap-4 p-6 bg-white/[0.02] border border-white/5">
+            <input
+              type="email"
+              required
+              value={email}
+              onChange={(e) => setEmail(e.target.value)}
+              placeholder="colleague@example.com"
+              className="flex-1 bg-transparent border border-white/10 text-sm text-white px-4 py-3 focus:border-accent-gold outline-none"
+            />
+            <select
+              value={role}
+              onChange={(e) => setRole(e.target.value as AssignableRole)}
+              className="bg-transparent border border-white/10 text-xs text-white px-3 py-3 uppercase tracking-widest"
+            >
+              {assignableRoles.map((r) => (
+                <option key={r} value={r} className="bg-bg-primary">
+                  {r}
+                </option>
+              ))}
+            </select>
+            <button
+              type="submit"
+              className="flex items-center justify-center gap-2 bg-accent-gold text-bg-primary px-6 py-3 text-[10px] font-bold uppercase tracking-widest"
+            >
+              <UserPlus size={14} /> Invite
+            </button>
+          </form>
+
+          {invitations.length > 0 && (
+            <div className="space-y-4">
+              <h2 className="text-[10px] font-bold text-white/40 uppercase tracking-[0.2em]">Pending Invitations</h2>
+              {invitations.map((invitation) => (
+                <div key={invitation.id} className="flex items-center gap-6 p-6 bg-white/[0.02] border border-white/5">
+                  <div className="flex-1 min-w-0">
+                    <p className="text-xs text-white truncate">{invitation.email}</p>
+                    <p className="text-[10px] text-white/40 uppercase tracking-widest mt-1">
+                      {invitation.role} · Expires {new Date(invitation.expires_at).toLocaleDateString()}
+                    </p>
+                  </div>
+                  <button
+                    onClick={() => run(() => revokeBusinessInvitation(businessId, invitation.id))}
+                    className="text-white/40 hover:text-primary transition-colors"
+                    aria-label="Revoke invitation"
+                  >
+                    <X size={16} />
+                  </button>
+                </div>
+              ))}
+            </div>
+          )}
+        </>
+      )}
+    </div>
+  );
+};
+
+export default TeamPage;